@@ -0,0 +1,143 @@
+// Package correlation computes return correlations across stocks and clusters stocks that move together.
+package correlation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Aligned holds daily log returns of several stocks on a common set of dates.
+// A return is NaN (a gap) if the stock has no close on either the date or the previous date.
+type Aligned struct {
+	symbols []string
+	dates   []time.Time // Date at the end of each return.
+	returns [][]float64 // Returns by symbol, then date.
+}
+
+// Align lines up the daily prices of each stock on the dates traded by any of them,
+// keeping the most recent lookback returns (all returns if lookback is 0).
+func Align(prices map[string][]stock.DailyPrice, lookback int) (a Aligned, err error) {
+	if len(prices) < 2 {
+		return a, fmt.Errorf("at least 2 stocks are needed to correlate, found %d", len(prices))
+	}
+
+	if lookback < 0 {
+		return a, fmt.Errorf("invalid lookback: %d", lookback)
+	}
+
+	// Union of dates traded by any stock.
+	closes := make(map[string]map[time.Time]float64, len(prices))
+	dateSet := make(map[time.Time]bool)
+	for symbol, dps := range prices {
+		a.symbols = append(a.symbols, symbol)
+		closes[symbol] = make(map[time.Time]float64, len(dps))
+		for _, dp := range dps {
+			_, c := dp.OpenClose()
+			closes[symbol][dp.Date()] = c
+			dateSet[dp.Date()] = true
+		}
+	}
+	sort.Strings(a.symbols)
+
+	dates := make([]time.Time, 0, len(dateSet))
+	for date := range dateSet {
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	if len(dates) < 2 {
+		return a, fmt.Errorf("not enough dates to compute returns: %d", len(dates))
+	}
+
+	if lookback > 0 && len(dates) > lookback+1 {
+		dates = dates[len(dates)-lookback-1:]
+	}
+	a.dates = dates[1:]
+
+	for _, symbol := range a.symbols {
+		returns := make([]float64, 0, len(a.dates))
+		for i := 1; i < len(dates); i++ {
+			prev, okPrev := closes[symbol][dates[i-1]]
+			c, ok := closes[symbol][dates[i]]
+			if okPrev && ok {
+				returns = append(returns, math.Log(c/prev))
+			} else {
+				returns = append(returns, math.NaN())
+			}
+		}
+		a.returns = append(a.returns, returns)
+	}
+
+	return
+}
+
+func (a Aligned) Symbols() []string {
+	return a.symbols
+}
+
+func (a Aligned) Dates() []time.Time {
+	return a.dates
+}
+
+// Gaps returns the number of missing returns for each symbol.
+func (a Aligned) Gaps() map[string]int {
+	gaps := make(map[string]int, len(a.symbols))
+	for i, symbol := range a.symbols {
+		for _, r := range a.returns[i] {
+			if math.IsNaN(r) {
+				gaps[symbol]++
+			}
+		}
+	}
+
+	return gaps
+}
+
+func (a Aligned) index(symbol string) (int, error) {
+	for i, s := range a.symbols {
+		if s == symbol {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("symbol not found: %s", symbol)
+}
+
+// pearson returns the correlation of the pairs where both values are present and the number of pairs used.
+// If mask is given, only positions where mask is true are used.
+func pearson(xs, ys []float64, mask []bool) (float64, int) {
+	n := 0
+	var sumX, sumY float64
+	for i := range xs {
+		if math.IsNaN(xs[i]) || math.IsNaN(ys[i]) || (mask != nil && !mask[i]) {
+			continue
+		}
+		sumX += xs[i]
+		sumY += ys[i]
+		n++
+	}
+	if n < 2 {
+		return math.NaN(), n
+	}
+
+	meanX, meanY := sumX/float64(n), sumY/float64(n)
+	var cov, varX, varY float64
+	for i := range xs {
+		if math.IsNaN(xs[i]) || math.IsNaN(ys[i]) || (mask != nil && !mask[i]) {
+			continue
+		}
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return math.NaN(), n
+	}
+
+	return cov / math.Sqrt(varX*varY), n
+}
@@ -0,0 +1,105 @@
+package correlation
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Cluster is a node of a hierarchical clustering of stocks by correlation distance.
+// Leaves hold a single symbol; other nodes join two clusters at the distance between them.
+type Cluster struct {
+	Symbols  []string
+	Distance float64 // Average distance between the two joined clusters, 0 for a leaf.
+	Left     *Cluster
+	Right    *Cluster
+}
+
+// Distance converts a correlation to a distance from 0 (perfectly correlated) to 2 (perfectly opposed).
+// Unknown correlations are treated as uncorrelated, and rounding beyond -1 or 1 is clamped.
+func Distance(rho float64) float64 {
+	if math.IsNaN(rho) {
+		rho = 0
+	}
+	rho = math.Max(-1, math.Min(1, rho))
+
+	return math.Sqrt(2 * (1 - rho))
+}
+
+// Cluster builds an average-linkage hierarchical clustering of the matrix's stocks.
+func (m Matrix) Cluster() (*Cluster, error) {
+	n := len(m.symbols)
+	if n == 0 {
+		return nil, fmt.Errorf("correlation matrix is empty")
+	}
+
+	clusters := make([]*Cluster, 0, n)
+	members := make([][]int, 0, n)
+	for i, s := range m.symbols {
+		clusters = append(clusters, &Cluster{Symbols: []string{s}})
+		members = append(members, []int{i})
+	}
+
+	avgDistance := func(a, b []int) float64 {
+		sum := 0.0
+		for _, i := range a {
+			for _, j := range b {
+				sum += Distance(m.values[i][j])
+			}
+		}
+		return sum / float64(len(a)*len(b))
+	}
+
+	for len(clusters) > 1 {
+		bestI, bestJ, best := 0, 1, math.Inf(1)
+		for i := range clusters {
+			for j := i + 1; j < len(clusters); j++ {
+				if d := avgDistance(members[i], members[j]); d < best {
+					bestI, bestJ, best = i, j, d
+				}
+			}
+		}
+
+		joined := &Cluster{
+			Symbols:  append(append([]string{}, clusters[bestI].Symbols...), clusters[bestJ].Symbols...),
+			Distance: best,
+			Left:     clusters[bestI],
+			Right:    clusters[bestJ],
+		}
+		joinedMembers := append(append([]int{}, members[bestI]...), members[bestJ]...)
+
+		// Replace i with the joined cluster and remove j (j > i).
+		clusters[bestI], members[bestI] = joined, joinedMembers
+		clusters = append(clusters[:bestJ], clusters[bestJ+1:]...)
+		members = append(members[:bestJ], members[bestJ+1:]...)
+	}
+
+	return clusters[0], nil
+}
+
+// Cut splits the clustering into groups joined at no more than the maximum distance.
+func (c *Cluster) Cut(maxDistance float64) [][]string {
+	if c.Left == nil || c.Distance <= maxDistance {
+		return [][]string{c.Symbols}
+	}
+
+	return append(c.Left.Cut(maxDistance), c.Right.Cut(maxDistance)...)
+}
+
+// String draws the clustering as an indented tree.
+func (c *Cluster) String() string {
+	return c.format(0)
+}
+
+func (c *Cluster) format(depth int) string {
+	indent := strings.Repeat("  ", depth)
+	if c.Left == nil {
+		return fmt.Sprintf("%s%s\n", indent, c.Symbols[0])
+	}
+
+	str := fmt.Sprintf("%s+ %.2f\n", indent, c.Distance)
+	str += c.Left.format(depth + 1)
+	str += c.Right.format(depth + 1)
+
+	return str
+}
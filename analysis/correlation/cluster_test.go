@@ -0,0 +1,56 @@
+package correlation
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+func TestCluster(t *testing.T) {
+	// MIX is half alternating and half orthogonal, correlated 1/sqrt(2) with SAME and TWICE and -1/sqrt(2) with OPP.
+	a, err := Align(map[string][]stock.DailyPrice{
+		"SAME":  testPrices(t, 100, alternating),
+		"TWICE": testPrices(t, 50, scaled(alternating, 2, nil)),
+		"OPP":   testPrices(t, 80, scaled(alternating, -1, nil)),
+		"MIX":   testPrices(t, 30, scaled(alternating, 1, orthogonal)),
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := a.Correlation(PairwiseComplete, 2).Cluster()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// SAME and TWICE join first, then MIX, then OPP. Symbols are in alphabetical order, with a joined cluster
+	// taking the place of its first.
+	near, far := Distance(1/math.Sqrt2), Distance(-1/math.Sqrt2)
+	if got := root.Left.Right; got.Distance != 0 || !reflect.DeepEqual(got.Symbols, []string{"SAME", "TWICE"}) {
+		t.Errorf("first cluster %v at %.4f, want SAME and TWICE at 0", got.Symbols, got.Distance)
+	}
+	if got := root.Left; math.Abs(got.Distance-near) > 1e-9 || !reflect.DeepEqual(got.Symbols, []string{"MIX", "SAME", "TWICE"}) {
+		t.Errorf("second cluster %v at %.4f, want MIX, SAME and TWICE at %.4f", got.Symbols, got.Distance, near)
+	}
+	if want := (2 + 2 + far) / 3; math.Abs(root.Distance-want) > 1e-9 || !reflect.DeepEqual(root.Right.Symbols, []string{"OPP"}) {
+		t.Errorf("root joins %v at %.4f, want OPP at %.4f", root.Right.Symbols, root.Distance, want)
+	}
+
+	groups := root.Cut(1)
+	if want := [][]string{{"MIX", "SAME", "TWICE"}, {"OPP"}}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %v, want %v", groups, want)
+	}
+	if groups = root.Cut(2); len(groups) != 1 {
+		t.Errorf("groups = %v, want one group", groups)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	for _, tc := range []struct{ rho, want float64 }{{1, 0}, {0, math.Sqrt2}, {-1, 2}, {math.NaN(), math.Sqrt2}, {1 + 1e-15, 0}, {-1 - 1e-15, 2}} {
+		if got := Distance(tc.rho); math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("distance of %g = %g, want %g", tc.rho, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package correlation
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// PairwiseComplete correlates each pair on the dates both stocks have returns.
+	PairwiseComplete GapPolicy = iota
+	// CompleteCases correlates every pair on only the dates all stocks have returns.
+	CompleteCases
+)
+
+// GapPolicy selects how missing returns are handled.
+type GapPolicy int
+
+// Matrix holds the pairwise return correlations of several stocks.
+// Correlations with fewer than the minimum overlapping returns are NaN.
+type Matrix struct {
+	symbols []string
+	values  [][]float64
+	counts  [][]int // Number of returns used for each pair.
+}
+
+// Correlation computes the correlation of each pair of stocks.
+func (a Aligned) Correlation(policy GapPolicy, minOverlap int) Matrix {
+	var mask []bool
+	if policy == CompleteCases {
+		mask = make([]bool, len(a.dates))
+		for d := range a.dates {
+			mask[d] = true
+			for s := range a.symbols {
+				if math.IsNaN(a.returns[s][d]) {
+					mask[d] = false
+				}
+			}
+		}
+	}
+
+	m := Matrix{symbols: a.symbols}
+	m.values = make([][]float64, len(a.symbols))
+	m.counts = make([][]int, len(a.symbols))
+	for i := range a.symbols {
+		m.values[i] = make([]float64, len(a.symbols))
+		m.counts[i] = make([]int, len(a.symbols))
+	}
+
+	for i := range a.symbols {
+		for j := i; j < len(a.symbols); j++ {
+			rho, n := pearson(a.returns[i], a.returns[j], mask)
+			if n < minOverlap {
+				rho = math.NaN()
+			}
+			if i == j && !math.IsNaN(rho) {
+				rho = 1
+			}
+			m.values[i][j], m.values[j][i] = rho, rho
+			m.counts[i][j], m.counts[j][i] = n, n
+		}
+	}
+
+	return m
+}
+
+func (m Matrix) Symbols() []string {
+	return m.symbols
+}
+
+// Get returns the correlation of two stocks and the number of returns it is based on.
+func (m Matrix) Get(symbol1, symbol2 string) (float64, int, error) {
+	i, j := -1, -1
+	for k, s := range m.symbols {
+		if s == symbol1 {
+			i = k
+		}
+		if s == symbol2 {
+			j = k
+		}
+	}
+	if i < 0 || j < 0 {
+		return math.NaN(), 0, fmt.Errorf("symbol not found in correlation matrix: %s, %s", symbol1, symbol2)
+	}
+
+	return m.values[i][j], m.counts[i][j], nil
+}
+
+func (m Matrix) String() string {
+	str := "      "
+	for _, s := range m.symbols {
+		str += fmt.Sprintf(" %6s", s)
+	}
+	str += "\n"
+
+	for i, s := range m.symbols {
+		str += fmt.Sprintf("%-6s", s)
+		for j := range m.symbols {
+			str += fmt.Sprintf(" %6.2f", m.values[i][j])
+		}
+		str += "\n"
+	}
+
+	return str
+}
+
+// RollingPoint holds the correlation of two stocks over the window ending on a date.
+type RollingPoint struct {
+	Date  time.Time
+	Value float64 // NaN if fewer than half the window's returns are present for both stocks.
+	Count int     // Number of returns used.
+}
+
+// Rolling computes the correlation of two stocks over a moving window of returns.
+func (a Aligned) Rolling(symbol1, symbol2 string, window int) ([]RollingPoint, error) {
+	if window < 2 {
+		return nil, fmt.Errorf("invalid rolling correlation window: %d", window)
+	}
+
+	i, err := a.index(symbol1)
+	if err != nil {
+		return nil, err
+	}
+
+	j, err := a.index(symbol2)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]RollingPoint, 0, len(a.dates))
+	for end := window; end <= len(a.dates); end++ {
+		rho, n := pearson(a.returns[i][end-window:end], a.returns[j][end-window:end], nil)
+		if n < (window+1)/2 {
+			rho = math.NaN()
+		}
+		points = append(points, RollingPoint{Date: a.dates[end-1], Value: rho, Count: n})
+	}
+
+	return points, nil
+}
+
+func (rp RollingPoint) String() string {
+	return fmt.Sprintf("%s %6.2f %5d", rp.Date.Format("2006-01-02"), rp.Value, rp.Count)
+}
@@ -0,0 +1,166 @@
+package correlation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Daily returns in percent: alternating, and orthogonal to alternating over each four days.
+var (
+	alternating = []float64{1, -1, 1, -1, 1, -1, 1, -1}
+	orthogonal  = []float64{1, 1, -1, -1, 1, 1, -1, -1}
+)
+
+// testPrices returns daily prices starting at the first price on consecutive weekdays from January 2, 2024,
+// with the given daily log returns in percent. Dates with a skip flag set are left out.
+func testPrices(t *testing.T, first float64, returns []float64, skip ...int) []stock.DailyPrice {
+	t.Helper()
+
+	skipped := make(map[int]bool, len(skip))
+	for _, i := range skip {
+		skipped[i] = true
+	}
+
+	var prices []stock.DailyPrice
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	c := first
+	for i := 0; i <= len(returns); i++ {
+		for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			date = date.AddDate(0, 0, 1)
+		}
+		if i > 0 {
+			c *= math.Exp(returns[i-1] / 100)
+		}
+
+		if !skipped[i] {
+			dp, err := stock.NewDailyPrice(date.Year(), int(date.Month()), date.Day(), c, c, c, c, 1000)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prices = append(prices, dp)
+		}
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return prices
+}
+
+// scaled returns the returns multiplied by the factor, with the other's returns added if given.
+func scaled(returns []float64, factor float64, other []float64) []float64 {
+	result := make([]float64, len(returns))
+	for i, r := range returns {
+		result[i] = r * factor
+		if other != nil {
+			result[i] += other[i]
+		}
+	}
+
+	return result
+}
+
+func assertCorrelation(t *testing.T, m Matrix, symbol1, symbol2 string, want float64, wantCount int) {
+	t.Helper()
+
+	rho, n, err := m.Get(symbol1, symbol2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.IsNaN(rho) || math.Abs(rho-want) > 1e-9 || n != wantCount {
+		t.Errorf("correlation of %s and %s = %.4f over %d returns, want %.4f over %d", symbol1, symbol2, rho, n, want, wantCount)
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	a, err := Align(map[string][]stock.DailyPrice{
+		"SAME":  testPrices(t, 100, alternating),
+		"TWICE": testPrices(t, 50, scaled(alternating, 2, nil)),
+		"OPP":   testPrices(t, 80, scaled(alternating, -1, nil)),
+		"OTHER": testPrices(t, 30, orthogonal),
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := a.Correlation(PairwiseComplete, 2)
+	assertCorrelation(t, m, "SAME", "SAME", 1, 8)
+	assertCorrelation(t, m, "SAME", "TWICE", 1, 8)
+	assertCorrelation(t, m, "SAME", "OPP", -1, 8)
+	assertCorrelation(t, m, "TWICE", "OPP", -1, 8)
+	assertCorrelation(t, m, "SAME", "OTHER", 0, 8)
+	assertCorrelation(t, m, "OTHER", "OPP", 0, 8)
+
+	if _, _, err = m.Get("SAME", "NONE"); err == nil {
+		t.Error("got the correlation of an unknown symbol, want an error")
+	}
+}
+
+// A missing close leaves gaps in the returns to and from its date.
+func TestCorrelationGaps(t *testing.T) {
+	a, err := Align(map[string][]stock.DailyPrice{
+		"FULL": testPrices(t, 100, alternating),
+		"GAP":  testPrices(t, 50, alternating, 4),
+		"OPP":  testPrices(t, 80, scaled(alternating, -1, nil)),
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gaps := a.Gaps(); gaps["GAP"] != 2 || gaps["FULL"] != 0 {
+		t.Errorf("gaps = %v, want 2 for GAP only", gaps)
+	}
+
+	m := a.Correlation(PairwiseComplete, 2)
+	assertCorrelation(t, m, "FULL", "GAP", 1, 6)
+	assertCorrelation(t, m, "FULL", "OPP", -1, 8)
+
+	m = a.Correlation(CompleteCases, 2)
+	assertCorrelation(t, m, "FULL", "OPP", -1, 6)
+
+	// Too few overlapping returns leave the correlation unknown.
+	m = a.Correlation(PairwiseComplete, 7)
+	if rho, n, _ := m.Get("FULL", "GAP"); !math.IsNaN(rho) || n != 6 {
+		t.Errorf("correlation over %d returns = %.4f, want NaN", n, rho)
+	}
+}
+
+func TestRolling(t *testing.T) {
+	a, err := Align(map[string][]stock.DailyPrice{
+		"SAME":  testPrices(t, 100, alternating),
+		"OPP":   testPrices(t, 80, scaled(alternating, -1, nil)),
+		"OTHER": testPrices(t, 30, orthogonal),
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := a.Rolling("SAME", "OPP", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 5 {
+		t.Fatalf("%d rolling correlations, want 5", len(points))
+	}
+	for i, pt := range points {
+		if math.Abs(pt.Value+1) > 1e-9 || pt.Count != 4 || !pt.Date.Equal(a.Dates()[i+3]) {
+			t.Errorf("rolling correlation %s, want -1 over 4 returns ending %s", pt, a.Dates()[i+3].Format("2006-01-02"))
+		}
+	}
+
+	// The orthogonal returns are uncorrelated over each window starting on an even day.
+	points, err = a.Rolling("SAME", "OTHER", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []int{0, 2, 4} {
+		if math.Abs(points[i].Value) > 1e-9 {
+			t.Errorf("rolling correlation %s, want 0", points[i])
+		}
+	}
+
+	if _, err = a.Rolling("SAME", "OPP", 1); err == nil {
+		t.Error("rolling correlation over 1 return, want an error")
+	}
+}
@@ -0,0 +1,98 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// ADXValue holds the average directional index and the directional indicators, from 0 to 100.
+type ADXValue struct {
+	ADX     float64
+	PlusDI  float64
+	MinusDI float64
+}
+
+// ADX is Wilder's average directional index.
+type ADX struct {
+	started bool
+	prevHi  float64
+	prevLo  float64
+	tr      trueRange
+	avgTR   wilder
+	avgPlus wilder
+	avgMin  wilder
+	avgDX   wilder
+	value   ADXValue
+}
+
+func NewADX(period int) (*ADX, error) {
+	if err := validatePeriod("ADX", period); err != nil {
+		return nil, err
+	}
+
+	return &ADX{
+		avgTR:   wilder{period: period},
+		avgPlus: wilder{period: period},
+		avgMin:  wilder{period: period},
+		avgDX:   wilder{period: period},
+		value:   ADXValue{ADX: math.NaN(), PlusDI: math.NaN(), MinusDI: math.NaN()},
+	}, nil
+}
+
+func (a *ADX) Update(b stock.Bar) ADXValue {
+	h, l := b.HighLow()
+	tr := a.tr.update(b)
+	if !a.started {
+		a.started = true
+		a.prevHi, a.prevLo = h, l
+		return a.value
+	}
+
+	upMove, downMove := h-a.prevHi, a.prevLo-l
+	a.prevHi, a.prevLo = h, l
+
+	plusDM, minusDM := 0.0, 0.0
+	if upMove > downMove && upMove > 0 {
+		plusDM = upMove
+	}
+	if downMove > upMove && downMove > 0 {
+		minusDM = downMove
+	}
+
+	avgTR := a.avgTR.add(tr)
+	avgPlus := a.avgPlus.add(plusDM)
+	avgMinus := a.avgMin.add(minusDM)
+	if math.IsNaN(avgTR) || avgTR == 0 {
+		return a.value
+	}
+
+	a.value.PlusDI = avgPlus / avgTR * 100
+	a.value.MinusDI = avgMinus / avgTR * 100
+
+	dx := 0.0
+	if sum := a.value.PlusDI + a.value.MinusDI; sum > 0 {
+		dx = math.Abs(a.value.PlusDI-a.value.MinusDI) / sum * 100
+	}
+	a.value.ADX = a.avgDX.add(dx)
+
+	return a.value
+}
+
+func (a *ADX) Value() ADXValue {
+	return a.value
+}
+
+func ADXSeries(bars []stock.Bar, period int) ([]ADXValue, error) {
+	a, err := NewADX(period)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]ADXValue, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, a.Update(b))
+	}
+
+	return values, nil
+}
@@ -0,0 +1,59 @@
+package indicators
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Wilder's ADX with a period of 3 on the bars of the ATR worked example. From the second bar the
+// directional movements are
+//
+//	+DM 0.6, 0.3, 0,   0.5, 0.3, 0,   0,   0.5, 0.5
+//	-DM 0,   0,   0.2, 0,   0,   0.5, 0.2, 0,   0
+//
+// The true ranges and directional movements are smoothed from the second bar, so the directional
+// indicators are first defined at the fourth bar and the ADX, the smoothed DX, at the sixth.
+func TestADXWorkedExample(t *testing.T) {
+	plusDI := []float64{45.0000, 49.2537, 49.4681, 31.4189, 22.2754, 38.8613, 49.8057}
+	minusDI := []float64{10.0000, 5.9701, 4.2553, 25.5068, 27.7844, 18.4090, 12.2226}
+	adx := []float64{75.3911, 53.7226, 39.4833, 38.2262, 45.6809}
+
+	values, err := ADXSeries(workedBars(t), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		assertNaN(t, fmt.Sprintf("+DI[%d]", i), values[i].PlusDI)
+		assertNaN(t, fmt.Sprintf("-DI[%d]", i), values[i].MinusDI)
+	}
+	for i := range plusDI {
+		assertNear(t, fmt.Sprintf("+DI[%d]", i+3), values[i+3].PlusDI, plusDI[i], 0.0001)
+		assertNear(t, fmt.Sprintf("-DI[%d]", i+3), values[i+3].MinusDI, minusDI[i], 0.0001)
+	}
+
+	for i := 0; i < 5; i++ {
+		assertNaN(t, fmt.Sprintf("ADX[%d]", i), values[i].ADX)
+	}
+	for i, w := range adx {
+		assertNear(t, fmt.Sprintf("ADX[%d]", i+5), values[i+5].ADX, w, 0.0001)
+	}
+}
+
+// Bars that only rise have no downward movement, so -DI is 0 and the ADX is 100.
+func TestADXTrend(t *testing.T) {
+	closes := make([]float64, 20)
+	for i := range closes {
+		closes[i] = 100 + float64(i)
+	}
+
+	values, err := ADXSeries(closeBars(t, closes), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := values[len(values)-1]
+	assertNear(t, "+DI", last.PlusDI, 100, 1e-9)
+	assertNear(t, "-DI", last.MinusDI, 0, 1e-9)
+	assertNear(t, "ADX", last.ADX, 100, 1e-9)
+}
@@ -0,0 +1,87 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// trueRange tracks the previous close to compute each bar's true range.
+type trueRange struct {
+	prevClose float64
+}
+
+func (tr *trueRange) update(b stock.Bar) float64 {
+	h, l := b.HighLow()
+	_, c := b.OpenClose()
+
+	r := h - l
+	if tr.prevClose > 0 {
+		r = math.Max(r, math.Max(math.Abs(h-tr.prevClose), math.Abs(l-tr.prevClose)))
+	}
+	tr.prevClose = c
+
+	return r
+}
+
+// wilder is Wilder's smoothed average, seeded with the simple average of the first period.
+type wilder struct {
+	period int
+	count  int
+	value  float64
+}
+
+func (w *wilder) add(v float64) float64 {
+	w.count++
+	n := float64(w.period)
+	switch {
+	case w.count < w.period:
+		w.value += v
+		return math.NaN()
+	case w.count == w.period:
+		w.value = (w.value + v) / n
+	default:
+		w.value = (w.value*(n-1) + v) / n
+	}
+
+	return w.value
+}
+
+// ATR is Wilder's average true range.
+type ATR struct {
+	tr    trueRange
+	avg   wilder
+	value float64
+}
+
+func NewATR(period int) (*ATR, error) {
+	if err := validatePeriod("ATR", period); err != nil {
+		return nil, err
+	}
+
+	return &ATR{avg: wilder{period: period}, value: math.NaN()}, nil
+}
+
+func (a *ATR) Update(b stock.Bar) float64 {
+	a.value = a.avg.add(a.tr.update(b))
+
+	return a.value
+}
+
+func (a *ATR) Value() float64 {
+	return a.value
+}
+
+func ATRSeries(bars []stock.Bar, period int) ([]float64, error) {
+	a, err := NewATR(period)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, a.Update(b))
+	}
+
+	return values, nil
+}
@@ -0,0 +1,44 @@
+package indicators
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// workedBars returns the bars of the ATR and ADX worked examples.
+func workedBars(t *testing.T) []stock.Bar {
+	t.Helper()
+
+	highs := []float64{10.0, 10.6, 10.9, 10.7, 11.2, 11.5, 11.1, 10.8, 11.3, 11.8}
+	lows := []float64{9.5, 9.9, 10.3, 10.1, 10.5, 10.9, 10.4, 10.2, 10.6, 11.1}
+	closes := []float64{9.8, 10.4, 10.6, 10.3, 11.0, 11.2, 10.6, 10.7, 11.1, 11.6}
+
+	bars := make([]stock.Bar, 0, len(closes))
+	for i := range closes {
+		bars = append(bars, testBar(t, i, lows[i], closes[i], highs[i], lows[i], 1000))
+	}
+
+	return bars
+}
+
+// Wilder's average true range with a period of 3. The true ranges are
+//
+//	0.5 (high - low of the first bar), 0.8 (high - previous close), 0.6, 0.6, 0.9, 0.6, 0.8, 0.6, 0.7, 0.7
+//
+// The first ATR is the mean of the first three, then each is (previous * 2 + true range) / 3.
+func TestATRWorkedExample(t *testing.T) {
+	want := []float64{0.6333, 0.6222, 0.7148, 0.6765, 0.7177, 0.6785, 0.6856, 0.6904}
+
+	values, err := ATRSeries(workedBars(t), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertNaN(t, "ATR[0]", values[0])
+	assertNaN(t, "ATR[1]", values[1])
+	for i, w := range want {
+		assertNear(t, fmt.Sprintf("ATR[%d]", i+2), values[i+2], w, 0.0001)
+	}
+}
@@ -0,0 +1,71 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// BollingerValue holds the Bollinger Bands.
+type BollingerValue struct {
+	Middle float64 // Simple moving average.
+	Upper  float64 // Middle plus the band width in standard deviations.
+	Lower  float64 // Middle less the band width in standard deviations.
+}
+
+// Bollinger is the Bollinger Bands of closing prices, using the population standard deviation.
+type Bollinger struct {
+	sma   *SMA
+	width float64
+	value BollingerValue
+}
+
+// NewBollinger creates Bollinger Bands; the standard settings are a period of 20 and a width of 2.
+func NewBollinger(period int, width float64) (*Bollinger, error) {
+	sma, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bollinger{
+		sma:   sma,
+		width: width,
+		value: BollingerValue{Middle: math.NaN(), Upper: math.NaN(), Lower: math.NaN()},
+	}, nil
+}
+
+func (bb *Bollinger) Update(b stock.Bar) BollingerValue {
+	_, c := b.OpenClose()
+	middle := bb.sma.add(c)
+	if math.IsNaN(middle) {
+		return bb.value
+	}
+
+	variance := 0.0
+	for _, v := range bb.sma.window.values {
+		variance += (v - middle) * (v - middle)
+	}
+	sd := math.Sqrt(variance / float64(len(bb.sma.window.values)))
+
+	bb.value = BollingerValue{Middle: middle, Upper: middle + bb.width*sd, Lower: middle - bb.width*sd}
+
+	return bb.value
+}
+
+func (bb *Bollinger) Value() BollingerValue {
+	return bb.value
+}
+
+func BollingerSeries(bars []stock.Bar, period int, width float64) ([]BollingerValue, error) {
+	bb, err := NewBollinger(period, width)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]BollingerValue, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, bb.Update(b))
+	}
+
+	return values, nil
+}
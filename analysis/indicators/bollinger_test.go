@@ -0,0 +1,42 @@
+package indicators
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Bollinger Bands with the standard period of 20 and width of 2 on the closes of Wilder's RSI example.
+func TestBollingerSample(t *testing.T) {
+	want := map[int]BollingerValue{
+		19: {Middle: 45.4090, Upper: 47.1153, Lower: 43.7027},
+		20: {Middle: 45.5025, Upper: 47.1687, Lower: 43.8363},
+		25: {Middle: 45.9290, Upper: 46.6516, Lower: 45.2064},
+		32: {Middle: 45.2410, Upper: 47.6202, Lower: 42.8618},
+	}
+
+	values, err := BollingerSeries(closeBars(t, wilderCloses), 20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertNaN(t, "Middle[18]", values[18].Middle)
+	for i, w := range want {
+		assertNear(t, fmt.Sprintf("Middle[%d]", i), values[i].Middle, w.Middle, 0.0001)
+		assertNear(t, fmt.Sprintf("Upper[%d]", i), values[i].Upper, w.Upper, 0.0001)
+		assertNear(t, fmt.Sprintf("Lower[%d]", i), values[i].Lower, w.Lower, 0.0001)
+	}
+}
+
+// The bands use the population standard deviation: 2, 4, 4, 4, 5, 5, 7, 9 has a mean of 5 and deviation of 2.
+func TestBollingerPopulationDeviation(t *testing.T) {
+	values, err := BollingerSeries(closeBars(t, []float64{2, 4, 4, 4, 5, 5, 7, 9}), 8, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := values[len(values)-1]
+	assertNear(t, "Middle", last.Middle, 5, 1e-9)
+	assertNear(t, "Upper", last.Upper, 8, 1e-9)
+	assertNear(t, "Lower", last.Lower, 2, 1e-9)
+	assertNaN(t, "Middle[6]", values[6].Middle)
+}
@@ -0,0 +1,65 @@
+// Package indicators computes technical indicators over stock price bars.
+//
+// Each indicator is a value that is updated one bar at a time, so it can be kept current as new
+// bars are appended, and has a Series function to compute it over a whole price history.
+// Daily prices and one minute sales are converted to bars with stock.DailyPriceBars and stock.OneMinSaleBars.
+// Values are NaN until enough bars have been added for the indicator to be defined.
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+func validatePeriod(name string, period int) error {
+	if period < 1 {
+		return fmt.Errorf("invalid %s period: %d", name, period)
+	}
+
+	return nil
+}
+
+// window holds the most recent values of a series, up to its period.
+type window struct {
+	values []float64
+	next   int // Position of the oldest value once the window is full.
+	full   bool
+}
+
+func newWindow(period int) *window {
+	return &window{values: make([]float64, 0, period)}
+}
+
+// add inserts a value, returning the value it replaced and true if the window was already full.
+func (w *window) add(v float64) (float64, bool) {
+	if !w.full {
+		w.values = append(w.values, v)
+		w.full = len(w.values) == cap(w.values)
+		return 0, false
+	}
+
+	old := w.values[w.next]
+	w.values[w.next] = v
+	w.next = (w.next + 1) % len(w.values)
+
+	return old, true
+}
+
+// ordered returns the values from oldest to newest.
+func (w *window) ordered() []float64 {
+	if !w.full {
+		return w.values
+	}
+
+	return append(append([]float64{}, w.values[w.next:]...), w.values[:w.next]...)
+}
+
+func (w *window) maxMin() (float64, float64) {
+	max, min := math.Inf(-1), math.Inf(1)
+	for _, v := range w.values {
+		max = math.Max(max, v)
+		min = math.Min(min, v)
+	}
+
+	return max, min
+}
@@ -0,0 +1,263 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Closing prices of Wilder's RSI example as published by StockCharts.
+var wilderCloses = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89,
+	46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64, 46.21, 46.25,
+	45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57, 43.42, 42.66, 43.13,
+}
+
+// testBar returns a daily bar for the given day after the first trading day of 2024.
+func testBar(t *testing.T, day int, open, close, high, low float64, volume int64) stock.Bar {
+	t.Helper()
+
+	date := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC).AddDate(0, 0, day)
+	b, err := stock.NewBar(date, date, open, close, high, low, volume, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+// closeBars returns bars which open, close and trade only at the given closing prices.
+func closeBars(t *testing.T, closes []float64) []stock.Bar {
+	t.Helper()
+
+	bars := make([]stock.Bar, 0, len(closes))
+	for i, c := range closes {
+		bars = append(bars, testBar(t, i, c, c, c, c, 1000))
+	}
+
+	return bars
+}
+
+func assertNear(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+
+	if math.IsNaN(got) || math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %.4f, want %.4f (tolerance %g)", name, got, want, tolerance)
+	}
+}
+
+func assertNaN(t *testing.T, name string, got float64) {
+	t.Helper()
+
+	if !math.IsNaN(got) {
+		t.Errorf("%s = %.4f, want NaN", name, got)
+	}
+}
+
+// testPrice returns a price which wanders up and down without repeating, for bars with realistic ranges.
+func testPrice(i int) float64 {
+	return 100 + 8*math.Sin(float64(i)/5) + 3*math.Sin(float64(i)/1.7) + float64(i)/10
+}
+
+// incrementalCase is an indicator updated one bar at a time, along with its Series function.
+type incrementalCase struct {
+	name   string
+	update func() func(stock.Bar) interface{} // Returns the Update method of a new indicator.
+	series func(bars []stock.Bar) (interface{}, error)
+}
+
+func incrementalCases() []incrementalCase {
+	return []incrementalCase{
+		{
+			name: "SMA",
+			update: func() func(stock.Bar) interface{} {
+				s, _ := NewSMA(10)
+				return func(b stock.Bar) interface{} { return s.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return SMASeries(bars, 10) },
+		},
+		{
+			name: "EMA",
+			update: func() func(stock.Bar) interface{} {
+				e, _ := NewEMA(10)
+				return func(b stock.Bar) interface{} { return e.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return EMASeries(bars, 10) },
+		},
+		{
+			name: "WMA",
+			update: func() func(stock.Bar) interface{} {
+				w, _ := NewWMA(10)
+				return func(b stock.Bar) interface{} { return w.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return WMASeries(bars, 10) },
+		},
+		{
+			name: "RSI",
+			update: func() func(stock.Bar) interface{} {
+				r, _ := NewRSI(14)
+				return func(b stock.Bar) interface{} { return r.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return RSISeries(bars, 14) },
+		},
+		{
+			name: "ATR",
+			update: func() func(stock.Bar) interface{} {
+				a, _ := NewATR(14)
+				return func(b stock.Bar) interface{} { return a.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return ATRSeries(bars, 14) },
+		},
+		{
+			name: "ADX",
+			update: func() func(stock.Bar) interface{} {
+				a, _ := NewADX(14)
+				return func(b stock.Bar) interface{} { return a.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return ADXSeries(bars, 14) },
+		},
+		{
+			name: "Bollinger",
+			update: func() func(stock.Bar) interface{} {
+				bb, _ := NewBollinger(20, 2)
+				return func(b stock.Bar) interface{} { return bb.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return BollingerSeries(bars, 20, 2) },
+		},
+		{
+			name: "MACD",
+			update: func() func(stock.Bar) interface{} {
+				m, _ := NewMACD(12, 26, 9)
+				return func(b stock.Bar) interface{} { return m.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return MACDSeries(bars, 12, 26, 9) },
+		},
+		{
+			name: "Stochastic",
+			update: func() func(stock.Bar) interface{} {
+				s, _ := NewStochastic(14, 3, 3)
+				return func(b stock.Bar) interface{} { return s.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return StochasticSeries(bars, 14, 3, 3) },
+		},
+		{
+			name: "OBV",
+			update: func() func(stock.Bar) interface{} {
+				o := NewOBV()
+				return func(b stock.Bar) interface{} { return o.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return OBVSeries(bars), nil },
+		},
+		{
+			name: "VWAP",
+			update: func() func(stock.Bar) interface{} {
+				v := NewVWAP(true)
+				return func(b stock.Bar) interface{} { return v.Update(b) }
+			},
+			series: func(bars []stock.Bar) (interface{}, error) { return VWAPSeries(bars, true), nil },
+		},
+	}
+}
+
+// assertIncremental updates each indicator with the bars before and after an append,
+// and checks that every value matches the series computed over all the bars.
+func assertIncremental(t *testing.T, before, appended, all []stock.Bar) {
+	t.Helper()
+
+	for _, tc := range incrementalCases() {
+		update := tc.update()
+		values := make([]interface{}, 0, len(all))
+		for _, b := range before {
+			values = append(values, update(b))
+		}
+		for _, b := range appended {
+			values = append(values, update(b))
+		}
+
+		series, err := tc.series(all)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		// Formatting compares NaN values as equal and floats exactly.
+		if got, want := fmt.Sprint(values), fmt.Sprint(series); got != want {
+			t.Errorf("%s incremental values differ from the full series:\n got %s\nwant %s", tc.name, got, want)
+		}
+	}
+}
+
+func TestIncrementalDailyPrices(t *testing.T) {
+	s := stock.NewStock("TEST")
+
+	var dps []*stock.DailyPrice
+	date := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 80; i++ {
+		for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			date = date.AddDate(0, 0, 1)
+		}
+
+		open, close := testPrice(2*i), testPrice(2*i+1)
+		dp, err := stock.NewDailyPrice(date.Year(), int(date.Month()), date.Day(),
+			open, close, math.Max(open, close)+1.5, math.Min(open, close)-1.25, int64(1000000+7919*i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dps = append(dps, &dp)
+		date = date.AddDate(0, 0, 1)
+	}
+
+	if err := s.AppendDailyPrices(dps[:50]); err != nil {
+		t.Fatal(err)
+	}
+	before := stock.DailyPriceBars(s.Prices())
+
+	if err := s.AppendDailyPrices(dps[50:]); err != nil {
+		t.Fatal(err)
+	}
+	all := stock.DailyPriceBars(s.Prices())
+
+	assertIncremental(t, before, all[len(before):], all)
+}
+
+func TestIncrementalOneMinSales(t *testing.T) {
+	// One minute sales must be recent, so use the two weekdays before today.
+	var days []time.Time
+	for day := time.Now().AddDate(0, 0, -1); len(days) < 2; day = day.AddDate(0, 0, -1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days = append([]time.Time{day}, days...)
+		}
+	}
+
+	s := stock.NewStock("TEST")
+
+	var omss []stock.OneMinSale
+	for d, day := range days {
+		for m := 0; m < 45; m++ {
+			i := d*45 + m
+			open, close := testPrice(2*i), testPrice(2*i+1)
+			oms, err := stock.NewOneMinSale(day.Year(), int(day.Month()), day.Day(),
+				stock.MarketOpenHour+(stock.MarketOpenMinute+m)/60, (stock.MarketOpenMinute+m)%60,
+				open, close, math.Max(open, close)+0.25, math.Min(open, close)-0.2, int64(5000+37*i), (open+close)/2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			omss = append(omss, oms)
+		}
+	}
+
+	// Append through the middle of the first session, as minutes arrive during the day.
+	if err := s.AppendOneMinSales(omss[:30]); err != nil {
+		t.Fatal(err)
+	}
+	before := stock.OneMinSaleBars(s.OneMinSales())
+
+	if err := s.AppendOneMinSales(omss[30:]); err != nil {
+		t.Fatal(err)
+	}
+	all := stock.OneMinSaleBars(s.OneMinSales())
+
+	assertIncremental(t, before, all[len(before):], all)
+}
@@ -0,0 +1,84 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// MACDValue holds the moving average convergence/divergence lines.
+type MACDValue struct {
+	MACD      float64 // Fast EMA less slow EMA.
+	Signal    float64 // EMA of the MACD line.
+	Histogram float64 // MACD less Signal.
+}
+
+// MACD is the moving average convergence/divergence of closing prices.
+type MACD struct {
+	fast   *EMA
+	slow   *EMA
+	signal *EMA
+	value  MACDValue
+}
+
+// NewMACD creates a MACD indicator; the standard periods are 12, 26 and 9.
+func NewMACD(fastPeriod, slowPeriod, signalPeriod int) (*MACD, error) {
+	if fastPeriod >= slowPeriod {
+		return nil, fmt.Errorf("MACD fast period %d must be shorter than slow period %d", fastPeriod, slowPeriod)
+	}
+
+	fast, err := NewEMA(fastPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	slow, err := NewEMA(slowPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	signal, err := NewEMA(signalPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MACD{
+		fast:   fast,
+		slow:   slow,
+		signal: signal,
+		value:  MACDValue{MACD: math.NaN(), Signal: math.NaN(), Histogram: math.NaN()},
+	}, nil
+}
+
+func (m *MACD) Update(b stock.Bar) MACDValue {
+	fast := m.fast.Update(b)
+	slow := m.slow.Update(b)
+	if math.IsNaN(slow) {
+		return m.value
+	}
+
+	m.value.MACD = fast - slow
+	m.value.Signal = m.signal.add(m.value.MACD)
+	m.value.Histogram = m.value.MACD - m.value.Signal
+
+	return m.value
+}
+
+func (m *MACD) Value() MACDValue {
+	return m.value
+}
+
+func MACDSeries(bars []stock.Bar, fastPeriod, slowPeriod, signalPeriod int) ([]MACDValue, error) {
+	m, err := NewMACD(fastPeriod, slowPeriod, signalPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]MACDValue, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, m.Update(b))
+	}
+
+	return values, nil
+}
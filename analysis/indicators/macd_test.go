@@ -0,0 +1,42 @@
+package indicators
+
+import (
+	"fmt"
+	"testing"
+)
+
+// MACD with periods of 5, 10 and 4 on the closes of Wilder's RSI example. Each EMA is seeded with the
+// simple average of its first period, so the MACD line starts at the tenth close and the signal line
+// four values later.
+func TestMACDSample(t *testing.T) {
+	want := map[int]MACDValue{
+		9:  {MACD: 0.7106},
+		12: {MACD: 0.4577, Signal: 0.5993, Histogram: -0.1416},
+		19: {MACD: 0.1257, Signal: 0.2357, Histogram: -0.1100},
+		26: {MACD: -0.3273, Signal: -0.1177, Histogram: -0.2096},
+		32: {MACD: -0.6082, Signal: -0.5410, Histogram: -0.0672},
+	}
+
+	values, err := MACDSeries(closeBars(t, wilderCloses), 5, 10, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertNaN(t, "MACD[8]", values[8].MACD)
+	assertNaN(t, "Signal[11]", values[11].Signal)
+	for i, w := range want {
+		assertNear(t, fmt.Sprintf("MACD[%d]", i), values[i].MACD, w.MACD, 0.0001)
+		if i == 9 {
+			assertNaN(t, "Signal[9]", values[i].Signal)
+			continue
+		}
+		assertNear(t, fmt.Sprintf("Signal[%d]", i), values[i].Signal, w.Signal, 0.0001)
+		assertNear(t, fmt.Sprintf("Histogram[%d]", i), values[i].Histogram, w.Histogram, 0.0001)
+	}
+}
+
+func TestMACDInvalidPeriods(t *testing.T) {
+	if _, err := NewMACD(26, 12, 9); err == nil {
+		t.Error("NewMACD(26, 12, 9) succeeded, want an error")
+	}
+}
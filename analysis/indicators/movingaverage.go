@@ -0,0 +1,159 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// SMA is the simple moving average of closing prices.
+type SMA struct {
+	window *window
+	sum    float64
+	value  float64
+}
+
+func NewSMA(period int) (*SMA, error) {
+	if err := validatePeriod("SMA", period); err != nil {
+		return nil, err
+	}
+
+	return &SMA{window: newWindow(period), value: math.NaN()}, nil
+}
+
+func (s *SMA) Update(b stock.Bar) float64 {
+	_, c := b.OpenClose()
+
+	return s.add(c)
+}
+
+func (s *SMA) add(v float64) float64 {
+	old, _ := s.window.add(v)
+	s.sum += v - old
+	if s.window.full {
+		s.value = s.sum / float64(len(s.window.values))
+	}
+
+	return s.value
+}
+
+func (s *SMA) Value() float64 {
+	return s.value
+}
+
+func SMASeries(bars []stock.Bar, period int) ([]float64, error) {
+	s, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, s.Update(b))
+	}
+
+	return values, nil
+}
+
+// EMA is the exponential moving average of closing prices, seeded with the simple average of the first period.
+type EMA struct {
+	period int
+	alpha  float64
+	count  int
+	sum    float64
+	value  float64
+}
+
+func NewEMA(period int) (*EMA, error) {
+	if err := validatePeriod("EMA", period); err != nil {
+		return nil, err
+	}
+
+	return &EMA{period: period, alpha: 2 / float64(period+1), value: math.NaN()}, nil
+}
+
+func (e *EMA) Update(b stock.Bar) float64 {
+	_, c := b.OpenClose()
+
+	return e.add(c)
+}
+
+func (e *EMA) add(v float64) float64 {
+	e.count++
+	switch {
+	case e.count < e.period:
+		e.sum += v
+	case e.count == e.period:
+		e.value = (e.sum + v) / float64(e.period)
+	default:
+		e.value += e.alpha * (v - e.value)
+	}
+
+	return e.value
+}
+
+func (e *EMA) Value() float64 {
+	return e.value
+}
+
+func EMASeries(bars []stock.Bar, period int) ([]float64, error) {
+	e, err := NewEMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, e.Update(b))
+	}
+
+	return values, nil
+}
+
+// WMA is the linearly weighted moving average of closing prices, with the newest close weighted highest.
+type WMA struct {
+	window *window
+	value  float64
+}
+
+func NewWMA(period int) (*WMA, error) {
+	if err := validatePeriod("WMA", period); err != nil {
+		return nil, err
+	}
+
+	return &WMA{window: newWindow(period), value: math.NaN()}, nil
+}
+
+func (w *WMA) Update(b stock.Bar) float64 {
+	_, c := b.OpenClose()
+	w.window.add(c)
+
+	if w.window.full {
+		sum, weights := 0.0, 0.0
+		for i, v := range w.window.ordered() {
+			sum += v * float64(i+1)
+			weights += float64(i + 1)
+		}
+		w.value = sum / weights
+	}
+
+	return w.value
+}
+
+func (w *WMA) Value() float64 {
+	return w.value
+}
+
+func WMASeries(bars []stock.Bar, period int) ([]float64, error) {
+	w, err := NewWMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, w.Update(b))
+	}
+
+	return values, nil
+}
@@ -0,0 +1,64 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// RSI is Wilder's relative strength index of closing prices.
+type RSI struct {
+	prevClose float64
+	avgGain   wilder
+	avgLoss   wilder
+	value     float64
+}
+
+func NewRSI(period int) (*RSI, error) {
+	if err := validatePeriod("RSI", period); err != nil {
+		return nil, err
+	}
+
+	return &RSI{avgGain: wilder{period: period}, avgLoss: wilder{period: period}, value: math.NaN()}, nil
+}
+
+func (r *RSI) Update(b stock.Bar) float64 {
+	_, c := b.OpenClose()
+	if r.prevClose == 0 {
+		r.prevClose = c
+		return r.value
+	}
+
+	change := c - r.prevClose
+	r.prevClose = c
+
+	gain := r.avgGain.add(math.Max(change, 0))
+	loss := r.avgLoss.add(math.Max(-change, 0))
+	switch {
+	case math.IsNaN(gain):
+	case loss == 0:
+		r.value = 100
+	default:
+		r.value = 100 - 100/(1+gain/loss)
+	}
+
+	return r.value
+}
+
+func (r *RSI) Value() float64 {
+	return r.value
+}
+
+func RSISeries(bars []stock.Bar, period int) ([]float64, error) {
+	r, err := NewRSI(period)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, r.Update(b))
+	}
+
+	return values, nil
+}
@@ -0,0 +1,45 @@
+package indicators
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Wilder's RSI example with a period of 14, as published by StockCharts. The first value is defined
+// at the fifteenth close, after fourteen changes. The published table differs in the second decimal,
+// so values are compared to 0.1.
+func TestRSIWilderExample(t *testing.T) {
+	want := []float64{
+		70.53, 66.32, 66.55, 69.41, 66.36, 57.97, 62.93, 63.26, 56.06, 62.38,
+		54.71, 50.42, 39.99, 41.46, 41.87, 45.46, 37.30, 33.08, 37.77,
+	}
+
+	values, err := RSISeries(closeBars(t, wilderCloses), 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 14; i++ {
+		assertNaN(t, fmt.Sprintf("RSI[%d]", i), values[i])
+	}
+	for i, w := range want {
+		assertNear(t, fmt.Sprintf("RSI[%d]", i+14), values[i+14], w, 0.1)
+	}
+}
+
+func TestRSIAllGains(t *testing.T) {
+	values, err := RSISeries(closeBars(t, []float64{10, 11, 12, 13, 14}), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertNaN(t, "RSI[2]", values[2])
+	assertNear(t, "RSI[3]", values[3], 100, 0)
+	assertNear(t, "RSI[4]", values[4], 100, 0)
+}
+
+func TestRSIInvalidPeriod(t *testing.T) {
+	if _, err := NewRSI(0); err == nil {
+		t.Error("NewRSI(0) succeeded, want an error")
+	}
+}
@@ -0,0 +1,91 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// StochasticValue holds the stochastic oscillator lines, from 0 to 100.
+type StochasticValue struct {
+	K float64
+	D float64 // Simple moving average of K.
+}
+
+// Stochastic is the stochastic oscillator.
+// With a K smoothing period of 1 it is the fast stochastic, with 3 it is the standard slow stochastic.
+type Stochastic struct {
+	highs   *window
+	lows    *window
+	smoothK *SMA
+	d       *SMA
+	value   StochasticValue
+}
+
+// NewStochastic creates a stochastic oscillator; the standard slow stochastic periods are 14, 3 and 3.
+func NewStochastic(kPeriod, kSmoothing, dPeriod int) (*Stochastic, error) {
+	if err := validatePeriod("stochastic %K", kPeriod); err != nil {
+		return nil, err
+	}
+
+	smoothK, err := NewSMA(kSmoothing)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := NewSMA(dPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stochastic{
+		highs:   newWindow(kPeriod),
+		lows:    newWindow(kPeriod),
+		smoothK: smoothK,
+		d:       d,
+		value:   StochasticValue{K: math.NaN(), D: math.NaN()},
+	}, nil
+}
+
+func (s *Stochastic) Update(b stock.Bar) StochasticValue {
+	h, l := b.HighLow()
+	_, c := b.OpenClose()
+	s.highs.add(h)
+	s.lows.add(l)
+	if !s.highs.full {
+		return s.value
+	}
+
+	highest, _ := s.highs.maxMin()
+	_, lowest := s.lows.maxMin()
+
+	rawK := 50.0
+	if highest > lowest {
+		rawK = (c - lowest) / (highest - lowest) * 100
+	}
+
+	s.value.K = s.smoothK.add(rawK)
+	if !math.IsNaN(s.value.K) {
+		s.value.D = s.d.add(s.value.K)
+	}
+
+	return s.value
+}
+
+func (s *Stochastic) Value() StochasticValue {
+	return s.value
+}
+
+func StochasticSeries(bars []stock.Bar, kPeriod, kSmoothing, dPeriod int) ([]StochasticValue, error) {
+	s, err := NewStochastic(kPeriod, kSmoothing, dPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]StochasticValue, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, s.Update(b))
+	}
+
+	return values, nil
+}
@@ -0,0 +1,98 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// OBV is the on-balance volume, starting from 0 at the first bar.
+type OBV struct {
+	started   bool
+	prevClose float64
+	value     float64
+}
+
+func NewOBV() *OBV {
+	return &OBV{}
+}
+
+func (o *OBV) Update(b stock.Bar) float64 {
+	_, c := b.OpenClose()
+	if o.started {
+		switch {
+		case c > o.prevClose:
+			o.value += float64(b.Volume())
+		case c < o.prevClose:
+			o.value -= float64(b.Volume())
+		}
+	}
+	o.started = true
+	o.prevClose = c
+
+	return o.value
+}
+
+func (o *OBV) Value() float64 {
+	return o.value
+}
+
+func OBVSeries(bars []stock.Bar) []float64 {
+	o := NewOBV()
+
+	values := make([]float64, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, o.Update(b))
+	}
+
+	return values
+}
+
+// VWAP is the cumulative volume-weighted average price.
+// Each bar's own VWAP is used if known, otherwise its typical price.
+type VWAP struct {
+	sessionReset bool
+	day          int // Year and day of the current session, used to detect a new session.
+	pv           float64
+	volume       float64
+	value        float64
+}
+
+// NewVWAP creates a VWAP indicator. If sessionReset is true, as is usual for intraday bars,
+// the average restarts with the first bar of each trading day.
+func NewVWAP(sessionReset bool) *VWAP {
+	return &VWAP{sessionReset: sessionReset, value: math.NaN()}
+}
+
+func (v *VWAP) Update(b stock.Bar) float64 {
+	day := b.Start().Year()*1000 + b.Start().YearDay()
+	if v.sessionReset && day != v.day {
+		v.pv, v.volume = 0, 0
+		v.value = math.NaN()
+	}
+	v.day = day
+
+	vol := float64(b.Volume())
+	v.pv += b.AveragePrice() * vol
+	v.volume += vol
+	if v.volume > 0 {
+		v.value = v.pv / v.volume
+	}
+
+	return v.value
+}
+
+func (v *VWAP) Value() float64 {
+	return v.value
+}
+
+func VWAPSeries(bars []stock.Bar, sessionReset bool) []float64 {
+	v := NewVWAP(sessionReset)
+
+	values := make([]float64, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, v.Update(b))
+	}
+
+	return values
+}
@@ -0,0 +1,110 @@
+package integrity
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Gap is an opening price away from the previous session's close.
+type Gap struct {
+	Date           time.Time
+	PrevClose      float64
+	Open           float64
+	GapPct         float64 // Open relative to the previous close, in percent.
+	FilledSameDay  bool    // True if the session traded back to the previous close.
+	Filled         bool    // True if a later session (or the same one) traded back to the previous close.
+	SessionsToFill int     // Sessions after the gap until it was filled, 0 if filled the same day.
+}
+
+func (g Gap) String() string {
+	fill := "open"
+	switch {
+	case g.FilledSameDay:
+		fill = "filled same day"
+	case g.Filled:
+		fill = fmt.Sprintf("filled after %d sessions", g.SessionsToFill)
+	}
+
+	return fmt.Sprintf("%s %9.2f %9.2f %+7.2f%%  %s", g.Date.Format("2006-01-02"), g.PrevClose, g.Open, g.GapPct, fill)
+}
+
+func GapHeader() string {
+	return fmt.Sprintln("Date        PrevClose      Open     Gap")
+}
+
+// FindGaps returns the overnight gaps of at least minPct percent and whether each was filled.
+func FindGaps(prices []stock.DailyPrice, minPct float64) []Gap {
+	var gaps []Gap
+	for i := 1; i < len(prices); i++ {
+		_, prevClose := prices[i-1].OpenClose()
+		open, _ := prices[i].OpenClose()
+		gapPct := (open/prevClose - 1) * 100
+		if math.Abs(gapPct) < minPct {
+			continue
+		}
+
+		g := Gap{Date: prices[i].Date(), PrevClose: prevClose, Open: open, GapPct: gapPct}
+		for k := i; k < len(prices); k++ {
+			high, low := prices[k].HighLow()
+			if (gapPct > 0 && low <= prevClose) || (gapPct < 0 && high >= prevClose) {
+				g.Filled = true
+				g.FilledSameDay = k == i
+				g.SessionsToFill = k - i
+				break
+			}
+		}
+
+		gaps = append(gaps, g)
+	}
+
+	return gaps
+}
+
+// GapStats summarizes gaps and how often they were filled.
+type GapStats struct {
+	Count             int
+	Up                int
+	Down              int
+	FilledSameDay     int
+	Filled            int
+	AvgSessionsToFill float64 // Average over filled gaps.
+}
+
+func SummarizeGaps(gaps []Gap) (gs GapStats) {
+	total := 0
+	for _, g := range gaps {
+		gs.Count++
+		if g.GapPct > 0 {
+			gs.Up++
+		} else {
+			gs.Down++
+		}
+		if g.FilledSameDay {
+			gs.FilledSameDay++
+		}
+		if g.Filled {
+			gs.Filled++
+			total += g.SessionsToFill
+		}
+	}
+
+	if gs.Filled > 0 {
+		gs.AvgSessionsToFill = float64(total) / float64(gs.Filled)
+	}
+
+	return
+}
+
+func (gs GapStats) String() string {
+	if gs.Count == 0 {
+		return "no gaps"
+	}
+
+	return fmt.Sprintf("%d gaps (%d up, %d down), %.0f%% filled same day, %.0f%% filled, %.1f sessions to fill on average",
+		gs.Count, gs.Up, gs.Down,
+		float64(gs.FilledSameDay)/float64(gs.Count)*100, float64(gs.Filled)/float64(gs.Count)*100, gs.AvgSessionsToFill,
+	)
+}
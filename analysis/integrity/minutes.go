@@ -0,0 +1,78 @@
+package integrity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// MinuteRange is a run of consecutive missing minutes in a session.
+type MinuteRange struct {
+	Start time.Time // First missing minute.
+	End   time.Time // Last missing minute.
+}
+
+// Minutes returns the number of minutes in the range.
+func (mr MinuteRange) Minutes() int {
+	return int(mr.End.Sub(mr.Start).Minutes()) + 1
+}
+
+func (mr MinuteRange) String() string {
+	if mr.Minutes() == 1 {
+		return mr.Start.Format("2006-01-02 15:04")
+	}
+
+	return fmt.Sprintf("%s - %s (%d minutes)", mr.Start.Format("2006-01-02 15:04"), mr.End.Format("15:04"), mr.Minutes())
+}
+
+// sessionBounds returns the first and last minutes of the trading session on the day, allowing for early closes.
+func sessionBounds(day time.Time) (time.Time, time.Time) {
+	year, month, date := day.Date()
+	open := time.Date(year, month, date, stock.MarketOpenHour, stock.MarketOpenMinute, 0, 0, day.Location())
+	last := time.Date(year, month, date, stock.MarketCloseHour, stock.MarketCloseMinute, 0, 0, day.Location())
+	if markets.IsEarlyClose(day) {
+		last = time.Date(year, month, date, markets.EarlyCloseHour, 0, 0, 0, day.Location()).Add(-time.Minute)
+	}
+
+	return open, last
+}
+
+// MissingMinutes returns the minutes with no sale in each session that has at least one one minute sale.
+// Sessions with no sales at all are not reported here; they show up as missing sessions in the daily prices.
+// Thinly traded stocks may legitimately have minutes without any trades.
+func MissingMinutes(sales []stock.OneMinSale) []MinuteRange {
+	have := make(map[time.Time]bool, len(sales))
+	var days []time.Time
+	for _, oms := range sales {
+		t := oms.StartTime()
+		have[t] = true
+
+		day := time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, t.Location())
+		if len(days) == 0 || !days[len(days)-1].Equal(day) {
+			days = append(days, day)
+		}
+	}
+
+	var missing []MinuteRange
+	for _, day := range days {
+		open, last := sessionBounds(day)
+		var run *MinuteRange
+		for t := open; !t.After(last); t = t.Add(time.Minute) {
+			if have[t] {
+				run = nil
+				continue
+			}
+
+			if run == nil {
+				missing = append(missing, MinuteRange{Start: t, End: t})
+				run = &missing[len(missing)-1]
+			} else {
+				run.End = t
+			}
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,73 @@
+package integrity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Report holds the integrity check results for one stock.
+type Report struct {
+	Symbol                string
+	Start                 time.Time // First date checked for missing sessions.
+	End                   time.Time // Last date checked for missing sessions.
+	MissingSessions       []time.Time
+	Unexpected            []time.Time // Daily prices on days the markets were closed.
+	MissingMinuteSessions []time.Time // Days without one minute sales between the first and last days with them.
+	MissingMinutes        []MinuteRange
+	Gaps                  []Gap
+	GapStats              GapStats
+}
+
+// Check compares daily prices and one minute sales with the trading calendar and finds gaps of at least minGapPct percent.
+// Missing sessions are checked from the first daily price through end.
+func Check(symbol string, prices []stock.DailyPrice, sales []stock.OneMinSale, end time.Time, minGapPct float64) (r Report) {
+	r.Symbol = symbol
+	r.End = end
+	if len(prices) > 0 {
+		r.Start = prices[0].Date()
+		r.MissingSessions = MissingSessions(prices, r.Start, end)
+	}
+	r.Unexpected = UnexpectedSessions(prices)
+	r.MissingMinuteSessions = MissingMinuteSessions(sales)
+	r.MissingMinutes = MissingMinutes(sales)
+	r.Gaps = FindGaps(prices, minGapPct)
+	r.GapStats = SummarizeGaps(r.Gaps)
+
+	return
+}
+
+// OK returns true if no sessions or minutes are missing and there are no unexpected sessions.
+func (r Report) OK() bool {
+	return len(r.MissingSessions) == 0 && len(r.Unexpected) == 0 &&
+		len(r.MissingMinuteSessions) == 0 && len(r.MissingMinutes) == 0
+}
+
+func (r Report) String() string {
+	sb := strings.Builder{}
+
+	minutes := 0
+	for _, mr := range r.MissingMinutes {
+		minutes += mr.Minutes()
+	}
+
+	sb.WriteString(fmt.Sprintf("%s: %d missing sessions, %d unexpected sessions, %d sessions and %d minutes missing one minute sales; %s\n",
+		r.Symbol, len(r.MissingSessions), len(r.Unexpected), len(r.MissingMinuteSessions), minutes, r.GapStats))
+
+	for _, day := range r.MissingSessions {
+		sb.WriteString(fmt.Sprintf("  missing session    %s\n", day.Format("2006-01-02")))
+	}
+	for _, day := range r.Unexpected {
+		sb.WriteString(fmt.Sprintf("  unexpected session %s\n", day.Format("2006-01-02")))
+	}
+	for _, day := range r.MissingMinuteSessions {
+		sb.WriteString(fmt.Sprintf("  missing minutes    %s (whole session)\n", day.Format("2006-01-02")))
+	}
+	for _, mr := range r.MissingMinutes {
+		sb.WriteString(fmt.Sprintf("  missing minutes    %s\n", mr))
+	}
+
+	return sb.String()
+}
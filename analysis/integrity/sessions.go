@@ -0,0 +1,58 @@
+// Package integrity checks stored price histories against the trading calendar.
+package integrity
+
+import (
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// MissingSessions returns the trading days from start through end with no daily price.
+func MissingSessions(prices []stock.DailyPrice, start, end time.Time) []time.Time {
+	have := make(map[string]bool, len(prices))
+	for _, dp := range prices {
+		have[dp.DateStr()] = true
+	}
+
+	return missingDays(have, start, end)
+}
+
+// MissingMinuteSessions returns the trading days between the first and last one minute sales with no sales.
+func MissingMinuteSessions(sales []stock.OneMinSale) []time.Time {
+	if len(sales) == 0 {
+		return nil
+	}
+
+	have := make(map[string]bool)
+	for _, oms := range sales {
+		have[oms.StartTime().Format("2006-01-02")] = true
+	}
+
+	return missingDays(have, sales[0].StartTime(), sales[len(sales)-1].StartTime())
+}
+
+// missingDays returns the trading days from start through end that are not in have, keyed by "YYYY-MM-DD".
+func missingDays(have map[string]bool, start, end time.Time) []time.Time {
+	var missing []time.Time
+	day := time.Date(start.Year(), start.Month(), start.Day(), 12, 0, 0, 0, time.UTC)
+	for ; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if markets.IsTradingDay(day) && !have[day.Format("2006-01-02")] {
+			missing = append(missing, day)
+		}
+	}
+
+	return missing
+}
+
+// UnexpectedSessions returns the dates of daily prices that are not trading days.
+func UnexpectedSessions(prices []stock.DailyPrice) []time.Time {
+	var unexpected []time.Time
+	for _, dp := range prices {
+		if !markets.IsTradingDay(dp.Date()) {
+			unexpected = append(unexpected, dp.Date())
+		}
+	}
+
+	return unexpected
+}
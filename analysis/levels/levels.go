@@ -0,0 +1,48 @@
+// Package levels computes support and resistance price levels.
+package levels
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tsilvers/realtime-securities/markets/quote"
+)
+
+// Level is a named support or resistance price.
+type Level struct {
+	Price float64
+	Label string
+}
+
+func (l Level) String() string {
+	return fmt.Sprintf("%9.2f  %s", l.Price, l.Label)
+}
+
+// Nearest returns the closest level at or below the price and the closest level above it.
+// Either is nil if there is no level on that side.
+func Nearest(levels []Level, price float64) (below, above *Level) {
+	sorted := make([]Level, len(levels))
+	copy(sorted, levels)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Price < sorted[j].Price })
+
+	i := sort.Search(len(sorted), func(i int) bool { return sorted[i].Price > price })
+	if i > 0 {
+		below = &sorted[i-1]
+	}
+	if i < len(sorted) {
+		above = &sorted[i]
+	}
+
+	return
+}
+
+// NearestToQuote returns the closest levels below and above the quote's last price.
+func NearestToQuote(levels []Level, q quote.Quote) (below, above *Level, err error) {
+	if q.Last() <= 0 {
+		return nil, nil, fmt.Errorf("quote has no last price; symbol: %s", q.Symbol())
+	}
+
+	below, above = Nearest(levels, q.Last())
+
+	return
+}
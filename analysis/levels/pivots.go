@@ -0,0 +1,85 @@
+package levels
+
+import (
+	"fmt"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Pivots holds pivot point levels computed from the previous session's prices.
+// Resistance and Support hold R1, R2, ... and S1, S2, ... in order.
+type Pivots struct {
+	Method     string
+	Pivot      float64
+	Resistance []float64
+	Support    []float64
+}
+
+// ClassicPivots computes floor trader pivot points.
+func ClassicPivots(prev stock.DailyPrice) Pivots {
+	h, l := prev.HighLow()
+	_, c := prev.OpenClose()
+	p := (h + l + c) / 3
+
+	return Pivots{
+		Method:     "Classic",
+		Pivot:      p,
+		Resistance: []float64{2*p - l, p + (h - l), h + 2*(p-l)},
+		Support:    []float64{2*p - h, p - (h - l), l - 2*(h-p)},
+	}
+}
+
+// FibonacciPivots computes pivot points spaced by Fibonacci ratios of the previous range.
+func FibonacciPivots(prev stock.DailyPrice) Pivots {
+	h, l := prev.HighLow()
+	_, c := prev.OpenClose()
+	p := (h + l + c) / 3
+	r := h - l
+
+	return Pivots{
+		Method:     "Fibonacci",
+		Pivot:      p,
+		Resistance: []float64{p + 0.382*r, p + 0.618*r, p + r},
+		Support:    []float64{p - 0.382*r, p - 0.618*r, p - r},
+	}
+}
+
+// CamarillaPivots computes Camarilla pivot points, which are centered on the previous close.
+func CamarillaPivots(prev stock.DailyPrice) Pivots {
+	h, l := prev.HighLow()
+	_, c := prev.OpenClose()
+	r := (h - l) * 1.1
+
+	return Pivots{
+		Method:     "Camarilla",
+		Pivot:      (h + l + c) / 3,
+		Resistance: []float64{c + r/12, c + r/6, c + r/4, c + r/2},
+		Support:    []float64{c - r/12, c - r/6, c - r/4, c - r/2},
+	}
+}
+
+// Levels returns the pivot and each support and resistance as labeled levels.
+func (p Pivots) Levels() []Level {
+	levels := []Level{{Price: p.Pivot, Label: p.Method + " P"}}
+	for i, r := range p.Resistance {
+		levels = append(levels, Level{Price: r, Label: fmt.Sprintf("%s R%d", p.Method, i+1)})
+	}
+	for i, s := range p.Support {
+		levels = append(levels, Level{Price: s, Label: fmt.Sprintf("%s S%d", p.Method, i+1)})
+	}
+
+	return levels
+}
+
+func (p Pivots) String() string {
+	str := fmt.Sprintf("%-10s", p.Method)
+	for i := len(p.Support) - 1; i >= 0; i-- {
+		str += fmt.Sprintf(" S%d %.2f", i+1, p.Support[i])
+	}
+	str += fmt.Sprintf("  P %.2f ", p.Pivot)
+	for i, r := range p.Resistance {
+		str += fmt.Sprintf(" R%d %.2f", i+1, r)
+	}
+
+	return str
+}
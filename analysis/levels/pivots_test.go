@@ -0,0 +1,86 @@
+package levels
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+func assertLevels(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s = %v, want %v", name, got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-4 {
+			t.Errorf("%s%d = %.4f, want %.4f", name, i+1, got[i], want[i])
+		}
+	}
+}
+
+// The previous session traded from 100 to 120 and closed at 115, so the pivot is 111.6667 and the range 20.
+func TestPivots(t *testing.T) {
+	prev, err := stock.NewDailyPrice(2024, 1, 2, 104, 115, 120, 100, 1000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		pivots              Pivots
+		pivot               float64
+		resistance, support []float64
+	}{
+		{
+			// R1 = 2P - L, R2 = P + (H - L), R3 = H + 2(P - L), and the reverse for support.
+			pivots:     ClassicPivots(prev),
+			pivot:      111.6667,
+			resistance: []float64{123.3333, 131.6667, 143.3333},
+			support:    []float64{103.3333, 91.6667, 83.3333},
+		},
+		{
+			// P plus and minus 38.2%, 61.8% and 100% of the range.
+			pivots:     FibonacciPivots(prev),
+			pivot:      111.6667,
+			resistance: []float64{119.3067, 124.0267, 131.6667},
+			support:    []float64{104.0267, 99.3067, 91.6667},
+		},
+		{
+			// The close plus and minus 1.1 times the range divided by 12, 6, 4 and 2.
+			pivots:     CamarillaPivots(prev),
+			pivot:      111.6667,
+			resistance: []float64{116.8333, 118.6667, 120.5, 126},
+			support:    []float64{113.1667, 111.3333, 109.5, 104},
+		},
+	} {
+		p := tc.pivots
+		if math.Abs(p.Pivot-tc.pivot) > 1e-4 {
+			t.Errorf("%s pivot = %.4f, want %.4f", p.Method, p.Pivot, tc.pivot)
+		}
+		assertLevels(t, p.Method+" R", p.Resistance, tc.resistance)
+		assertLevels(t, p.Method+" S", p.Support, tc.support)
+
+		if levels := p.Levels(); len(levels) != 1+len(tc.resistance)+len(tc.support) || levels[1].Label != p.Method+" R1" {
+			t.Errorf("%s levels = %v", p.Method, levels)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	levels := []Level{{Price: 110, Label: "R1"}, {Price: 100, Label: "P"}, {Price: 90, Label: "S1"}}
+
+	below, above := Nearest(levels, 105)
+	if below == nil || above == nil || below.Label != "P" || above.Label != "R1" {
+		t.Errorf("nearest to 105 = %v, %v, want P and R1", below, above)
+	}
+
+	// A level at the price is below it.
+	if below, above = Nearest(levels, 100); below.Label != "P" || above.Label != "R1" {
+		t.Errorf("nearest to 100 = %v, %v, want P and R1", below, above)
+	}
+
+	if below, above = Nearest(levels, 120); below.Label != "R1" || above != nil {
+		t.Errorf("nearest to 120 = %v, %v, want R1 and none", below, above)
+	}
+}
@@ -0,0 +1,104 @@
+package levels
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Swing is a bar whose high (or low) is beyond the highs (or lows) of the bars on both sides of it.
+type Swing struct {
+	Index int
+	Time  time.Time
+	Price float64
+	High  bool // True for a swing high, false for a swing low.
+}
+
+// Swings finds swing highs and lows that exceed the strength bars on each side.
+func Swings(bars []stock.Bar, strength int) ([]Swing, error) {
+	if strength < 1 {
+		return nil, fmt.Errorf("invalid swing strength: %d", strength)
+	}
+
+	var swings []Swing
+	for i := strength; i < len(bars)-strength; i++ {
+		high, low := bars[i].HighLow()
+		isHigh, isLow := true, true
+		for k := i - strength; k <= i+strength; k++ {
+			if k == i {
+				continue
+			}
+			h, l := bars[k].HighLow()
+			isHigh = isHigh && high > h
+			isLow = isLow && low < l
+		}
+
+		if isHigh {
+			swings = append(swings, Swing{Index: i, Time: bars[i].Start(), Price: high, High: true})
+		}
+		if isLow {
+			swings = append(swings, Swing{Index: i, Time: bars[i].Start(), Price: low, High: false})
+		}
+	}
+
+	return swings, nil
+}
+
+// Zone is a price range where several swings turned.
+type Zone struct {
+	Low     float64
+	High    float64
+	Price   float64 // Average price of the swings in the zone.
+	Touches int     // Number of swings in the zone.
+	Last    time.Time
+}
+
+// Zones clusters swing prices lying within tolerancePct percent of the zone's average price,
+// keeping zones with at least minTouches swings, strongest first.
+func Zones(swings []Swing, tolerancePct float64, minTouches int) []Zone {
+	sorted := make([]Swing, len(swings))
+	copy(sorted, swings)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Price < sorted[j].Price })
+
+	var zones []Zone
+	var sum float64
+	for i, sw := range sorted {
+		if i > 0 {
+			z := &zones[len(zones)-1]
+			if sw.Price-z.Price <= z.Price*tolerancePct/100 {
+				sum += sw.Price
+				z.Touches++
+				z.High = sw.Price
+				z.Price = sum / float64(z.Touches)
+				if sw.Time.After(z.Last) {
+					z.Last = sw.Time
+				}
+				continue
+			}
+		}
+
+		zones = append(zones, Zone{Low: sw.Price, High: sw.Price, Price: sw.Price, Touches: 1, Last: sw.Time})
+		sum = sw.Price
+	}
+
+	strong := make([]Zone, 0, len(zones))
+	for _, z := range zones {
+		if z.Touches >= minTouches {
+			strong = append(strong, z)
+		}
+	}
+	sort.SliceStable(strong, func(i, j int) bool { return strong[i].Touches > strong[j].Touches })
+
+	return strong
+}
+
+func (z Zone) Level() Level {
+	return Level{Price: z.Price, Label: fmt.Sprintf("Zone %.2f-%.2f (%d touches)", z.Low, z.High, z.Touches)}
+}
+
+func (z Zone) String() string {
+	return fmt.Sprintf("%9.2f  %9.2f - %9.2f  %3d touches, last %s",
+		z.Price, z.Low, z.High, z.Touches, z.Last.Format("2006-01-02"))
+}
@@ -0,0 +1,89 @@
+package levels
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// swingBars have swing highs at 110 and 111 (bars 2 and 7) and swing lows at 95 and 95.5 (bars 4 and 9)
+// beyond the two bars on each side.
+var swingBars = [][2]float64{
+	{103, 99}, {104, 100}, {110, 101}, {105, 98}, {102, 95}, {106, 97},
+	{109.5, 99}, {111, 100}, {104, 96}, {103, 95.5}, {105, 97}, {106, 98},
+}
+
+func testBars(t *testing.T, highLows [][2]float64) []stock.Bar {
+	t.Helper()
+
+	bars := make([]stock.Bar, 0, len(highLows))
+	for i, hl := range highLows {
+		date := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+		mid := (hl[0] + hl[1]) / 2
+		b, err := stock.NewBar(date, date, mid, mid, hl[0], hl[1], 1000, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bars = append(bars, b)
+	}
+
+	return bars
+}
+
+func TestSwings(t *testing.T) {
+	bars := testBars(t, swingBars)
+	swings, err := Swings(bars, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Swing{
+		{Index: 2, Price: 110, High: true},
+		{Index: 4, Price: 95},
+		{Index: 7, Price: 111, High: true},
+		{Index: 9, Price: 95.5},
+	}
+	if len(swings) != len(want) {
+		t.Fatalf("swings = %v, want %v", swings, want)
+	}
+	for i, sw := range swings {
+		want[i].Time = bars[want[i].Index].Start()
+		if sw != want[i] {
+			t.Errorf("swing %d = %+v, want %+v", i, sw, want[i])
+		}
+	}
+
+	if _, err = Swings(bars, 0); err == nil {
+		t.Error("swings of strength 0, want an error")
+	}
+}
+
+// Within 1%, the lows and the highs each form a zone of two touches.
+func TestZones(t *testing.T) {
+	bars := testBars(t, swingBars)
+	swings, err := Swings(bars, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zones := Zones(swings, 1, 2)
+	if len(zones) != 2 {
+		t.Fatalf("zones = %v, want 2", zones)
+	}
+	for i, want := range []Zone{
+		{Low: 95, High: 95.5, Price: 95.25, Touches: 2, Last: bars[9].Start()},
+		{Low: 110, High: 111, Price: 110.5, Touches: 2, Last: bars[7].Start()},
+	} {
+		z := zones[i]
+		if z.Low != want.Low || z.High != want.High || math.Abs(z.Price-want.Price) > 1e-9 || z.Touches != want.Touches || !z.Last.Equal(want.Last) {
+			t.Errorf("zone %d = %s, want %s", i, z, want)
+		}
+	}
+
+	// 0.8% joins the lows but not the highs.
+	if zones = Zones(swings, 0.8, 2); len(zones) != 1 || zones[0].Price != 95.25 {
+		t.Errorf("zones within 0.8%% = %v, want only the lows", zones)
+	}
+}
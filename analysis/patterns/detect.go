@@ -0,0 +1,179 @@
+package patterns
+
+import (
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Detect finds candlestick patterns in the bars, in order of the bar each pattern ends on.
+// Reversal patterns (hammers, stars, engulfing and harami) are only reported after a trend
+// in the opposite direction, so they are not found in the first Config.TrendBars bars.
+func Detect(bars []stock.Bar, cfg Config) ([]Match, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	candles := make([]candle, 0, len(bars))
+	for _, b := range bars {
+		candles = append(candles, newCandle(b))
+	}
+
+	d := detector{cfg: cfg, candles: candles}
+	for i := range candles {
+		d.single(i)
+		if i >= 1 {
+			d.double(i)
+		}
+		if i >= 2 {
+			d.triple(i)
+		}
+	}
+
+	return d.matches, nil
+}
+
+type detector struct {
+	cfg     Config
+	candles []candle
+	matches []Match
+}
+
+func (d *detector) add(p Pattern, i int, strength float64) {
+	d.matches = append(d.matches, Match{Pattern: p, Index: i, Strength: clamp(strength)})
+}
+
+// trend returns 1 for an uptrend, -1 for a downtrend and 0 for no trend or too little history,
+// measured over the TrendBars closes ending at bar end.
+func (d *detector) trend(end int) int {
+	start := end - d.cfg.TrendBars
+	if start < 0 || end < 0 {
+		return 0
+	}
+
+	change := (d.candles[end].close/d.candles[start].close - 1) * 100
+	switch {
+	case change >= d.cfg.TrendPercent:
+		return 1
+	case change <= -d.cfg.TrendPercent:
+		return -1
+	}
+
+	return 0
+}
+
+// single recognizes one bar patterns ending at bar i.
+func (d *detector) single(i int) {
+	c := d.candles[i]
+	if c.rng == 0 {
+		return
+	}
+
+	// A doji with one long shadow is also checked below as a hammer-shaped bar.
+	if c.bodyFrac() <= d.cfg.DojiBody {
+		d.add(Doji, i, 1-c.bodyFrac()/d.cfg.DojiBody)
+	}
+
+	if c.bodyFrac() > d.cfg.SmallBody {
+		return
+	}
+
+	trend := d.trend(i - 1)
+
+	// Long lower shadow.
+	if c.lower >= d.cfg.LongShadow*c.body && c.upper <= d.cfg.ShortShadow*c.rng {
+		strength := (c.lower/c.rng - 0.5) / 0.5
+		switch trend {
+		case -1:
+			d.add(Hammer, i, strength)
+		case 1:
+			d.add(HangingMan, i, strength)
+		}
+	}
+
+	// Long upper shadow.
+	if c.upper >= d.cfg.LongShadow*c.body && c.lower <= d.cfg.ShortShadow*c.rng {
+		strength := (c.upper/c.rng - 0.5) / 0.5
+		switch trend {
+		case -1:
+			d.add(InvertedHammer, i, strength)
+		case 1:
+			d.add(ShootingStar, i, strength)
+		}
+	}
+}
+
+// double recognizes two bar patterns ending at bar i.
+func (d *detector) double(i int) {
+	prev, c := d.candles[i-1], d.candles[i]
+	if prev.body == 0 || c.body == 0 {
+		return
+	}
+
+	trend := d.trend(i - 2)
+
+	// Engulfing: the body engulfs the previous, opposite colored body.
+	if c.bodyTop() >= prev.bodyTop() && c.bodyBottom() <= prev.bodyBottom() && c.body > prev.body {
+		strength := 1 - prev.body/c.body
+		if trend == -1 && prev.down() && c.up() {
+			d.add(BullishEngulfing, i, strength)
+		}
+		if trend == 1 && prev.up() && c.down() {
+			d.add(BearishEngulfing, i, strength)
+		}
+	}
+
+	// Harami: a small body within the previous long, opposite colored body.
+	if prev.bodyFrac() >= d.cfg.LongBody && c.bodyTop() <= prev.bodyTop() && c.bodyBottom() >= prev.bodyBottom() &&
+		c.body < prev.body {
+		strength := 1 - c.body/prev.body
+		if trend == -1 && prev.down() && !c.down() {
+			d.add(BullishHarami, i, strength)
+		}
+		if trend == 1 && prev.up() && !c.up() {
+			d.add(BearishHarami, i, strength)
+		}
+	}
+}
+
+// triple recognizes three bar patterns ending at bar i.
+func (d *detector) triple(i int) {
+	first, star, last := d.candles[i-2], d.candles[i-1], d.candles[i]
+	trend := d.trend(i - 3)
+
+	// Stars: a long bar, a small bodied bar beyond its body, and a long bar closing well into the first.
+	if first.bodyFrac() >= d.cfg.LongBody && star.bodyFrac() <= d.cfg.SmallBody && last.bodyFrac() >= d.cfg.LongBody {
+		if trend == -1 && first.down() && last.up() && star.bodyTop() <= first.close && last.close > first.midpoint() {
+			d.add(MorningStar, i, (last.close-first.midpoint())/(first.open-first.midpoint()))
+		}
+		if trend == 1 && first.up() && last.down() && star.bodyBottom() >= first.close && last.close < first.midpoint() {
+			d.add(EveningStar, i, (first.midpoint()-last.close)/(first.midpoint()-first.open))
+		}
+	}
+
+	// Three long bars in the same direction, each opening within the previous body.
+	bars := d.candles[i-2 : i+1]
+	soldiers, crows := true, true
+	bodySum := 0.0
+	for k, c := range bars {
+		bodySum += c.bodyFrac()
+		long := c.bodyFrac() >= d.cfg.LongBody
+		soldiers = soldiers && long && c.up() && c.upper <= d.cfg.ShortShadow*c.rng
+		crows = crows && long && c.down() && c.lower <= d.cfg.ShortShadow*c.rng
+		if k > 0 {
+			p := bars[k-1]
+			within := c.open >= p.bodyBottom() && c.open <= p.bodyTop()
+			soldiers = soldiers && within && c.close > p.close
+			crows = crows && within && c.close < p.close
+		}
+	}
+
+	strength := (bodySum/3 - d.cfg.LongBody) / (1 - d.cfg.LongBody)
+	if d.cfg.LongBody == 1 {
+		strength = 1
+	}
+	if soldiers {
+		d.add(ThreeWhiteSoldiers, i, strength)
+	}
+	if crows {
+		d.add(ThreeBlackCrows, i, strength)
+	}
+}
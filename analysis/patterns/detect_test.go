@@ -0,0 +1,170 @@
+package patterns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// ohlc is a bar's open, close, high and low.
+type ohlc [4]float64
+
+// downtrend is six bars closing from 110 down to 100, a 9% decline over the default five trend bars.
+// Their bodies are half their ranges, too large for stars and hammers and too small for long bars,
+// and do not overlap, so they form no patterns.
+var downtrend = []ohlc{
+	{111, 110, 111.5, 109.5},
+	{109, 108, 109.5, 107.5},
+	{107, 106, 107.5, 105.5},
+	{105, 104, 105.5, 103.5},
+	{103, 102, 103.5, 101.5},
+	{101, 100, 101.5, 99.5},
+}
+
+// mirror reflects the bars about a price of 100, turning bullish patterns into bearish ones.
+func mirror(bars []ohlc) []ohlc {
+	mirrored := make([]ohlc, len(bars))
+	for i, b := range bars {
+		mirrored[i] = ohlc{200 - b[0], 200 - b[1], 200 - b[3], 200 - b[2]}
+	}
+
+	return mirrored
+}
+
+func testBars(t *testing.T, bars []ohlc) []stock.Bar {
+	t.Helper()
+
+	result := make([]stock.Bar, 0, len(bars))
+	for i, b := range bars {
+		date := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+		bar, err := stock.NewBar(date, date, b[0], b[1], b[2], b[3], 1000, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, bar)
+	}
+
+	return result
+}
+
+// detect returns the matches of the pattern after the trend bars.
+func detect(t *testing.T, trend, bars []ohlc, p Pattern) []Match {
+	t.Helper()
+
+	matches, err := Detect(testBars(t, append(append([]ohlc{}, trend...), bars...)), DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found []Match
+	for _, m := range matches {
+		if m.Pattern == p {
+			found = append(found, m)
+		}
+	}
+
+	return found
+}
+
+// Each bullish pattern follows a downtrend, and the mirror image of its bars after an uptrend is the bearish pattern.
+// The negative bars miss the pattern by one measure.
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		bullish, bearish Pattern
+		bars, negative   []ohlc
+	}{
+		{
+			// A long lower shadow, and one with a long upper shadow as well.
+			bullish:  Hammer,
+			bearish:  ShootingStar,
+			bars:     []ohlc{{99.6, 100, 100.1, 98}},
+			negative: []ohlc{{99.6, 100, 101, 98}},
+		},
+		{
+			// A long upper shadow, and one with a long lower shadow as well.
+			bullish:  InvertedHammer,
+			bearish:  HangingMan,
+			bars:     []ohlc{{100, 100.4, 102, 99.9}},
+			negative: []ohlc{{100, 100.4, 102, 99}},
+		},
+		{
+			// An up body engulfing the previous down body, and one opening within it.
+			bullish:  BullishEngulfing,
+			bearish:  BearishEngulfing,
+			bars:     []ohlc{{100.5, 99.5, 100.7, 99.3}, {99.3, 100.8, 101, 99.1}},
+			negative: []ohlc{{100.5, 99.5, 100.7, 99.3}, {99.6, 100.8, 101, 99.4}},
+		},
+		{
+			// A small up body within the previous long down body, and one closing above it.
+			bullish:  BullishHarami,
+			bearish:  BearishHarami,
+			bars:     []ohlc{{101.5, 99.5, 101.7, 99.3}, {100.2, 100.6, 100.8, 100}},
+			negative: []ohlc{{101.5, 99.5, 101.7, 99.3}, {100.2, 101.8, 102, 100}},
+		},
+		{
+			// A star below a long down bar, then a long up bar closing above its midpoint, and one closing below it.
+			bullish:  MorningStar,
+			bearish:  EveningStar,
+			bars:     []ohlc{{102, 100, 102.2, 99.8}, {99.5, 99.7, 99.9, 99.3}, {99.8, 101.6, 101.8, 99.6}},
+			negative: []ohlc{{102, 100, 102.2, 99.8}, {99.5, 99.7, 99.9, 99.3}, {98.9, 100.6, 100.8, 98.7}},
+		},
+		{
+			// Three long up bars each opening within the previous body, and a third opening above it.
+			bullish:  ThreeWhiteSoldiers,
+			bearish:  ThreeBlackCrows,
+			bars:     []ohlc{{100, 101.8, 101.9, 99.8}, {100.9, 102.7, 102.8, 100.7}, {101.8, 103.6, 103.7, 101.6}},
+			negative: []ohlc{{100, 101.8, 101.9, 99.8}, {100.9, 102.7, 102.8, 100.7}, {103, 104.8, 104.9, 102.8}},
+		},
+	} {
+		for _, c := range []struct {
+			p                     Pattern
+			trend, bars, negative []ohlc
+		}{
+			{tc.bullish, downtrend, tc.bars, tc.negative},
+			{tc.bearish, mirror(downtrend), mirror(tc.bars), mirror(tc.negative)},
+		} {
+			matches := detect(t, c.trend, c.bars, c.p)
+			last := len(c.trend) + len(c.bars) - 1
+			if len(matches) != 1 || matches[0].Index != last {
+				t.Errorf("%s: matches %v, want one ending at bar %d", c.p, matches, last)
+			} else if s := matches[0].Strength; s <= 0 || s > 1 {
+				t.Errorf("%s: strength %g, want between 0 and 1", c.p, s)
+			}
+
+			if matches = detect(t, c.trend, c.negative, c.p); len(matches) > 0 {
+				t.Errorf("%s: negative bars matched %v", c.p, matches)
+			}
+		}
+	}
+}
+
+// Reversal patterns are only reported after a trend in the opposite direction.
+func TestDetectNeedsTrend(t *testing.T) {
+	hammer := []ohlc{{99.6, 100, 100.1, 98}}
+	if matches := detect(t, mirror(downtrend), hammer, Hammer); len(matches) > 0 {
+		t.Errorf("hammer after an uptrend matched %v", matches)
+	}
+	if matches := detect(t, nil, hammer, Hammer); len(matches) > 0 {
+		t.Errorf("hammer without a trend matched %v", matches)
+	}
+}
+
+func TestDetectDoji(t *testing.T) {
+	matches := detect(t, nil, []ohlc{{100, 100.05, 101, 99}}, Doji)
+	if len(matches) != 1 || matches[0].Strength <= 0.7 {
+		t.Errorf("doji matches %v, want one of strength 75%%", matches)
+	}
+
+	if matches = detect(t, nil, []ohlc{{100, 100.5, 101, 99}}, Doji); len(matches) > 0 {
+		t.Errorf("a body of a quarter of the range matched %v", matches)
+	}
+}
+
+func TestDetectConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TrendBars = 0
+	if _, err := Detect(nil, cfg); err == nil {
+		t.Error("detected with no trend bars, want an error")
+	}
+}
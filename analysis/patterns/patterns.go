@@ -0,0 +1,182 @@
+// Package patterns recognizes candlestick patterns in stock price bars.
+package patterns
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+const (
+	Doji Pattern = iota
+	Hammer
+	HangingMan
+	InvertedHammer
+	ShootingStar
+	BullishEngulfing
+	BearishEngulfing
+	BullishHarami
+	BearishHarami
+	MorningStar
+	EveningStar
+	ThreeWhiteSoldiers
+	ThreeBlackCrows
+)
+
+// Pattern identifies a candlestick pattern.
+type Pattern int
+
+var patternNames = map[Pattern]string{
+	Doji:               "Doji",
+	Hammer:             "Hammer",
+	HangingMan:         "Hanging Man",
+	InvertedHammer:     "Inverted Hammer",
+	ShootingStar:       "Shooting Star",
+	BullishEngulfing:   "Bullish Engulfing",
+	BearishEngulfing:   "Bearish Engulfing",
+	BullishHarami:      "Bullish Harami",
+	BearishHarami:      "Bearish Harami",
+	MorningStar:        "Morning Star",
+	EveningStar:        "Evening Star",
+	ThreeWhiteSoldiers: "Three White Soldiers",
+	ThreeBlackCrows:    "Three Black Crows",
+}
+
+func (p Pattern) String() string {
+	if name, ok := patternNames[p]; ok {
+		return name
+	}
+
+	return "Unknown"
+}
+
+// Bullish returns 1 for bullish reversal or continuation patterns, -1 for bearish ones and 0 for doji.
+func (p Pattern) Bullish() int {
+	switch p {
+	case Doji:
+		return 0
+	case Hammer, InvertedHammer, BullishEngulfing, BullishHarami, MorningStar, ThreeWhiteSoldiers:
+		return 1
+	}
+
+	return -1
+}
+
+// Config holds the thresholds used to recognize patterns. Sizes are fractions of the bar's high-low range.
+type Config struct {
+	DojiBody     float64 // Largest body of a doji.
+	SmallBody    float64 // Largest body of a star or of a hammer-shaped bar.
+	LongBody     float64 // Smallest body of a long bar, as used by stars, soldiers and crows.
+	LongShadow   float64 // Smallest ratio of the long shadow to the body of a hammer-shaped bar.
+	ShortShadow  float64 // Largest opposite shadow of a hammer-shaped bar.
+	TrendBars    int     // Bars before the pattern used to find the prior trend.
+	TrendPercent float64 // Smallest close-to-close change over TrendBars, in percent, that counts as a trend.
+}
+
+// DefaultConfig returns commonly used thresholds.
+func DefaultConfig() Config {
+	return Config{
+		DojiBody:     0.1,
+		SmallBody:    0.35,
+		LongBody:     0.6,
+		LongShadow:   2,
+		ShortShadow:  0.15,
+		TrendBars:    5,
+		TrendPercent: 1,
+	}
+}
+
+func (c Config) Validate() error {
+	if c.DojiBody <= 0 || c.DojiBody >= 1 {
+		return fmt.Errorf("invalid doji body size: %g", c.DojiBody)
+	}
+
+	if c.SmallBody <= 0 || c.SmallBody >= 1 {
+		return fmt.Errorf("invalid small body size: %g", c.SmallBody)
+	}
+
+	if c.LongBody <= 0 || c.LongBody > 1 {
+		return fmt.Errorf("invalid long body size: %g", c.LongBody)
+	}
+
+	if c.LongShadow <= 0 {
+		return fmt.Errorf("invalid long shadow ratio: %g", c.LongShadow)
+	}
+
+	if c.ShortShadow < 0 || c.ShortShadow >= 1 {
+		return fmt.Errorf("invalid short shadow size: %g", c.ShortShadow)
+	}
+
+	if c.TrendBars < 1 {
+		return fmt.Errorf("invalid trend bars: %d", c.TrendBars)
+	}
+
+	return nil
+}
+
+// Match is a pattern found in a series of bars.
+type Match struct {
+	Pattern  Pattern
+	Index    int     // Index of the last bar of the pattern.
+	Strength float64 // From 0 (barely meets the thresholds) to 1 (textbook example).
+}
+
+func (m Match) String() string {
+	return fmt.Sprintf("%s (%.0f%%)", m.Pattern, m.Strength*100)
+}
+
+// candle holds the measurements of a bar used to recognize patterns.
+type candle struct {
+	open, close, high, low float64
+	body                   float64 // Absolute size of the body.
+	rng                    float64 // High less low.
+	upper                  float64 // Upper shadow.
+	lower                  float64 // Lower shadow.
+}
+
+func newCandle(b stock.Bar) candle {
+	c := candle{}
+	c.open, c.close = b.OpenClose()
+	c.high, c.low = b.HighLow()
+	c.body = math.Abs(c.close - c.open)
+	c.rng = c.high - c.low
+	c.upper = c.high - math.Max(c.open, c.close)
+	c.lower = math.Min(c.open, c.close) - c.low
+
+	return c
+}
+
+func (c candle) up() bool {
+	return c.close > c.open
+}
+
+func (c candle) down() bool {
+	return c.close < c.open
+}
+
+// bodyFrac returns the body as a fraction of the range.
+func (c candle) bodyFrac() float64 {
+	if c.rng == 0 {
+		return 0
+	}
+
+	return c.body / c.rng
+}
+
+func (c candle) bodyTop() float64 {
+	return math.Max(c.open, c.close)
+}
+
+func (c candle) bodyBottom() float64 {
+	return math.Min(c.open, c.close)
+}
+
+func (c candle) midpoint() float64 {
+	return (c.open + c.close) / 2
+}
+
+// clamp limits a strength to the range 0 to 1.
+func clamp(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
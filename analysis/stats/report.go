@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Summary holds the return and risk statistics of a stock's daily prices.
+// Fractions are shown as percentages in the report.
+type Summary struct {
+	Symbol     string
+	Days       int
+	Return     float64 // Annualized return.
+	Volatility float64 // Annualized volatility of simple returns.
+	Sharpe     float64
+	Sortino    float64
+	Drawdown   Drawdown
+	Calmar     float64
+	Skew       float64 // Of log returns.
+	Kurtosis   float64 // Excess kurtosis of log returns.
+	Beta       float64 // NaN if no benchmark was given.
+	Alpha      float64 // NaN if no benchmark was given.
+}
+
+// Summarize computes the statistics of the daily prices.
+// Beta and alpha are computed against the benchmark's prices if any are given.
+func Summarize(symbol string, prices, benchmark []stock.DailyPrice, riskFree float64) (s Summary, err error) {
+	if len(prices) < 3 {
+		return s, fmt.Errorf("not enough daily prices for %s: %d", symbol, len(prices))
+	}
+
+	returns := SimpleReturns(prices)
+	logReturns := LogReturns(prices)
+
+	s.Symbol = symbol
+	s.Days = len(prices)
+	s.Return = AnnualizedReturn(prices)
+	s.Volatility = AnnualizedVolatility(returns)
+	s.Sharpe = Sharpe(returns, riskFree)
+	s.Sortino = Sortino(returns, riskFree)
+	s.Drawdown = MaxDrawdown(prices)
+	s.Calmar = Calmar(prices)
+	s.Skew = Skew(logReturns)
+	s.Kurtosis = Kurtosis(logReturns)
+
+	if len(benchmark) == 0 {
+		s.Beta, s.Alpha = math.NaN(), math.NaN()
+		return
+	}
+	if s.Beta, s.Alpha, err = BetaAlpha(prices, benchmark, riskFree); err != nil {
+		err = fmt.Errorf("beta for %s: %w", symbol, err)
+	}
+
+	return
+}
+
+func SummaryHeader() string {
+	return fmt.Sprintln("Symbol  Days  Return     Vol  Sharpe Sortino   MaxDD  Calmar   Skew   Kurt   Beta   Alpha   DD Peak    DD Trough")
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%-6s %5d %6.2f%% %6.2f%% %7.2f %7.2f %6.2f%% %7.2f %6.2f %6.2f %6.2f %6.2f%%  %s %s",
+		s.Symbol, s.Days, s.Return*100, s.Volatility*100, s.Sharpe, s.Sortino, s.Drawdown.Depth*100, s.Calmar,
+		s.Skew, s.Kurtosis, s.Beta, s.Alpha*100, s.Drawdown.Peak.Format("2006-01-02"), s.Drawdown.Trough.Format("2006-01-02"),
+	)
+}
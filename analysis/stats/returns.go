@@ -0,0 +1,123 @@
+// Package stats computes return and risk statistics from daily stock prices.
+package stats
+
+import (
+	"math"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+const TradingDaysPerYear = 252
+
+// SimpleReturns returns the close-to-close percentage changes as fractions (0.01 = 1%).
+func SimpleReturns(prices []stock.DailyPrice) []float64 {
+	returns := make([]float64, 0, len(prices))
+	for i := 1; i < len(prices); i++ {
+		_, prev := prices[i-1].OpenClose()
+		_, c := prices[i].OpenClose()
+		returns = append(returns, c/prev-1)
+	}
+
+	return returns
+}
+
+// LogReturns returns the natural log of each close-to-close price ratio.
+func LogReturns(prices []stock.DailyPrice) []float64 {
+	returns := make([]float64, 0, len(prices))
+	for i := 1; i < len(prices); i++ {
+		_, prev := prices[i-1].OpenClose()
+		_, c := prices[i].OpenClose()
+		returns = append(returns, math.Log(c/prev))
+	}
+
+	return returns
+}
+
+// AnnualizedReturn returns the compound annual growth rate from the first to the last close,
+// counting TradingDaysPerYear sessions per year.
+func AnnualizedReturn(prices []stock.DailyPrice) float64 {
+	if len(prices) < 2 {
+		return math.NaN()
+	}
+
+	_, first := prices[0].OpenClose()
+	_, last := prices[len(prices)-1].OpenClose()
+	years := float64(len(prices)-1) / TradingDaysPerYear
+
+	return math.Pow(last/first, 1/years) - 1
+}
+
+func Mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+
+	return sum / float64(len(xs))
+}
+
+// StdDev returns the sample standard deviation.
+func StdDev(xs []float64) float64 {
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+
+	mean := Mean(xs)
+	sum := 0.0
+	for _, x := range xs {
+		sum += (x - mean) * (x - mean)
+	}
+
+	return math.Sqrt(sum / float64(len(xs)-1))
+}
+
+// Skew returns the sample skewness (adjusted Fisher-Pearson coefficient).
+func Skew(xs []float64) float64 {
+	n := float64(len(xs))
+	if n < 3 {
+		return math.NaN()
+	}
+
+	m2, m3, _ := moments(xs)
+	if m2 == 0 {
+		return 0
+	}
+
+	return math.Sqrt(n*(n-1)) / (n - 2) * m3 / math.Pow(m2, 1.5)
+}
+
+// Kurtosis returns the sample excess kurtosis, 0 for a normal distribution.
+func Kurtosis(xs []float64) float64 {
+	n := float64(len(xs))
+	if n < 4 {
+		return math.NaN()
+	}
+
+	m2, _, m4 := moments(xs)
+	if m2 == 0 {
+		return 0
+	}
+
+	g2 := m4/(m2*m2) - 3
+
+	return (n - 1) / ((n - 2) * (n - 3)) * ((n+1)*g2 + 6)
+}
+
+// moments returns the second, third and fourth central moments.
+func moments(xs []float64) (m2, m3, m4 float64) {
+	mean := Mean(xs)
+	for _, x := range xs {
+		d := x - mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+
+	n := float64(len(xs))
+
+	return m2 / n, m3 / n, m4 / n
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// testPrices returns daily prices closing at the given prices on consecutive weekdays from January 2, 2024.
+func testPrices(t *testing.T, closes ...float64) []stock.DailyPrice {
+	t.Helper()
+
+	prices := make([]stock.DailyPrice, 0, len(closes))
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, c := range closes {
+		for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			date = date.AddDate(0, 0, 1)
+		}
+		dp, err := stock.NewDailyPrice(date.Year(), int(date.Month()), date.Day(), c, c, c, c, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prices = append(prices, dp)
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return prices
+}
+
+func assertNear(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+
+	if math.IsNaN(got) || math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %.6f, want %.6f", name, got, want)
+	}
+}
+
+func TestReturns(t *testing.T) {
+	prices := testPrices(t, 100, 110, 99, 118.8)
+
+	simple := SimpleReturns(prices)
+	logs := LogReturns(prices)
+	for i, want := range []float64{0.1, -0.1, 0.2} {
+		assertNear(t, "simple return", simple[i], want, 1e-12)
+		assertNear(t, "log return", logs[i], math.Log(1+want), 1e-12)
+	}
+
+	assertNear(t, "mean", Mean(simple), 0.2/3, 1e-12)
+	// Deviations from the mean are 1/30, -1/6 and 2/15.
+	assertNear(t, "standard deviation", StdDev(simple), math.Sqrt(0.07/3), 1e-12)
+}
+
+// Two years of sessions growing 21% in total is a 10% annual return.
+func TestAnnualizedReturn(t *testing.T) {
+	closes := make([]float64, 2*TradingDaysPerYear+1)
+	for i := range closes {
+		closes[i] = 100 * math.Pow(1.21, float64(i)/float64(2*TradingDaysPerYear))
+	}
+
+	assertNear(t, "annualized return", AnnualizedReturn(testPrices(t, closes...)), 0.1, 1e-9)
+}
+
+func TestMoments(t *testing.T) {
+	// Reference values as given by spreadsheet SKEW and KURT functions.
+	assertNear(t, "skew", Skew([]float64{1, 2, 3, 4, 10}), 1.2*math.Sqrt2, 1e-9)
+	assertNear(t, "symmetric skew", Skew([]float64{1, 2, 3, 4, 5}), 0, 1e-12)
+	assertNear(t, "kurtosis", Kurtosis([]float64{1, 2, 3, 4, 5}), -1.2, 1e-9)
+
+	if !math.IsNaN(Skew([]float64{1, 2})) || !math.IsNaN(Kurtosis([]float64{1, 2, 3})) {
+		t.Error("moments of too few values are not NaN")
+	}
+}
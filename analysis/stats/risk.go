@@ -0,0 +1,168 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// AnnualizedVolatility returns the standard deviation of daily returns scaled to a year.
+func AnnualizedVolatility(returns []float64) float64 {
+	return StdDev(returns) * math.Sqrt(TradingDaysPerYear)
+}
+
+// Sharpe returns the annualized Sharpe ratio of daily returns.
+// The risk-free rate is annual (0.02 = 2%).
+func Sharpe(returns []float64, riskFree float64) float64 {
+	excess := make([]float64, 0, len(returns))
+	for _, r := range returns {
+		excess = append(excess, r-riskFree/TradingDaysPerYear)
+	}
+
+	sd := StdDev(excess)
+	if sd == 0 {
+		return math.NaN()
+	}
+
+	return Mean(excess) / sd * math.Sqrt(TradingDaysPerYear)
+}
+
+// Sortino returns the annualized Sortino ratio of daily returns, using the downside deviation
+// below the daily risk-free rate. The risk-free rate is annual (0.02 = 2%).
+func Sortino(returns []float64, riskFree float64) float64 {
+	if len(returns) < 2 {
+		return math.NaN()
+	}
+
+	target := riskFree / TradingDaysPerYear
+	excess := 0.0
+	downside := 0.0
+	for _, r := range returns {
+		excess += r - target
+		if r < target {
+			downside += (r - target) * (r - target)
+		}
+	}
+
+	n := float64(len(returns))
+	if downside == 0 {
+		return math.NaN()
+	}
+
+	return (excess / n) / math.Sqrt(downside/n) * math.Sqrt(TradingDaysPerYear)
+}
+
+// Drawdown holds the largest peak-to-trough decline in closing prices.
+type Drawdown struct {
+	Depth    float64   // Decline from the peak as a negative fraction (-0.25 = 25% drawdown).
+	Peak     time.Time // Date of the peak close.
+	Trough   time.Time // Date of the lowest close after the peak.
+	Recovery time.Time // First date closing at or above the peak, zero if not recovered.
+}
+
+// MaxDrawdown returns the largest peak-to-trough decline in closing prices.
+func MaxDrawdown(prices []stock.DailyPrice) Drawdown {
+	dd := Drawdown{}
+	if len(prices) == 0 {
+		return dd
+	}
+
+	peakIdx := 0
+	_, peak := prices[0].OpenClose()
+	maxPeakIdx, maxTroughIdx := 0, 0
+	for i, dp := range prices {
+		_, c := dp.OpenClose()
+		if c > peak {
+			peak, peakIdx = c, i
+			continue
+		}
+
+		if depth := c/peak - 1; depth < dd.Depth {
+			dd.Depth = depth
+			maxPeakIdx, maxTroughIdx = peakIdx, i
+		}
+	}
+
+	dd.Peak = prices[maxPeakIdx].Date()
+	dd.Trough = prices[maxTroughIdx].Date()
+
+	_, peak = prices[maxPeakIdx].OpenClose()
+	for _, dp := range prices[maxTroughIdx:] {
+		if _, c := dp.OpenClose(); c >= peak && dd.Depth < 0 {
+			dd.Recovery = dp.Date()
+			break
+		}
+	}
+
+	return dd
+}
+
+func (dd Drawdown) String() string {
+	recovery := "not recovered"
+	if !dd.Recovery.IsZero() {
+		recovery = dd.Recovery.Format("2006-01-02")
+	}
+
+	return fmt.Sprintf("%.2f%% peak %s trough %s recovery %s",
+		dd.Depth*100, dd.Peak.Format("2006-01-02"), dd.Trough.Format("2006-01-02"), recovery)
+}
+
+// Calmar returns the annualized return divided by the magnitude of the maximum drawdown.
+func Calmar(prices []stock.DailyPrice) float64 {
+	dd := MaxDrawdown(prices)
+	if dd.Depth == 0 {
+		return math.NaN()
+	}
+
+	return AnnualizedReturn(prices) / -dd.Depth
+}
+
+// BetaAlpha regresses the stock's daily returns on the benchmark's over the dates both traded.
+// Returns are only taken between consecutive sessions present in both histories.
+// Alpha is Jensen's alpha, annualized. The risk-free rate is annual (0.02 = 2%).
+func BetaAlpha(prices, benchmark []stock.DailyPrice, riskFree float64) (beta, alpha float64, err error) {
+	closes := make(map[time.Time]float64, len(benchmark))
+	for _, dp := range benchmark {
+		_, c := dp.OpenClose()
+		closes[dp.Date()] = c
+	}
+
+	var stockReturns, benchReturns []float64
+	prevStock, prevBench := 0.0, 0.0
+	for _, dp := range prices {
+		bench, ok := closes[dp.Date()]
+		if !ok {
+			prevStock = 0
+			continue
+		}
+
+		_, c := dp.OpenClose()
+		if prevStock > 0 {
+			stockReturns = append(stockReturns, c/prevStock-1)
+			benchReturns = append(benchReturns, bench/prevBench-1)
+		}
+		prevStock, prevBench = c, bench
+	}
+
+	if len(stockReturns) < 2 {
+		return math.NaN(), math.NaN(), fmt.Errorf("not enough overlapping dates with benchmark: %d", len(stockReturns))
+	}
+
+	rf := riskFree / TradingDaysPerYear
+	meanStock, meanBench := Mean(stockReturns), Mean(benchReturns)
+	cov, variance := 0.0, 0.0
+	for i := range stockReturns {
+		cov += (stockReturns[i] - meanStock) * (benchReturns[i] - meanBench)
+		variance += (benchReturns[i] - meanBench) * (benchReturns[i] - meanBench)
+	}
+	if variance == 0 {
+		return math.NaN(), math.NaN(), fmt.Errorf("benchmark returns have no variance")
+	}
+
+	beta = cov / variance
+	alpha = ((meanStock - rf) - beta*(meanBench-rf)) * TradingDaysPerYear
+
+	return beta, alpha, nil
+}
@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// Daily returns of 10%, -10% and 20% have a mean of 1/15 and a sample standard deviation of sqrt(0.07/3).
+func TestSharpeSortino(t *testing.T) {
+	returns := []float64{0.1, -0.1, 0.2}
+
+	// (1/15) / sqrt(0.07/3) * sqrt(252) = 4 * sqrt(3)
+	assertNear(t, "Sharpe", Sharpe(returns, 0), 4*math.Sqrt(3), 1e-9)
+
+	// The downside deviation is sqrt(0.01/3): (1/15) / sqrt(0.01/3) * sqrt(252) = 2 * sqrt(84)
+	assertNear(t, "Sortino", Sortino(returns, 0), 2*math.Sqrt(84), 1e-9)
+
+	// A 25.2% annual risk-free rate is 0.1% a day, lowering each return.
+	excess := []float64{0.099, -0.101, 0.199}
+	assertNear(t, "Sharpe with a risk-free rate", Sharpe(returns, 0.252), Mean(excess)/StdDev(excess)*math.Sqrt(TradingDaysPerYear), 1e-9)
+	assertNear(t, "Sortino with a risk-free rate", Sortino(returns, 0.252),
+		Mean(excess)/math.Sqrt(0.101*0.101/3)*math.Sqrt(TradingDaysPerYear), 1e-9)
+
+	if !math.IsNaN(Sortino([]float64{0.1, 0.2}, 0)) {
+		t.Error("Sortino without losses is not NaN")
+	}
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	prices := testPrices(t, 100, 110, 99, 104.5, 88, 111, 105)
+	dd := MaxDrawdown(prices)
+
+	// From 110 to 88.
+	assertNear(t, "drawdown", dd.Depth, -0.2, 1e-12)
+	if !dd.Peak.Equal(prices[1].Date()) || !dd.Trough.Equal(prices[4].Date()) || !dd.Recovery.Equal(prices[5].Date()) {
+		t.Errorf("drawdown %s, want peak %s trough %s recovery %s", dd,
+			prices[1].Date().Format("2006-01-02"), prices[4].Date().Format("2006-01-02"), prices[5].Date().Format("2006-01-02"))
+	}
+
+	dd = MaxDrawdown(testPrices(t, 100, 120, 90, 100))
+	assertNear(t, "unrecovered drawdown", dd.Depth, -0.25, 1e-12)
+	if !dd.Recovery.IsZero() {
+		t.Errorf("recovery = %s, want none", dd.Recovery.Format("2006-01-02"))
+	}
+
+	if dd = MaxDrawdown(testPrices(t, 100, 101, 102)); dd.Depth != 0 || !math.IsNaN(Calmar(testPrices(t, 100, 101, 102))) {
+		t.Errorf("rising prices have drawdown %s, want none and a NaN Calmar ratio", dd)
+	}
+}
+
+// The stock's returns are twice the benchmark's plus 0.1% a day, over the dates both traded.
+func TestBetaAlpha(t *testing.T) {
+	benchmark := testPrices(t, 100, 101, 98.98, 99, 100, 103)
+	benchmark = append(benchmark[:3], benchmark[4:]...)
+
+	// The stock's close on the date missing from the benchmark, and the return from it, are skipped.
+	prices := testPrices(t, 50, 50*1.021, 50*1.021*0.961, 500, 40, 40*1.061)
+
+	beta, alpha, err := BetaAlpha(prices, benchmark, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNear(t, "beta", beta, 2, 1e-9)
+	assertNear(t, "alpha", alpha, 0.001*TradingDaysPerYear, 1e-9)
+
+	// With a risk-free rate, alpha is reduced by the rate times (1 - beta), annualized.
+	_, alpha, err = BetaAlpha(prices, benchmark, 0.0252)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNear(t, "alpha with a risk-free rate", alpha, (0.001+0.0001)*TradingDaysPerYear, 1e-9)
+
+	if _, _, err = BetaAlpha(prices, testPrices(t, 100, 100, 100), 0); err == nil {
+		t.Error("beta against a flat benchmark, want an error")
+	}
+}
+
+func TestSummarizeWithoutBenchmark(t *testing.T) {
+	s, err := Summarize("TEST", testPrices(t, 100, 110, 99, 118.8), nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Days != 4 || !math.IsNaN(s.Beta) || !math.IsNaN(s.Alpha) {
+		t.Errorf("summary %s, want 4 days without beta and alpha", s)
+	}
+	assertNear(t, "summary Sharpe", s.Sharpe, 4*math.Sqrt(3), 1e-9)
+	assertNear(t, "summary drawdown", s.Drawdown.Depth, -0.1, 1e-12)
+
+	if _, err = Summarize("TEST", testPrices(t, 100, 110), nil, 0); err == nil {
+		t.Error("summarized two prices, want an error")
+	}
+}
@@ -0,0 +1,215 @@
+// Package volatility computes realized volatility cones from daily stock prices.
+package volatility
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/analysis/stats"
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// DefaultWindows are the realized volatility windows of a cone, in sessions.
+var DefaultWindows = []int{10, 20, 30, 60, 90, 120}
+
+// DefaultLookback is the number of sessions over which rolling volatilities are collected.
+const DefaultLookback = 252
+
+// ConeWindow holds the distribution of rolling realized volatility for one window.
+// Volatilities are annualized (0.25 = 25%).
+type ConeWindow struct {
+	Window     int // Sessions in each realized volatility measurement.
+	Samples    int // Number of rolling measurements.
+	Min        float64
+	P25        float64
+	Median     float64
+	P75        float64
+	Max        float64
+	Current    float64 // Realized volatility over the most recent window.
+	Percentile float64 // Percent of measurements at or below the current volatility.
+}
+
+// Cone is a realized volatility cone.
+type Cone struct {
+	Symbol   string
+	Lookback int
+	Windows  []ConeWindow
+	returns  []float64 // Log returns over the lookback plus the longest window.
+}
+
+// NewCone computes the volatility cone of daily log returns for each window. Rolling measurements
+// end on each of the last lookback sessions, so the prices must cover the lookback plus the longest window.
+func NewCone(symbol string, prices []stock.DailyPrice, windows []int, lookback int) (c Cone, err error) {
+	c.Symbol = symbol
+	c.Lookback = lookback
+
+	if lookback < 1 {
+		return c, fmt.Errorf("invalid lookback: %d", lookback)
+	}
+
+	longest := 0
+	for _, w := range windows {
+		if w < 2 {
+			return c, fmt.Errorf("invalid volatility window: %d", w)
+		}
+		if w > longest {
+			longest = w
+		}
+	}
+
+	returns := stats.LogReturns(prices)
+	if len(returns) < longest {
+		return c, fmt.Errorf("not enough price history for a %d session window: %d days", longest, len(prices))
+	}
+	if len(returns) > lookback+longest-1 {
+		returns = returns[len(returns)-(lookback+longest-1):]
+	}
+	c.returns = returns
+
+	for _, w := range windows {
+		vols := c.rolling(w)
+
+		cw := ConeWindow{
+			Window:     w,
+			Samples:    len(vols),
+			Current:    vols[len(vols)-1],
+			Percentile: rank(vols, vols[len(vols)-1]),
+		}
+		sort.Float64s(vols)
+		cw.Min = vols[0]
+		cw.P25 = percentile(vols, 25)
+		cw.Median = percentile(vols, 50)
+		cw.P75 = percentile(vols, 75)
+		cw.Max = vols[len(vols)-1]
+
+		c.Windows = append(c.Windows, cw)
+	}
+
+	return
+}
+
+// rolling returns the annualized realized volatility of each window of returns ending in the lookback, oldest first.
+func (c Cone) rolling(window int) []float64 {
+	first := len(c.returns) - c.Lookback + 1
+	if first < window {
+		first = window
+	}
+
+	vols := make([]float64, 0, len(c.returns)-first+1)
+	for end := first; end <= len(c.returns); end++ {
+		vols = append(vols, stats.AnnualizedVolatility(c.returns[end-window:end]))
+	}
+
+	return vols
+}
+
+// IVPoint places an option expiration's implied volatility on the cone.
+type IVPoint struct {
+	Expiration time.Time
+	Sessions   int     // Trading sessions until expiration, used as the realized volatility window.
+	IV         float64 // Annualized implied volatility.
+	Median     float64 // Median realized volatility over the window.
+	Percentile float64 // Percent of realized volatility measurements at or below the IV.
+}
+
+// PlaceIV compares an implied volatility with the realized volatilities over the sessions until expiration.
+func (c Cone) PlaceIV(expiration time.Time, iv float64, now time.Time) (p IVPoint, err error) {
+	p.Expiration = expiration
+	p.IV = iv
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+	for day := today.AddDate(0, 0, 1); !day.After(expiration); day = day.AddDate(0, 0, 1) {
+		if markets.IsTradingDay(day) {
+			p.Sessions++
+		}
+	}
+
+	window := p.Sessions
+	if window < 2 {
+		window = 2
+	}
+	if window > len(c.returns) {
+		return p, fmt.Errorf("not enough price history for a %d session window", window)
+	}
+
+	vols := c.rolling(window)
+	p.Percentile = rank(vols, iv)
+	sort.Float64s(vols)
+	p.Median = percentile(vols, 50)
+
+	return
+}
+
+// Richness describes the IV relative to realized volatility: rich above the 75th percentile, cheap below the 25th.
+func (p IVPoint) Richness() string {
+	switch {
+	case p.Percentile > 75:
+		return "rich"
+	case p.Percentile < 25:
+		return "cheap"
+	}
+
+	return "fair"
+}
+
+// rank returns the percent of values at or below x.
+func rank(values []float64, x float64) float64 {
+	cnt := 0
+	for _, v := range values {
+		if v <= x {
+			cnt++
+		}
+	}
+
+	return float64(cnt) / float64(len(values)) * 100
+}
+
+// percentile returns the linearly interpolated percentile of sorted values.
+func percentile(sorted []float64, pct float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	pos := pct / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	if lo >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+
+	return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+func ConeHeader() string {
+	return fmt.Sprintln("Window     Min     25%  Median     75%     Max  Current  Pctile  Samples")
+}
+
+func (cw ConeWindow) String() string {
+	return fmt.Sprintf("%6d %6.1f%% %6.1f%% %6.1f%% %6.1f%% %6.1f%% %7.1f%% %6.0f%% %8d",
+		cw.Window, cw.Min*100, cw.P25*100, cw.Median*100, cw.P75*100, cw.Max*100,
+		cw.Current*100, cw.Percentile, cw.Samples,
+	)
+}
+
+func (c Cone) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%s realized volatility cone over %d sessions\n", c.Symbol, c.Lookback))
+	sb.WriteString(ConeHeader())
+	for _, cw := range c.Windows {
+		sb.WriteString(fmt.Sprintln(cw))
+	}
+
+	return sb.String()
+}
+
+func IVPointHeader() string {
+	return fmt.Sprintln("Expiration  Sessions     IV  Median RV  Pctile")
+}
+
+func (p IVPoint) String() string {
+	return fmt.Sprintf("%s %8d %5.1f%% %9.1f%% %6.0f%%  %s",
+		p.Expiration.Format("2006-01-02"), p.Sessions, p.IV*100, p.Median*100, p.Percentile, p.Richness())
+}
@@ -0,0 +1,166 @@
+package volatility
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/analysis/stats"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// testPrices returns daily prices closing at each of the closes on consecutive weekdays.
+func testPrices(t *testing.T, closes ...float64) []stock.DailyPrice {
+	t.Helper()
+
+	prices := make([]stock.DailyPrice, 0, len(closes))
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, c := range closes {
+		for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			date = date.AddDate(0, 0, 1)
+		}
+		dp, err := stock.NewDailyPrice(date.Year(), int(date.Month()), date.Day(), c, c, c, c, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prices = append(prices, dp)
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return prices
+}
+
+// alternating returns n closes alternating between low and high, starting at low.
+func alternating(n int, low, high float64) []float64 {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = low
+		if i%2 == 1 {
+			closes[i] = high
+		}
+	}
+
+	return closes
+}
+
+func assertNear(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+
+	if math.IsNaN(got) || math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %.6f, want %.6f", name, got, want)
+	}
+}
+
+func TestNewConeConstantVolatility(t *testing.T) {
+	// Log returns alternate between +r and -r, so every window of the same length has the same volatility:
+	// r^2 * n/(n-1) for even windows and r^2 * (n+1)/n for odd windows, annualized.
+	r := math.Log(1.1)
+	annual := math.Sqrt(stats.TradingDaysPerYear)
+	want := map[int]float64{
+		2:  r * math.Sqrt(2) * annual,
+		5:  r * math.Sqrt(1.2) * annual,
+		10: r * math.Sqrt(10.0/9) * annual,
+	}
+
+	c, err := NewCone("TEST", testPrices(t, alternating(60, 100, 110)...), []int{2, 5, 10}, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Windows) != 3 {
+		t.Fatalf("%d windows, want 3", len(c.Windows))
+	}
+
+	for _, cw := range c.Windows {
+		if cw.Samples != 20 {
+			t.Errorf("%d session window: %d samples, want 20", cw.Window, cw.Samples)
+		}
+		for name, got := range map[string]float64{
+			"min": cw.Min, "25th percentile": cw.P25, "median": cw.Median, "75th percentile": cw.P75, "max": cw.Max, "current": cw.Current,
+		} {
+			assertNear(t, name, got, want[cw.Window], 1e-9)
+		}
+	}
+}
+
+func TestNewConePercentiles(t *testing.T) {
+	// Eleven returns: +b, -b, +b, -b, +b, -b, +a, -a, +a, -a, +a with b = 2a in log terms.
+	closes := append(alternating(7, 100, 121), alternating(5, 110, 100)...)
+	c, err := NewCone("TEST", testPrices(t, closes...), []int{2}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ten two session windows: five at b, one straddling -b and +a, then four at a.
+	// The volatility of two returns x and y is |x - y| / sqrt(2), annualized.
+	a, b := math.Log(1.1), math.Log(1.21)
+	annual := math.Sqrt(stats.TradingDaysPerYear)
+	va := 2 * a / math.Sqrt(2) * annual
+	vb := 2 * b / math.Sqrt(2) * annual
+	vs := (a + b) / math.Sqrt(2) * annual
+
+	cw := c.Windows[0]
+	if cw.Samples != 10 {
+		t.Errorf("%d samples, want 10", cw.Samples)
+	}
+
+	// Sorted: va x4, vs, vb x5. The 25th percentile falls between the third and fourth values,
+	// the median halfway between the fifth and sixth and the 75th percentile between the seventh and eighth.
+	assertNear(t, "min", cw.Min, va, 1e-9)
+	assertNear(t, "25th percentile", cw.P25, va, 1e-9)
+	assertNear(t, "median", cw.Median, (vs+vb)/2, 1e-9)
+	assertNear(t, "75th percentile", cw.P75, vb, 1e-9)
+	assertNear(t, "max", cw.Max, vb, 1e-9)
+	assertNear(t, "current", cw.Current, va, 1e-9)
+	assertNear(t, "percentile", cw.Percentile, 40, 1e-9)
+}
+
+func TestNewConeErrors(t *testing.T) {
+	prices := testPrices(t, alternating(10, 100, 110)...)
+
+	if _, err := NewCone("TEST", prices, []int{2}, 0); err == nil {
+		t.Error("zero lookback: want error")
+	}
+	if _, err := NewCone("TEST", prices, []int{1}, 5); err == nil {
+		t.Error("one session window: want error")
+	}
+	if _, err := NewCone("TEST", prices, []int{10}, 5); err == nil {
+		t.Error("window longer than the returns: want error")
+	}
+}
+
+func TestPlaceIV(t *testing.T) {
+	c, err := NewCone("TEST", testPrices(t, alternating(60, 100, 110)...), []int{2, 5, 10}, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Five sessions from Friday to the following Friday.
+	now := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	expiration := time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+	median := math.Log(1.1) * math.Sqrt(1.2) * math.Sqrt(stats.TradingDaysPerYear)
+
+	p, err := c.PlaceIV(expiration, median*1.5, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Sessions != 5 {
+		t.Errorf("%d sessions, want 5", p.Sessions)
+	}
+	assertNear(t, "median", p.Median, median, 1e-9)
+	assertNear(t, "rich percentile", p.Percentile, 100, 1e-9)
+	if p.Richness() != "rich" {
+		t.Errorf("richness = %s, want rich", p.Richness())
+	}
+
+	if p, err = c.PlaceIV(expiration, median/2, now); err != nil {
+		t.Fatal(err)
+	}
+	assertNear(t, "cheap percentile", p.Percentile, 0, 1e-9)
+	if p.Richness() != "cheap" {
+		t.Errorf("richness = %s, want cheap", p.Richness())
+	}
+
+	if _, err = c.PlaceIV(now.AddDate(0, 3, 0), median, now); err == nil {
+		t.Error("expiration beyond the price history: want error")
+	}
+}
@@ -0,0 +1,68 @@
+package volume
+
+import (
+	"math"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// AnchoredVWAPValue holds the anchored VWAP and its standard deviation bands.
+type AnchoredVWAPValue struct {
+	VWAP   float64
+	StdDev float64 // Volume-weighted standard deviation of prices around the VWAP.
+}
+
+// Band returns the price the number of standard deviations above (positive) or below (negative) the VWAP.
+func (v AnchoredVWAPValue) Band(stdDevs float64) float64 {
+	return v.VWAP + stdDevs*v.StdDev
+}
+
+// AnchoredVWAP is the volume-weighted average price of all bars starting at an anchor time, without session resets.
+// Each bar's own VWAP is used if known, otherwise its typical price.
+type AnchoredVWAP struct {
+	anchor time.Time
+	pv     float64
+	pv2    float64
+	volume float64
+	value  AnchoredVWAPValue
+}
+
+func NewAnchoredVWAP(anchor time.Time) *AnchoredVWAP {
+	return &AnchoredVWAP{anchor: anchor, value: AnchoredVWAPValue{VWAP: math.NaN(), StdDev: math.NaN()}}
+}
+
+// Update adds a bar. Bars starting before the anchor are ignored.
+func (a *AnchoredVWAP) Update(b stock.Bar) AnchoredVWAPValue {
+	if b.Start().Before(a.anchor) {
+		return a.value
+	}
+
+	price := b.AveragePrice()
+	vol := float64(b.Volume())
+	a.pv += price * vol
+	a.pv2 += price * price * vol
+	a.volume += vol
+	if a.volume > 0 {
+		vwap := a.pv / a.volume
+		variance := a.pv2/a.volume - vwap*vwap
+		a.value = AnchoredVWAPValue{VWAP: vwap, StdDev: math.Sqrt(math.Max(variance, 0))}
+	}
+
+	return a.value
+}
+
+func (a *AnchoredVWAP) Value() AnchoredVWAPValue {
+	return a.value
+}
+
+func AnchoredVWAPSeries(bars []stock.Bar, anchor time.Time) []AnchoredVWAPValue {
+	a := NewAnchoredVWAP(anchor)
+
+	values := make([]AnchoredVWAPValue, 0, len(bars))
+	for _, b := range bars {
+		values = append(values, a.Update(b))
+	}
+
+	return values
+}
@@ -0,0 +1,51 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+func TestAnchoredVWAP(t *testing.T) {
+	bars := []stock.Bar{
+		minuteBar(t, 0, 100, 100, 100), // Before the anchor.
+		minuteBar(t, 1, 10, 10, 1),
+		minuteBar(t, 2, 20, 20, 3),
+		minuteBar(t, 3, 0, 30, 4), // Typical price 30.
+	}
+
+	values := AnchoredVWAPSeries(bars, bars[1].Start())
+	if len(values) != len(bars) {
+		t.Fatalf("%d values, want %d", len(values), len(bars))
+	}
+
+	if !math.IsNaN(values[0].VWAP) || !math.IsNaN(values[0].StdDev) {
+		t.Errorf("before the anchor = %+v, want NaN", values[0])
+	}
+
+	assertNear(t, "first VWAP", values[1].VWAP, 10, 1e-9)
+	assertNear(t, "first standard deviation", values[1].StdDev, 0, 1e-9)
+
+	// (10*1 + 20*3) / 4 = 17.5, variance (100*1 + 400*3) / 4 - 17.5^2 = 18.75.
+	assertNear(t, "second VWAP", values[2].VWAP, 17.5, 1e-9)
+	assertNear(t, "second standard deviation", values[2].StdDev, math.Sqrt(18.75), 1e-9)
+
+	// (70 + 30*4) / 8 = 23.75, variance (1300 + 900*4) / 8 - 23.75^2 = 48.4375.
+	sd := math.Sqrt(48.4375)
+	assertNear(t, "third VWAP", values[3].VWAP, 23.75, 1e-9)
+	assertNear(t, "third standard deviation", values[3].StdDev, sd, 1e-9)
+	assertNear(t, "upper band", values[3].Band(2), 23.75+2*sd, 1e-9)
+	assertNear(t, "lower band", values[3].Band(-1), 23.75-sd, 1e-9)
+}
+
+func TestAnchoredVWAPValue(t *testing.T) {
+	a := NewAnchoredVWAP(testOpen)
+	if v := a.Value(); !math.IsNaN(v.VWAP) {
+		t.Errorf("VWAP before any bars = %.4f, want NaN", v.VWAP)
+	}
+
+	a.Update(minuteBar(t, 0, 50, 50, 10))
+	a.Update(minuteBar(t, 1, 60, 60, 0))
+	assertNear(t, "VWAP", a.Value().VWAP, 50, 1e-9)
+}
@@ -0,0 +1,173 @@
+// Package volume analyzes where and at what prices stock volume traded.
+package volume
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// DefaultValueArea is the usual fraction of volume in the value area.
+const DefaultValueArea = 0.7
+
+// Profile is a volume-at-price histogram. Each bar's volume is placed in the bucket containing its
+// average price (its VWAP if known, otherwise its typical price), so one minute bars give the best detail.
+type Profile struct {
+	Start         time.Time // Start of the first bar.
+	End           time.Time // Start of the last bar.
+	BucketSize    float64
+	Low           float64 // Bottom price of the first bucket; buckets are aligned to multiples of the bucket size.
+	Volumes       []int64 // Volume traded in each bucket, from the lowest price up.
+	Total         int64
+	POC           float64 // Point of control: the middle price of the bucket with the most volume.
+	ValueAreaHigh float64 // Top price of the value area.
+	ValueAreaLow  float64 // Bottom price of the value area.
+}
+
+// NewProfile builds the volume profile of the bars. valueArea is the fraction of volume in the value area,
+// which is grown from the point of control toward the side with more volume.
+func NewProfile(bars []stock.Bar, bucketSize, valueArea float64) (p Profile, err error) {
+	if len(bars) == 0 {
+		return p, fmt.Errorf("no bars for volume profile")
+	}
+
+	if bucketSize <= 0 {
+		return p, fmt.Errorf("invalid bucket size: %g", bucketSize)
+	}
+
+	if valueArea <= 0 || valueArea > 1 {
+		return p, fmt.Errorf("invalid value area: %g", valueArea)
+	}
+
+	p.Start = bars[0].Start()
+	p.End = bars[len(bars)-1].Start()
+	p.BucketSize = bucketSize
+
+	lowIdx, highIdx := math.MaxInt, math.MinInt
+	for _, b := range bars {
+		idx := bucketIndex(b.AveragePrice(), bucketSize)
+		if idx < lowIdx {
+			lowIdx = idx
+		}
+		if idx > highIdx {
+			highIdx = idx
+		}
+	}
+
+	p.Low = float64(lowIdx) * bucketSize
+	p.Volumes = make([]int64, highIdx-lowIdx+1)
+	for _, b := range bars {
+		p.Volumes[bucketIndex(b.AveragePrice(), bucketSize)-lowIdx] += b.Volume()
+		p.Total += b.Volume()
+	}
+
+	poc := 0
+	for i, v := range p.Volumes {
+		if v > p.Volumes[poc] {
+			poc = i
+		}
+	}
+	p.POC = p.Low + (float64(poc)+0.5)*bucketSize
+
+	// Grow the value area one bucket at a time toward the larger neighboring volume.
+	lo, hi := poc, poc
+	volume := p.Volumes[poc]
+	target := int64(math.Ceil(float64(p.Total) * valueArea))
+	for volume < target {
+		up, down := int64(-1), int64(-1)
+		if hi+1 < len(p.Volumes) {
+			up = p.Volumes[hi+1]
+		}
+		if lo > 0 {
+			down = p.Volumes[lo-1]
+		}
+
+		if up >= down {
+			hi++
+			volume += up
+		} else {
+			lo--
+			volume += down
+		}
+	}
+	p.ValueAreaLow = p.Low + float64(lo)*bucketSize
+	p.ValueAreaHigh = p.Low + float64(hi+1)*bucketSize
+
+	return
+}
+
+// SessionProfiles builds a volume profile for each trading day of intraday bars.
+func SessionProfiles(bars []stock.Bar, bucketSize, valueArea float64) ([]Profile, error) {
+	var profiles []Profile
+
+	first := 0
+	for i := range bars {
+		if i < len(bars)-1 && stock.SessionDay(bars[i].Start()).Equal(stock.SessionDay(bars[i+1].Start())) {
+			continue
+		}
+
+		p, err := NewProfile(bars[first:i+1], bucketSize, valueArea)
+		if err != nil {
+			return profiles, err
+		}
+		profiles = append(profiles, p)
+		first = i + 1
+	}
+
+	return profiles, nil
+}
+
+// bucketIndex returns the bucket containing the price. A small tolerance keeps prices on a bucket boundary,
+// such as 10.20 with a 0.10 bucket size, from falling into the bucket below through rounding.
+func bucketIndex(price, bucketSize float64) int {
+	return int(math.Floor(price/bucketSize + 1e-9))
+}
+
+func ProfileHeader() string {
+	return fmt.Sprintln("Start             End                  POC       VAL       VAH          Volume")
+}
+
+func (p Profile) String() string {
+	return fmt.Sprintf("%s  %s  %9.2f %9.2f %9.2f %15d",
+		p.Start.Format("2006-01-02 15:04"), p.End.Format("2006-01-02 15:04"),
+		p.POC, p.ValueAreaLow, p.ValueAreaHigh, p.Total,
+	)
+}
+
+// Histogram draws the profile from the highest price down, with the longest bar width characters long.
+// The point of control is marked with "<POC" and buckets in the value area with "*".
+func (p Profile) Histogram(width int) string {
+	var largest int64
+	for _, v := range p.Volumes {
+		if v > largest {
+			largest = v
+		}
+	}
+
+	sb := strings.Builder{}
+	for i := len(p.Volumes) - 1; i >= 0; i-- {
+		bottom := p.Low + float64(i)*p.BucketSize
+		mid := bottom + p.BucketSize/2
+
+		marker := " "
+		if bottom >= p.ValueAreaLow && bottom < p.ValueAreaHigh {
+			marker = "*"
+		}
+
+		length := 0
+		if largest > 0 {
+			length = int(float64(p.Volumes[i]) / float64(largest) * float64(width))
+		}
+
+		sb.WriteString(fmt.Sprintf("%9.2f %s %-*s %12d", bottom, marker, width, strings.Repeat("#", length), p.Volumes[i]))
+		if math.Abs(mid-p.POC) < p.BucketSize/2 {
+			sb.WriteString(" <POC")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,145 @@
+package volume
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+var testOpen = time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+
+// minuteBar returns the one minute bar the given minutes after the open, trading at its VWAP.
+// A zero VWAP leaves the bar to be placed by its typical price, with the close in the middle of its range.
+func minuteBar(t *testing.T, minute int, vwap, close float64, volume int64) stock.Bar {
+	t.Helper()
+
+	start := testOpen.Add(time.Duration(minute) * time.Minute)
+	b, err := stock.NewBar(start, start, close, close, close+2, close-2, volume, vwap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func assertNear(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+
+	if math.IsNaN(got) || math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %.4f, want %.4f (tolerance %g)", name, got, want, tolerance)
+	}
+}
+
+// profileBars have bucket volumes of 10, 35, 50, 30, 40 and 5 from $100 up in $1 buckets.
+func profileBars(t *testing.T) []stock.Bar {
+	t.Helper()
+
+	return []stock.Bar{
+		minuteBar(t, 0, 102.5, 102.5, 50),
+		minuteBar(t, 1, 101.2, 101.2, 20),
+		minuteBar(t, 2, 103.0, 103.0, 30),
+		minuteBar(t, 3, 100.5, 100.5, 10),
+		minuteBar(t, 4, 101.9, 101.9, 15),
+		minuteBar(t, 5, 0, 104.5, 40),
+		minuteBar(t, 6, 105.99, 105.99, 5),
+	}
+}
+
+func TestNewProfile(t *testing.T) {
+	bars := profileBars(t)
+	p, err := NewProfile(bars, 1, DefaultValueArea)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int64{10, 35, 50, 30, 40, 5}
+	if len(p.Volumes) != len(want) {
+		t.Fatalf("volumes = %v, want %v", p.Volumes, want)
+	}
+	for i := range want {
+		if p.Volumes[i] != want[i] {
+			t.Errorf("volumes = %v, want %v", p.Volumes, want)
+			break
+		}
+	}
+
+	if p.Total != 170 {
+		t.Errorf("total = %d, want 170", p.Total)
+	}
+	if !p.Start.Equal(bars[0].Start()) || !p.End.Equal(bars[len(bars)-1].Start()) {
+		t.Errorf("profile from %s to %s, want %s to %s", p.Start, p.End, bars[0].Start(), bars[len(bars)-1].Start())
+	}
+	assertNear(t, "low", p.Low, 100, 1e-9)
+	assertNear(t, "POC", p.POC, 102.5, 1e-9)
+
+	// 70% of 170 is 119: 50 at the POC, then 35 below (larger than 30 above), then 30 and 40 above
+	// (each larger than 10 below) reach 155.
+	assertNear(t, "value area low", p.ValueAreaLow, 101, 1e-9)
+	assertNear(t, "value area high", p.ValueAreaHigh, 105, 1e-9)
+}
+
+func TestNewProfileWholeValueArea(t *testing.T) {
+	p, err := NewProfile(profileBars(t), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertNear(t, "value area low", p.ValueAreaLow, 100, 1e-9)
+	assertNear(t, "value area high", p.ValueAreaHigh, 106, 1e-9)
+}
+
+func TestNewProfileErrors(t *testing.T) {
+	bars := profileBars(t)
+
+	if _, err := NewProfile(nil, 1, DefaultValueArea); err == nil {
+		t.Error("no bars: want error")
+	}
+	if _, err := NewProfile(bars, 0, DefaultValueArea); err == nil {
+		t.Error("zero bucket size: want error")
+	}
+	if _, err := NewProfile(bars, 1, 0); err == nil {
+		t.Error("zero value area: want error")
+	}
+	if _, err := NewProfile(bars, 1, 1.1); err == nil {
+		t.Error("value area over 1: want error")
+	}
+}
+
+func TestBucketIndex(t *testing.T) {
+	if got := bucketIndex(10.20, 0.10); got != 102 {
+		t.Errorf("bucketIndex(10.20, 0.10) = %d, want 102", got)
+	}
+	if got := bucketIndex(10.29, 0.10); got != 102 {
+		t.Errorf("bucketIndex(10.29, 0.10) = %d, want 102", got)
+	}
+}
+
+func TestSessionProfiles(t *testing.T) {
+	bars := profileBars(t)
+	nextDay := testOpen.AddDate(0, 0, 1)
+	next, err := stock.NewBar(nextDay, nextDay, 110, 110, 110, 110, 100, 110.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bars = append(bars, next)
+
+	profiles, err := SessionProfiles(bars, 1, DefaultValueArea)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("%d profiles, want 2", len(profiles))
+	}
+
+	if profiles[0].Total != 170 {
+		t.Errorf("first session total = %d, want 170", profiles[0].Total)
+	}
+	if profiles[1].Total != 100 {
+		t.Errorf("second session total = %d, want 100", profiles[1].Total)
+	}
+	assertNear(t, "second session POC", profiles[1].POC, 110.5, 1e-9)
+	assertNear(t, "second session value area low", profiles[1].ValueAreaLow, 110, 1e-9)
+	assertNear(t, "second session value area high", profiles[1].ValueAreaHigh, 111, 1e-9)
+}
@@ -0,0 +1,110 @@
+package volume
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Curve is the average cumulative volume traded by each minute of the session,
+// used to judge volume so far in a session against what is normal for that time of day.
+type Curve struct {
+	sessions   int
+	cumulative [stock.SessionMinutes]float64
+}
+
+// NewCurve builds the volume curve from the complete sessions in one minute sales history.
+// Sessions on or after the before date, such as today's partial session, and early close sessions are skipped.
+func NewCurve(history []stock.OneMinSale, before time.Time) (c Curve, err error) {
+	cutoff := time.Date(before.Year(), before.Month(), before.Day(), 0, 0, 0, 0, time.UTC)
+
+	var session [stock.SessionMinutes]float64
+	var day time.Time
+	add := func() {
+		if day.IsZero() || !day.Before(cutoff) || markets.IsEarlyClose(day) {
+			return
+		}
+		for i := 1; i < stock.SessionMinutes; i++ {
+			session[i] += session[i-1]
+		}
+		for i := range session {
+			c.cumulative[i] += session[i]
+		}
+		c.sessions++
+	}
+
+	for _, oms := range history {
+		t := oms.StartTime()
+		if d := stock.SessionDay(t); !d.Equal(day) {
+			add()
+			day = d
+			session = [stock.SessionMinutes]float64{}
+		}
+
+		if i, ok := minuteIndex(t); ok {
+			session[i] += float64(oms.Volume())
+		}
+	}
+	add()
+
+	if c.sessions == 0 {
+		return c, fmt.Errorf("no complete sessions in one minute sales history")
+	}
+
+	for i := range c.cumulative {
+		c.cumulative[i] /= float64(c.sessions)
+	}
+
+	return
+}
+
+// Sessions returns the number of sessions averaged.
+func (c Curve) Sessions() int {
+	return c.sessions
+}
+
+// Expected returns the average volume traded from the open through the minute of t.
+func (c Curve) Expected(t time.Time) (float64, error) {
+	i, ok := minuteIndex(t)
+	if !ok {
+		return 0, fmt.Errorf("time is outside of market hours: %s", t.Format("15:04"))
+	}
+
+	return c.cumulative[i], nil
+}
+
+// RVOL returns the relative volume: the volume traded through the minute of t as a multiple of the average.
+func (c Curve) RVOL(volume int64, t time.Time) (float64, error) {
+	expected, err := c.Expected(t)
+	if err != nil {
+		return 0, err
+	}
+	if expected <= 0 {
+		return 0, fmt.Errorf("no average volume by %s", t.Format("15:04"))
+	}
+
+	return float64(volume) / expected, nil
+}
+
+// LinearRVOL returns the relative volume assuming the average daily volume trades evenly through the session.
+// It is a rough substitute when no one minute sales history is available, overstating RVOL near the open.
+func LinearRVOL(volume, avgVolume int64, t time.Time) (float64, error) {
+	i, ok := minuteIndex(t)
+	if !ok {
+		return 0, fmt.Errorf("time is outside of market hours: %s", t.Format("15:04"))
+	}
+	if avgVolume <= 0 {
+		return 0, fmt.Errorf("invalid average volume: %d", avgVolume)
+	}
+
+	return float64(volume) / (float64(avgVolume) * float64(i+1) / stock.SessionMinutes), nil
+}
+
+// minuteIndex returns the minute of the session at t, starting with 0 at the open.
+func minuteIndex(t time.Time) (int, bool) {
+	i := (t.Hour()-stock.MarketOpenHour)*60 + t.Minute() - stock.MarketOpenMinute
+
+	return i, i >= 0 && i < stock.SessionMinutes
+}
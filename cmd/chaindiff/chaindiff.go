@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+)
+
+const (
+	minOpenInterestChange = 100
+	minPremiumChangePct   = 25
+)
+
+// main compares two stored option chain snapshots of a stock, by default the two most recent.
+func main() {
+	if len(os.Args) != 2 && len(os.Args) != 4 {
+		usage()
+	}
+	symbol := os.Args[1]
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	snapshotGobs, err := store.LoadChainSnapshots(symbol)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if len(snapshotGobs) < 2 {
+		log.Fatalf("At least 2 option chain snapshots are needed for %s, found %d\n", symbol, len(snapshotGobs))
+	}
+
+	// Snapshots are numbered from 1, oldest first.
+	from, to := len(snapshotGobs)-1, len(snapshotGobs)
+	if len(os.Args) == 4 {
+		if from, err = strconv.Atoi(os.Args[2]); err != nil {
+			usage()
+		}
+		if to, err = strconv.Atoi(os.Args[3]); err != nil {
+			usage()
+		}
+		if from < 1 || to < 1 || from > len(snapshotGobs) || to > len(snapshotGobs) {
+			log.Fatalf("Snapshot numbers for %s must be between 1 and %d\n", symbol, len(snapshotGobs))
+		}
+	}
+
+	fromSnapshot, err := snapshotGobs[from-1].ToChainSnapshot()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	toSnapshot, err := snapshotGobs[to-1].ToChainSnapshot()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	diff, err := option.DiffChains(fromSnapshot, toSnapshot, option.DiffThresholds{
+		MinOpenInterestChange: minOpenInterestChange,
+		MinPremiumChangePct:   minPremiumChangePct,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Print(diff)
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: chaindiff Symbol [FromSnapshot ToSnapshot]\n\n")
+	os.Exit(1)
+}
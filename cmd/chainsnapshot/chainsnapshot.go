@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+	"github.com/tsilvers/realtime-securities/provider"
+)
+
+// main retrieves the option chains of each stock's expirations, or with -maxdays those expiring within that many
+// days, and appends them to the stored snapshot history.
+func main() {
+	var filter option.ExpirationFilter
+	switch {
+	case len(os.Args) == 1:
+	case len(os.Args) == 3 && os.Args[1] == "-maxdays":
+		maxDays, err := strconv.Atoi(os.Args[2])
+		if err != nil || maxDays < 1 {
+			usage()
+		}
+		filter.MaxDays = maxDays
+	default:
+		usage()
+	}
+
+	ds := provider.GetProvider("Tradier")
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	fmt.Println("Saving option chain snapshots...")
+
+	// Retrieve list of stock symbols.
+	symbols := stock.GetSymbols()
+
+	// Retrieve underlying prices.
+	lastPrices := make(map[string]float64)
+	for _, q := range ds.GetQuotes(symbols) {
+		lastPrices[q.Symbol()] = q.Last()
+	}
+
+	cnt := 0
+	for _, symbol := range symbols {
+		cnt++
+		fmt.Printf("%4d: %s\n", cnt, symbol)
+
+		// Load each expiration's calls and puts. A snapshot missing any chain is not saved, since it would
+		// show the missing options as removed.
+		taken := time.Now()
+		var chains []option.Expiration
+		complete := true
+		for _, exp := range filter.Filter(ds.GetOptionExpirations(symbol)) {
+			chain, err := ds.GetOptionChain(symbol, exp)
+			if err != nil {
+				log.Println(err)
+				complete = false
+				break
+			}
+			chains = append(chains, chain)
+		}
+		if !complete {
+			log.Printf("Option chain snapshot for %s not saved\n", symbol)
+			continue
+		}
+
+		snapshot, err := option.NewChainSnapshot(symbol, taken, lastPrices[symbol], chains)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		if err = store.SaveChainSnapshot(symbol, snapshot.ToGob()); err != nil {
+			log.Println(err)
+			continue
+		}
+		fmt.Printf("      %d expirations\n", len(chains))
+	}
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: chainsnapshot [-maxdays Days]\n\n")
+	os.Exit(1)
+}
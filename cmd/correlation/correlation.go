@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/tsilvers/realtime-securities/analysis/correlation"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+)
+
+const (
+	defaultLookback = 120
+	minOverlap      = 20
+	clusterDistance = 0.8 // Groups stocks with an average correlation above about 0.68.
+)
+
+// main displays return correlations and clusters of the stocks from persisted daily prices,
+// or the rolling correlation of two stocks.
+func main() {
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	if len(os.Args) != 1 && len(os.Args) != 2 && len(os.Args) != 5 {
+		usage()
+	}
+
+	lookback := defaultLookback
+	if len(os.Args) >= 2 {
+		if lookback, err = strconv.Atoi(os.Args[1]); err != nil {
+			usage()
+		}
+	}
+
+	// Load price histories from persistence.
+	prices := make(map[string][]stock.DailyPrice)
+	for _, symbol := range stock.GetSymbols() {
+		dps, err := persist.LoadDailyPrices(store, symbol)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		prices[symbol] = dps
+	}
+
+	aligned, err := correlation.Align(prices, lookback)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Rolling correlation of two stocks.
+	if len(os.Args) == 5 {
+		window, err := strconv.Atoi(os.Args[4])
+		if err != nil {
+			usage()
+		}
+
+		points, err := aligned.Rolling(os.Args[2], os.Args[3], window)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		fmt.Printf("%s / %s %d session rolling correlation:\n", os.Args[2], os.Args[3], window)
+		for _, pt := range points {
+			fmt.Println(pt)
+		}
+		return
+	}
+
+	fmt.Printf("Correlation of daily log returns over %d sessions:\n", len(aligned.Dates()))
+	matrix := aligned.Correlation(correlation.PairwiseComplete, minOverlap)
+	fmt.Print(matrix)
+
+	fmt.Println("\nMissing returns:")
+	gaps := aligned.Gaps()
+	for _, symbol := range aligned.Symbols() {
+		if gaps[symbol] > 0 {
+			fmt.Printf("%-6s %5d\n", symbol, gaps[symbol])
+		}
+	}
+
+	cluster, err := matrix.Cluster()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Println("\nClusters by correlation distance:")
+	fmt.Print(cluster)
+
+	fmt.Printf("\nGroups within distance %.2f:\n", clusterDistance)
+	for _, group := range cluster.Cut(clusterDistance) {
+		fmt.Println(group)
+	}
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: correlation [LookbackSessions [Symbol1 Symbol2 Window]]\n\n")
+	os.Exit(1)
+}
@@ -4,33 +4,48 @@ import (
 	"fmt"
 	"github.com/tsilvers/realtime-securities/markets/stock"
 	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
 	"github.com/tsilvers/realtime-securities/provider"
 	"log"
 	"os"
 	"time"
 )
 
-// main retrieves and persists daily stock prices starting from the requested date.
+// main retrieves and persists daily stock prices.
+// By default each stock's stored prices are updated from its last stored date, and the start date is only used
+// for stocks with no stored prices. With -rebuild, each stock's stored prices are replaced with those from the start date.
 func main() {
 	ds := provider.GetProvider("Tradier")
 
-	// Get start date.
-	if len(os.Args) != 2 {
-		usage()
+	// Get options and start date.
+	args := os.Args[1:]
+	rebuild := false
+	if len(args) > 0 && args[0] == "-rebuild" {
+		rebuild = true
+		args = args[1:]
 	}
-	start, err := time.Parse("01/02/2006", os.Args[1])
-	if err != nil {
+	if len(args) > 1 || (rebuild && len(args) == 0) {
 		usage()
 	}
+	var start time.Time
+	if len(args) == 1 {
+		var err error
+		if start, err = time.Parse("01/02/2006", args[0]); err != nil {
+			usage()
+		}
+	}
 
 	fmt.Println("Loading daily stock prices...")
 
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
 	// Retrieve list of stock symbols.
 	symbols := stock.GetSymbols()
 
-	// Clear stored prices.
-	persist.InitPricesStore()
-
 	// Load price histories.
 	var prices []*stock.DailyPrice
 	cnt := 0
@@ -38,8 +53,25 @@ func main() {
 		cnt++
 		fmt.Printf("%4d: %s\n", cnt, symbol)
 
+		// Start from the last stored date, which is fetched again in case it was stored before the close.
+		from := start
+		if !rebuild {
+			last, err := store.LastPriceDate(symbol)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			if !last.IsZero() {
+				from = last
+			}
+		}
+		if from.IsZero() {
+			log.Printf("No stored prices for %s; a start date is needed\n", symbol)
+			continue
+		}
+
 		// Load price history from data source.
-		prices = ds.GetPriceHistory(symbol, start)
+		prices = ds.GetPriceHistory(symbol, from)
 
 		// Persist price history.
 		var priceGobs []stock.DailyPriceGob
@@ -47,7 +79,17 @@ func main() {
 			priceGobs = append(priceGobs, price.ToGob())
 			fmt.Printf("      %s\n", price.Date().Format("01/02/2006"))
 		}
-		err = persist.SavePrices(symbol, priceGobs)
+
+		if rebuild {
+			// Keep the stored prices rather than replacing them with nothing if the retrieval failed.
+			if len(priceGobs) == 0 {
+				log.Printf("No prices retrieved for %s; keeping its stored prices\n", symbol)
+				continue
+			}
+			err = store.SavePrices(symbol, priceGobs)
+		} else {
+			err = store.UpsertPrices(symbol, priceGobs)
+		}
 		if err != nil {
 			log.Println(err)
 			continue
@@ -56,6 +98,6 @@ func main() {
 }
 
 func usage() {
-	_, _ = fmt.Fprintf(os.Stderr, "Usage: dailyprices StartDate (mm/dd/yyyy)\n\n")
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: dailyprices [StartDate (mm/dd/yyyy)]\n       dailyprices -rebuild StartDate (mm/dd/yyyy)\n\n")
 	os.Exit(1)
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+	"github.com/tsilvers/realtime-securities/provider"
+)
+
+// main displays the expected move by each near-term expiration and compares it with persisted daily price history.
+func main() {
+	ds := provider.GetProvider("Tradier")
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	symbols := os.Args[1:]
+	if len(symbols) == 0 {
+		symbols = stock.GetSymbols()
+	}
+
+	quotes := ds.GetQuotes(symbols)
+
+	for _, q := range quotes {
+		symbol := q.Symbol()
+		st := stock.NewStock(symbol)
+		st.SetQuote(q)
+
+		// Load price history from persistence.
+		prices, err := persist.LoadDailyPrices(store, symbol)
+		if err != nil {
+			log.Printf("Persistence load error %s: %s", symbol, err)
+			continue
+		}
+
+		dps := make([]*stock.DailyPrice, len(prices))
+		for i := range prices {
+			dps[i] = &prices[i]
+		}
+
+		if err = st.AppendDailyPrices(dps); err != nil {
+			log.Printf("Persistence load error %s: %s", symbol, err)
+			continue
+		}
+
+		// Load near-term option chains from the data provider.
+		var chains []option.Expiration
+		for _, exp := range option.DefaultExpirationFilter.Filter(ds.GetOptionExpirations(symbol)) {
+			chain, err := ds.GetOptionChain(symbol, exp)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			chains = append(chains, chain)
+		}
+
+		if err = st.AppendExpirations(chains); err != nil {
+			log.Println(err)
+			continue
+		}
+
+		comparisons, errs := st.ExpectedMoves(time.Now())
+		for _, err := range errs {
+			log.Println(err)
+		}
+
+		fmt.Printf("\n%s: %.2f\n", symbol, q.Last())
+		fmt.Print(option.ExpectedMoveHeader())
+		for _, mc := range comparisons {
+			fmt.Println(mc.ExpectedMove)
+		}
+		fmt.Print(stock.MoveComparisonHeader())
+		for _, mc := range comparisons {
+			fmt.Println(mc)
+		}
+	}
+}
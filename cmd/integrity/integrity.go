@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/analysis/integrity"
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+	"github.com/tsilvers/realtime-securities/provider"
+)
+
+// minGapPct is the smallest overnight gap reported, in percent.
+const minGapPct = 2.0
+
+// main checks persisted daily prices and one minute sales against the trading calendar,
+// and optionally refetches missing sessions from the data provider into the persisted prices.
+// Missing one minute sales are only reported.
+func main() {
+	ds := provider.GetProvider("Tradier")
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	args := os.Args[1:]
+	refetch := false
+	if len(args) > 0 && args[0] == "-refetch" {
+		refetch = true
+		args = args[1:]
+	}
+	for _, arg := range args {
+		if len(arg) > 0 && arg[0] == '-' {
+			usage()
+		}
+	}
+
+	symbols := args
+	if len(symbols) == 0 {
+		symbols = stock.GetSymbols()
+	}
+
+	// Today's session may not be complete.
+	end := time.Now().AddDate(0, 0, -1)
+
+	for _, symbol := range symbols {
+		prices, err := persist.LoadDailyPrices(store, symbol)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		sales, err := persist.LoadMinuteSales(store, symbol, time.Time{}, end)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		report := integrity.Check(symbol, prices, sales, end, minGapPct)
+		fmt.Print(report)
+
+		// Missing minutes are only reported: the provider's one minute history does not reach back far enough to fill them.
+		if refetch && len(report.MissingSessions) > 0 {
+			for _, run := range sessionRuns(report.MissingSessions) {
+				if prices, err = refetchSessions(ds, store, symbol, prices, run[0], run[len(run)-1]); err != nil {
+					log.Println(err)
+					break
+				}
+			}
+
+			report = integrity.Check(symbol, prices, sales, end, minGapPct)
+			fmt.Printf("After refetch: %d missing sessions\n", len(report.MissingSessions))
+		}
+
+		if len(report.Gaps) > 0 {
+			fmt.Print(integrity.GapHeader())
+			for _, g := range report.Gaps {
+				fmt.Println(g)
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// sessionRuns splits missing sessions into runs of consecutive trading days.
+func sessionRuns(days []time.Time) [][]time.Time {
+	var runs [][]time.Time
+	for i, day := range days {
+		if i == 0 || !nextTradingDay(days[i-1]).Equal(day) {
+			runs = append(runs, nil)
+		}
+		runs[len(runs)-1] = append(runs[len(runs)-1], day)
+	}
+
+	return runs
+}
+
+// nextTradingDay returns the first trading day after day.
+func nextTradingDay(day time.Time) time.Time {
+	day = day.AddDate(0, 0, 1)
+	for !markets.IsTradingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return day
+}
+
+// refetchSessions retrieves the daily prices from the start date through the end date and merges them into the persisted prices.
+func refetchSessions(ds provider.Provider, store persist.Store, symbol string, prices []stock.DailyPrice, start, end time.Time) ([]stock.DailyPrice, error) {
+	var fetched []stock.DailyPrice
+	var priceGobs []stock.DailyPriceGob
+	last := end.Format("2006-01-02")
+	for _, dp := range ds.GetPriceHistory(symbol, start) {
+		if dp.DateStr() > last {
+			continue
+		}
+		fetched = append(fetched, *dp)
+		priceGobs = append(priceGobs, dp.ToGob())
+	}
+
+	if err := store.UpsertPrices(symbol, priceGobs); err != nil {
+		return prices, err
+	}
+
+	return stock.MergeDailyPrices(prices, fetched), nil
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: integrity [-refetch] [Symbol ...]\n\n")
+	os.Exit(1)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/tsilvers/realtime-securities/analysis/levels"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+	"github.com/tsilvers/realtime-securities/provider"
+)
+
+const (
+	lookback     = 250 // Sessions of price history searched for swings.
+	swingBars    = 3   // Bars on each side of a swing high or low.
+	tolerancePct = 1.0 // Largest distance of a swing from its zone's average price.
+	minTouches   = 2   // Fewest swings making a zone.
+)
+
+// main displays pivot points and support and resistance zones from persisted daily prices,
+// and the nearest levels below and above the current price.
+func main() {
+	ds := provider.GetProvider("Tradier")
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	symbols := os.Args[1:]
+	if len(symbols) == 0 {
+		symbols = stock.GetSymbols()
+	}
+
+	quotes := ds.GetQuotes(symbols)
+
+	for _, q := range quotes {
+		symbol := q.Symbol()
+
+		prices, err := persist.LoadDailyPrices(store, symbol)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if len(prices) == 0 {
+			log.Printf("No price history for %s\n", symbol)
+			continue
+		}
+		if len(prices) > lookback {
+			prices = prices[len(prices)-lookback:]
+		}
+
+		prev := prices[len(prices)-1]
+		pivots := []levels.Pivots{
+			levels.ClassicPivots(prev),
+			levels.FibonacciPivots(prev),
+			levels.CamarillaPivots(prev),
+		}
+
+		swings, err := levels.Swings(stock.DailyPriceBars(prices), swingBars)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		zones := levels.Zones(swings, tolerancePct, minTouches)
+
+		var all []levels.Level
+		fmt.Printf("\n%s: %.2f  (pivots from %s)\n", symbol, q.Last(), prev.Date().Format("2006-01-02"))
+		for _, p := range pivots {
+			fmt.Println(p)
+			all = append(all, p.Levels()...)
+		}
+
+		fmt.Println("Support/resistance zones:")
+		for _, z := range zones {
+			fmt.Println(z)
+			all = append(all, z.Level())
+		}
+
+		below, above, err := levels.NearestToQuote(all, q)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if above != nil {
+			fmt.Printf("Nearest above: %s\n", above)
+		}
+		if below != nil {
+			fmt.Printf("Nearest below: %s\n", below)
+		}
+	}
+}
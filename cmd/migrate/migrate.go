@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/tsilvers/realtime-securities/config"
+	"github.com/tsilvers/realtime-securities/persist"
+)
+
+// main upgrades the files of the gob store to the current version of the data they hold, in place.
+// With -dryrun, the upgrade of each file is checked and reported but no files are changed.
+func main() {
+	args := os.Args[1:]
+	dryRun := false
+	if len(args) > 0 && args[0] == "-dryrun" {
+		dryRun = true
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		usage()
+	}
+
+	if dryRun {
+		fmt.Printf("Checking migration of store %s (dry run)...\n\n", config.StoreDir())
+	} else {
+		fmt.Printf("Migrating store %s...\n\n", config.StoreDir())
+	}
+
+	results, err := persist.Migrate(dryRun)
+	if len(results) > 0 {
+		fmt.Print(persist.MigrationHeader())
+	}
+
+	failed := 0
+	for _, result := range results {
+		fmt.Println(result)
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	switch {
+	case len(results) == 0:
+		fmt.Println("All files are up to date.")
+	case dryRun:
+		fmt.Printf("\n%d files to migrate, %d would fail.\n", len(results), failed)
+	default:
+		fmt.Printf("\n%d files migrated, %d failed and were left unchanged.\n", len(results)-failed, failed)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: migrate [-dryrun]\n\n")
+	os.Exit(1)
+}
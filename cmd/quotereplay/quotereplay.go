@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/quote"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+)
+
+// main replays the quote snapshots recorded for a stock on a day (default the latest day recorded),
+// showing the change in the last price between snapshots, spread statistics and data glitches.
+func main() {
+	if len(os.Args) < 2 || len(os.Args) > 3 {
+		usage()
+	}
+	symbol := os.Args[1]
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	var day time.Time
+	if len(os.Args) == 3 {
+		if day, err = time.Parse("2006-01-02", os.Args[2]); err != nil {
+			usage()
+		}
+	} else {
+		days, err := store.QuoteSnapshotDates(symbol)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(days) == 0 {
+			log.Fatalf("No quote snapshots recorded for %s\n", symbol)
+		}
+		day = days[len(days)-1]
+	}
+
+	snapshotGobs, err := store.LoadQuoteSnapshots(symbol, day)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Printf("%s quote snapshots received %s\n", symbol, day.Format("2006-01-02"))
+	fmt.Print(quote.SnapshotHeader())
+
+	var prev *quote.Snapshot
+	spreads, glitches := 0, 0
+	spreadSum, minSpread, maxSpread := 0.0, math.Inf(1), 0.0
+	for _, snapshotGob := range snapshotGobs {
+		snapshot, err := snapshotGob.ToSnapshot()
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		line := snapshot.String()
+		if prev != nil && prev.Quote.Last() > 0 {
+			line += fmt.Sprintf(" %+8.2f", snapshot.Quote.Last()-prev.Quote.Last())
+		}
+		if g := snapshot.Glitches(); len(g) > 0 {
+			line += "  ** " + strings.Join(g, ", ")
+			glitches++
+		}
+		fmt.Println(line)
+
+		if spread := snapshot.Spread(); spread > 0 {
+			spreads++
+			spreadSum += spread
+			minSpread = math.Min(minSpread, spread)
+			maxSpread = math.Max(maxSpread, spread)
+		}
+		prev = &snapshot
+	}
+
+	fmt.Printf("\n%d snapshots, %d with glitches\n", len(snapshotGobs), glitches)
+	if spreads > 0 {
+		fmt.Printf("Spread: average %.4f, min %.4f, max %.4f\n", spreadSum/float64(spreads), minSpread, maxSpread)
+	}
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: quotereplay Symbol [Date (yyyy-mm-dd)]\n\n")
+	os.Exit(1)
+}
@@ -3,19 +3,28 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/tsilvers/realtime-securities/markets/quote"
 
 	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
 	"github.com/tsilvers/realtime-securities/provider"
 )
 
 const stocksPerRequest = 100
 
-// main retrieves realtime quotes for requested stocks and displays them in ascending price order.
+// main retrieves realtime quotes for requested stocks, records them, and displays them in ascending price order.
 func main() {
 	ds := provider.GetProvider("Tradier")
 
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
 	// Create stocks for each symbol.
 	var stocks = make(map[string]*stock.Stock)
 	var symbols = make([]string, 0, 4)
@@ -37,6 +46,15 @@ func main() {
 
 		quotes := ds.GetQuotes(symbols[i:last])
 
+		// Record the quotes.
+		var snapshotGobs []quote.SnapshotGob
+		for _, snapshot := range quote.NewSnapshots(quotes, time.Now()) {
+			snapshotGobs = append(snapshotGobs, snapshot.ToGob())
+		}
+		if err := store.SaveQuoteSnapshots(snapshotGobs); err != nil {
+			log.Println(err)
+		}
+
 		for _, q := range quotes {
 			stocks[q.Symbol()].SetQuote(q)
 		}
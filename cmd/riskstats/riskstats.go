@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/tsilvers/realtime-securities/analysis/stats"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+)
+
+// main displays return and risk statistics from persisted daily stock prices.
+func main() {
+	if len(os.Args) > 3 {
+		usage()
+	}
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	// Optional benchmark symbol and risk-free rate.
+	var benchmark []stock.DailyPrice
+	if len(os.Args) >= 2 {
+		if benchmark, err = persist.LoadDailyPrices(store, os.Args[1]); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	riskFree := 0.0
+	if len(os.Args) == 3 {
+		if riskFree, err = strconv.ParseFloat(os.Args[2], 64); err != nil {
+			usage()
+		}
+		riskFree /= 100
+	}
+
+	fmt.Print(stats.SummaryHeader())
+	for _, symbol := range stock.GetSymbols() {
+		prices, err := persist.LoadDailyPrices(store, symbol)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		summary, err := stats.Summarize(symbol, prices, benchmark, riskFree)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		fmt.Println(summary)
+	}
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: riskstats [BenchmarkSymbol [RiskFreeRatePercent]]\n\n")
+	os.Exit(1)
+}
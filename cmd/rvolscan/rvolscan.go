@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/analysis/volume"
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+	"github.com/tsilvers/realtime-securities/provider"
+)
+
+const (
+	defaultMultiple = 2.0 // Default smallest relative volume flagged.
+	historyDays     = 30  // Calendar days of persisted one minute sales averaged.
+)
+
+type result struct {
+	symbol string
+	last   float64
+	chgPct float64
+	volume int
+	rvol   float64
+	linear bool // True if RVOL assumes volume trades evenly through the session.
+}
+
+// main flags stocks trading at a multiple of their normal volume for this time of the session.
+func main() {
+	ds := provider.GetProvider("Tradier")
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	if len(os.Args) > 2 {
+		usage()
+	}
+	multiple := defaultMultiple
+	if len(os.Args) == 2 {
+		if multiple, err = strconv.ParseFloat(os.Args[1], 64); err != nil || multiple <= 0 {
+			usage()
+		}
+	}
+
+	// Compare with the full session once the market has closed.
+	now := markets.MarketTime(time.Now())
+	closeTime := time.Date(now.Year(), now.Month(), now.Day(), stock.MarketCloseHour, stock.MarketCloseMinute, 0, 0, time.UTC)
+	if now.After(closeTime) {
+		now = closeTime
+	}
+
+	start := now.AddDate(0, 0, -historyDays)
+
+	var results []result
+	for _, q := range ds.GetQuotes(stock.GetSymbols()) {
+		if q.Volume() == 0 {
+			continue
+		}
+
+		r := result{symbol: q.Symbol(), last: q.Last(), chgPct: q.ChangePct(), volume: q.Volume()}
+
+		history, err := persist.LoadMinuteSales(store, q.Symbol(), start, now)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		curve, err := volume.NewCurve(history, now)
+		if err == nil {
+			r.rvol, err = curve.RVOL(int64(q.Volume()), now)
+		} else {
+			r.linear = true
+			r.rvol, err = volume.LinearRVOL(int64(q.Volume()), int64(q.AvgVolume()), now)
+		}
+		if err != nil {
+			log.Printf("RVOL for %s: %s\n", q.Symbol(), err)
+			continue
+		}
+
+		if r.rvol >= multiple {
+			results = append(results, r)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].rvol > results[j].rvol })
+
+	fmt.Printf("Stocks at %.1fx or more of normal volume by %s\n", multiple, now.Format("15:04"))
+	fmt.Println("Symbol      Last    Chg%         Volume    RVOL")
+	for _, r := range results {
+		note := ""
+		if r.linear {
+			note = "  (from average daily volume)"
+		}
+		fmt.Printf("%-8s %7.2f %7.2f %14d %7.2f%s\n", r.symbol, r.last, r.chgPct, r.volume, r.rvol, note)
+	}
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: rvolscan [Multiple]\n\n")
+	os.Exit(1)
+}
@@ -4,12 +4,20 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/tsilvers/realtime-securities/analysis/patterns"
 	"github.com/tsilvers/realtime-securities/markets/stock"
 	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
 )
 
-// main retrieves and displays persisted daily stock price info.
+// main retrieves and displays persisted daily stock price info, annotated with candlestick patterns.
 func main() {
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
 	fmt.Println("Retrieving daily stock prices...")
 
 	// Retrieve list of stock symbols.
@@ -21,7 +29,7 @@ func main() {
 	for _, symbol := range symbols {
 		stocks[symbol] = stock.NewStock(symbol)
 
-		priceGobs, err := persist.LoadPrices(symbol)
+		priceGobs, err := store.LoadPrices(symbol)
 		if err != nil {
 			log.Printf("Persistence load error %s: %s", symbol, err)
 			continue
@@ -52,9 +60,20 @@ func main() {
 	for _, st := range stockList {
 		fmt.Printf("\n%s:\n", st.Symbol())
 
+		// Annotate each day with the candlestick patterns ending on it.
+		prices := st.Prices()
+		matches, err := patterns.Detect(stock.DailyPriceBars(prices), patterns.DefaultConfig())
+		if err != nil {
+			log.Printf("Pattern detection error %s: %s", st.Symbol(), err)
+		}
+		annotations := make(map[int]string)
+		for _, match := range matches {
+			annotations[match.Index] += "  " + match.String()
+		}
+
 		fmt.Print(stock.DailyPriceHeader())
-		for _, dp := range st.Prices() {
-			fmt.Println(dp)
+		for i, dp := range prices {
+			fmt.Println(dp.String() + annotations[i])
 		}
 	}
 }
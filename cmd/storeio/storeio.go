@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+	"github.com/tsilvers/realtime-securities/persist/transfer"
+)
+
+// main exports stored daily prices, one minute sales or option expirations to CSV, JSON or JSON Lines files,
+// or imports them from those files into the store. The file format is given by the file's extension.
+// Imported data is validated, and nothing is imported if any record is invalid.
+func main() {
+	args := os.Args[1:]
+
+	if len(args) < 3 {
+		usage()
+	}
+	command, kind, fname := args[0], args[1], args[2]
+	if kind != "prices" && kind != "minutes" && kind != "expirations" {
+		usage()
+	}
+
+	format, err := transfer.FormatOf(fname)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	switch {
+	case command == "export":
+		symbols := args[3:]
+		if len(symbols) == 0 {
+			symbols = stock.GetSymbols()
+		}
+		err = export(store, kind, fname, format, symbols)
+	case command == "import" && len(args) == 3:
+		err = load(store, kind, fname, format)
+	default:
+		usage()
+	}
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func export(store persist.Store, kind, fname string, format transfer.Format, symbols []string) error {
+	file, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch kind {
+	case "prices":
+		var records []transfer.PriceRecord
+		for _, symbol := range symbols {
+			prices, err := store.LoadPrices(symbol)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			records = append(records, transfer.PriceRecords(symbol, prices)...)
+		}
+		err = transfer.WritePrices(file, format, records)
+		fmt.Printf("%d daily prices exported\n", len(records))
+
+	case "minutes":
+		var records []transfer.MinuteRecord
+		for _, symbol := range symbols {
+			sales, err := store.LoadOneMinSales(symbol, time.Time{}, time.Now())
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			records = append(records, transfer.MinuteRecords(symbol, sales)...)
+		}
+		err = transfer.WriteMinutes(file, format, records)
+		fmt.Printf("%d one minute sales exported\n", len(records))
+
+	case "expirations":
+		var records []transfer.ExpirationRecord
+		for _, symbol := range symbols {
+			exps, err := store.LoadExpirations(symbol)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			records = append(records, transfer.ExpirationRecords(symbol, exps)...)
+		}
+		err = transfer.WriteExpirations(file, format, records)
+		fmt.Printf("%d option expirations exported\n", len(records))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+func load(store persist.Store, kind, fname string, format transfer.Format) error {
+	file, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	invalid := 0
+	check := func(err error) {
+		if err != nil {
+			log.Println(err)
+			invalid++
+		}
+	}
+
+	switch kind {
+	case "prices":
+		records, err := transfer.ReadPrices(file, format)
+		if err != nil {
+			return err
+		}
+
+		bySymbol := make(map[string][]stock.DailyPriceGob)
+		for _, r := range records {
+			price, err := r.ToGob()
+			check(err)
+			bySymbol[r.Symbol] = append(bySymbol[r.Symbol], price)
+		}
+		if invalid > 0 {
+			return fmt.Errorf("nothing imported: %d invalid daily prices", invalid)
+		}
+
+		for symbol, prices := range bySymbol {
+			if err := store.UpsertPrices(symbol, prices); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("%d daily prices imported\n", len(records))
+
+	case "minutes":
+		records, err := transfer.ReadMinutes(file, format)
+		if err != nil {
+			return err
+		}
+
+		bySymbol := make(map[string][]stock.OneMinSaleGob)
+		for _, r := range records {
+			sale, err := r.ToGob()
+			check(err)
+			bySymbol[r.Symbol] = append(bySymbol[r.Symbol], sale)
+		}
+		if invalid > 0 {
+			return fmt.Errorf("nothing imported: %d invalid one minute sales", invalid)
+		}
+
+		for symbol, sales := range bySymbol {
+			if err := store.SaveOneMinSales(symbol, sales); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("%d one minute sales imported\n", len(records))
+
+	case "expirations":
+		records, err := transfer.ReadExpirations(file, format)
+		if err != nil {
+			return err
+		}
+
+		bySymbol := make(map[string][]option.ExpirationGob)
+		for _, r := range records {
+			exp, err := r.ToGob()
+			check(err)
+			bySymbol[r.Symbol] = append(bySymbol[r.Symbol], exp)
+		}
+		if invalid > 0 {
+			return fmt.Errorf("nothing imported: %d invalid option expirations", invalid)
+		}
+
+		// Each symbol's imported expirations replace its stored expirations.
+		for symbol, exps := range bySymbol {
+			if err := store.SaveExpirations(symbol, exps); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("%d option expirations imported\n", len(records))
+	}
+
+	return nil
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: storeio export prices|minutes|expirations File [Symbol ...]\n"+
+		"       storeio import prices|minutes|expirations File\n"+
+		"File formats: .csv, .json, .jsonl\n\n")
+	os.Exit(1)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+	"github.com/tsilvers/realtime-securities/provider"
+)
+
+// providerDays is how many days of one minute sales the data provider serves.
+const providerDays = 10
+
+// main retrieves one minute sales newer than those already stored and persists them.
+// Run it at least every couple of weeks, since older sales cannot be retrieved.
+func main() {
+	ds := provider.GetProvider("Tradier")
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	symbols := os.Args[1:]
+	if len(symbols) == 0 {
+		symbols = stock.GetSymbols()
+	}
+
+	year, month, day := time.Now().AddDate(0, 0, -providerDays+1).Date()
+	earliest := time.Date(year, month, day, stock.MarketOpenHour, stock.MarketOpenMinute, 0, 0, time.UTC)
+
+	for _, symbol := range symbols {
+		last, err := store.LastOneMinSaleTime(symbol)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		start := earliest
+		if last.After(start) {
+			start = last
+		} else if !last.IsZero() {
+			log.Printf("%s: one minute sales after %s may have been missed\n", symbol, last.Format("2006-01-02 15:04"))
+		}
+
+		var saleGobs []stock.OneMinSaleGob
+		// The last stored minute may have been saved while it was still trading, so it is replaced.
+		for _, sale := range ds.GetTimeSales(symbol, start) {
+			if !sale.StartTime().Before(last) {
+				saleGobs = append(saleGobs, sale.ToGob())
+			}
+		}
+
+		if err = store.SaveOneMinSales(symbol, saleGobs); err != nil {
+			log.Println(err)
+			continue
+		}
+
+		fmt.Printf("%-8s %6d one minute sales saved\n", symbol, len(saleGobs))
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/tsilvers/realtime-securities/config"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+)
+
+// main decodes and validates every file of the gob store, reporting corrupt, unreadable and invalid files,
+// files of symbols no longer in the symbols list, and temporary files left by interrupted writes.
+// With -quarantine, files with problems are moved to the store's quarantine directory. With -repair,
+// damaged files are rewritten with their valid records and the others are quarantined.
+func main() {
+	args := os.Args[1:]
+	mode := persist.ReportOnly
+	if len(args) > 0 {
+		switch args[0] {
+		case "-quarantine":
+			mode = persist.Quarantine
+			args = args[1:]
+		case "-repair":
+			mode = persist.Repair
+			args = args[1:]
+		}
+	}
+	if len(args) > 0 {
+		usage()
+	}
+
+	var symbols []string
+	for _, symbol := range stock.GetSymbols() {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	fmt.Printf("Verifying store %s...\n\n", config.StoreDir())
+
+	checks, err := persist.Verify(symbols, mode)
+
+	problems, repaired, quarantined, failed := 0, 0, 0, 0
+	for _, check := range checks {
+		if check.OK() {
+			continue
+		}
+		if problems == 0 {
+			fmt.Print(persist.VerifyHeader())
+		}
+		fmt.Println(check)
+
+		problems++
+		switch {
+		case check.Err != nil:
+			failed++
+		case check.Action == "repaired":
+			repaired++
+		case check.Action == "quarantined":
+			quarantined++
+		}
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if problems == 0 {
+		fmt.Printf("All %d files are OK.\n", len(checks))
+		return
+	}
+
+	fmt.Printf("\n%d files checked, %d with problems.\n", len(checks), problems)
+	if mode != persist.ReportOnly {
+		fmt.Printf("%d repaired, %d quarantined, %d failed. Originals of repaired and quarantined files are in %s.\n",
+			repaired, quarantined, failed, persist.QuarantineDir())
+	}
+
+	if mode == persist.ReportOnly || failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: verifystore [-quarantine | -repair]\n\n")
+	os.Exit(1)
+}
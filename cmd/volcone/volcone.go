@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/analysis/volatility"
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+	_ "github.com/tsilvers/realtime-securities/persist/sqlite"
+	"github.com/tsilvers/realtime-securities/provider"
+)
+
+// ivFilter keeps expirations within the longest cone window.
+var ivFilter = option.ExpirationFilter{MaxDays: 180}
+
+// main displays realized volatility cones from persisted daily prices,
+// with the at-the-money implied volatility of each expiration placed on the cone.
+func main() {
+	ds := provider.GetProvider("Tradier")
+
+	store, err := persist.Open()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer store.Close()
+
+	if len(os.Args) > 2 {
+		usage()
+	}
+	lookback := volatility.DefaultLookback
+	if len(os.Args) == 2 {
+		if lookback, err = strconv.Atoi(os.Args[1]); err != nil {
+			usage()
+		}
+	}
+
+	now := time.Now()
+	symbols := stock.GetSymbols()
+	quotes := ds.GetQuotes(symbols)
+
+	for _, q := range quotes {
+		symbol := q.Symbol()
+
+		prices, err := persist.LoadDailyPrices(store, symbol)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		cone, err := volatility.NewCone(symbol, prices, volatility.DefaultWindows, lookback)
+		if err != nil {
+			log.Printf("Volatility cone for %s: %s\n", symbol, err)
+			continue
+		}
+		fmt.Print(cone)
+
+		fmt.Print(volatility.IVPointHeader())
+		for _, exp := range ivFilter.Filter(ds.GetOptionExpirations(symbol)) {
+			chain, err := ds.GetOptionChain(symbol, exp)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			em, err := chain.ExpectedMove(q.Last(), now)
+			if err != nil || em.IV == 0 {
+				continue
+			}
+
+			p, err := cone.PlaceIV(em.Expiration, em.IV, now)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			fmt.Println(p)
+		}
+		fmt.Println()
+	}
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: volcone [LookbackSessions]\n\n")
+	os.Exit(1)
+}
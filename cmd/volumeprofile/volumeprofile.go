@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/analysis/volume"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/provider"
+)
+
+const (
+	salesDays      = 5    // Days of one minute sales retrieved.
+	defaultBucket  = 0.10 // Default volume profile bucket size.
+	histogramWidth = 50
+)
+
+// main displays volume profiles of each recent session and the latest session's histogram from one minute sales,
+// and the VWAP anchored at a chosen time with its standard deviation bands.
+func main() {
+	ds := provider.GetProvider("Tradier")
+
+	if len(os.Args) < 2 || len(os.Args) > 4 {
+		usage()
+	}
+	symbol := os.Args[1]
+
+	bucketSize := defaultBucket
+	if len(os.Args) >= 3 {
+		var err error
+		if bucketSize, err = strconv.ParseFloat(os.Args[2], 64); err != nil {
+			usage()
+		}
+	}
+
+	var anchor time.Time
+	if len(os.Args) == 4 {
+		var err error
+		if anchor, err = time.Parse("2006-01-02 15:04", os.Args[3]); err != nil {
+			usage()
+		}
+	}
+
+	year, month, day := time.Now().AddDate(0, 0, -salesDays).Date()
+	start := time.Date(year, month, day, stock.MarketOpenHour, stock.MarketOpenMinute, 0, 0, time.UTC)
+	bars := stock.OneMinSaleBars(ds.GetTimeSales(symbol, start))
+	if len(bars) == 0 {
+		log.Fatalf("No one minute sales for %s\n", symbol)
+	}
+
+	profiles, err := volume.SessionProfiles(bars, bucketSize, volume.DefaultValueArea)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Printf("%s volume profiles\n", symbol)
+	fmt.Print(volume.ProfileHeader())
+	for _, p := range profiles {
+		fmt.Println(p)
+	}
+	fmt.Println()
+	fmt.Print(profiles[len(profiles)-1].Histogram(histogramWidth))
+
+	if anchor.IsZero() {
+		anchor = bars[0].Start()
+	}
+	avwap := volume.AnchoredVWAPSeries(bars, anchor)
+	v := avwap[len(avwap)-1]
+	_, last := bars[len(bars)-1].OpenClose()
+
+	fmt.Printf("\nVWAP anchored at %s: %.2f  (last %.2f)\n", anchor.Format("2006-01-02 15:04"), v.VWAP, last)
+	fmt.Printf("  -2σ %.2f  -1σ %.2f  +1σ %.2f  +2σ %.2f\n", v.Band(-2), v.Band(-1), v.Band(1), v.Band(2))
+}
+
+func usage() {
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: volumeprofile Symbol [BucketSize [AnchorTime (yyyy-mm-dd hh:mm)]]\n\n")
+	os.Exit(1)
+}
@@ -0,0 +1,211 @@
+// Package config locates the data used by all packages: the store, the list of stock symbols and
+// data provider credentials, so that commands work from any directory.
+//
+// The data root is, in order of precedence:
+//   - the REALTIME_SECURITIES_ROOT environment variable,
+//   - the root setting of the config file,
+//   - the repo's resources directory, when run from a cmd directory of the repo (logged, as it depends on
+//     the working directory),
+//   - $XDG_DATA_HOME/realtime-securities, or ~/.local/share/realtime-securities if XDG_DATA_HOME is not set.
+//
+// The config file is given by the REALTIME_SECURITIES_CONFIG environment variable, or is
+// $XDG_CONFIG_HOME/realtime-securities/config (~/.config/realtime-securities/config by default).
+// It is optional, and holds "key = value" lines for the root, store, backend, symbols and credentials settings;
+// lines starting with # are comments. Relative paths are relative to the config file's directory
+// for root, and to the data root for the others. The backend setting names the kind of store used by
+// the commands (see persist.Open), gob files by default; the REALTIME_SECURITIES_BACKEND environment
+// variable overrides it.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	appName    = "realtime-securities"
+	RootEnv    = "REALTIME_SECURITIES_ROOT"
+	ConfigEnv  = "REALTIME_SECURITIES_CONFIG"
+	BackendEnv = "REALTIME_SECURITIES_BACKEND"
+	repoRoot   = "../../resources" // Resources directory relative to a cmd directory of the repo.
+	repoCmd    = "../../cmd"       // Commands directory relative to a cmd directory of the repo.
+	configName = "config"
+)
+
+// Config holds the locations of the data.
+type Config struct {
+	Root        string // Data root directory.
+	Store       string // Persisted market data directory, default Root/store.
+	Backend     string // Kind of store kept in the Store directory, default "gob".
+	Symbols     string // List of stock symbols, default Root/data/symbols.dat.
+	Credentials string // Data provider credentials directory, default Root/provider-auth.
+}
+
+var (
+	once   sync.Once
+	config Config
+)
+
+// Get returns the configuration, which is loaded on first use.
+// It stops the program if the config file cannot be read.
+func Get() Config {
+	once.Do(func() {
+		var err error
+		if config, err = load(); err != nil {
+			log.Fatalln("Error loading configuration:", err)
+		}
+	})
+
+	return config
+}
+
+// StoreDir returns the directory of persisted market data.
+func StoreDir() string {
+	return Get().Store
+}
+
+// StoreBackend returns the kind of store used for persisted market data.
+func StoreBackend() string {
+	return Get().Backend
+}
+
+// SymbolsFile returns the file listing the stock symbols.
+func SymbolsFile() string {
+	return Get().Symbols
+}
+
+// CredentialsFile returns the file holding the named data provider's credentials.
+func CredentialsFile(provider string) string {
+	return filepath.Join(Get().Credentials, provider)
+}
+
+func load() (c Config, err error) {
+	settings, configDir, err := readConfigFile()
+	if err != nil {
+		return c, err
+	}
+
+	switch {
+	case os.Getenv(RootEnv) != "":
+		c.Root = os.Getenv(RootEnv)
+	case settings["root"] != "":
+		c.Root = resolve(configDir, settings["root"])
+	case isDir(repoRoot) && isDir(repoCmd):
+		// Resolved now, so the root does not move if the working directory changes.
+		if c.Root, err = filepath.Abs(repoRoot); err != nil {
+			return c, fmt.Errorf("could not find data directory: %w", err)
+		}
+		log.Println("Using the repo's resources directory as the data root:", c.Root)
+	default:
+		if c.Root, err = xdgDir("XDG_DATA_HOME", ".local/share"); err != nil {
+			return c, err
+		}
+	}
+
+	c.Store = resolve(c.Root, settingOr(settings, "store", "store"))
+	c.Backend = settingOr(settings, "backend", "gob")
+	if os.Getenv(BackendEnv) != "" {
+		c.Backend = os.Getenv(BackendEnv)
+	}
+	c.Backend = strings.ToLower(c.Backend)
+	c.Symbols = resolve(c.Root, settingOr(settings, "symbols", filepath.Join("data", "symbols.dat")))
+	c.Credentials = resolve(c.Root, settingOr(settings, "credentials", "provider-auth"))
+
+	return
+}
+
+// readConfigFile returns the settings in the config file and its directory. A missing config file has no settings.
+func readConfigFile() (settings map[string]string, dir string, err error) {
+	settings = make(map[string]string)
+
+	fname := os.Getenv(ConfigEnv)
+	if fname == "" {
+		configHome, err := xdgDir("XDG_CONFIG_HOME", ".config")
+		if err != nil {
+			return settings, "", nil // No config file without a home directory.
+		}
+		fname = filepath.Join(configHome, configName)
+	}
+	dir = filepath.Dir(fname)
+
+	file, err := os.Open(fname)
+	if errors.Is(err, fs.ErrNotExist) && os.Getenv(ConfigEnv) == "" {
+		return settings, dir, nil
+	}
+	if err != nil {
+		return settings, dir, fmt.Errorf("could not read config file %s: %w", fname, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(text, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !found || (key != "root" && key != "store" && key != "backend" && key != "symbols" && key != "credentials") {
+			return settings, dir, fmt.Errorf("invalid setting in config file %s, line %d: %s", fname, line, text)
+		}
+		settings[key] = strings.TrimSpace(value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return settings, dir, fmt.Errorf("could not read config file %s: %w", fname, err)
+	}
+
+	return settings, dir, nil
+}
+
+// xdgDir returns this application's directory under the XDG base directory in the environment variable,
+// or under the default directory in the user's home.
+func xdgDir(env, defaultDir string) (string, error) {
+	base := os.Getenv(env)
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not find data directory: %w", err)
+		}
+		base = filepath.Join(home, defaultDir)
+	}
+
+	return filepath.Join(base, appName), nil
+}
+
+func settingOr(settings map[string]string, key, defaultValue string) string {
+	if value := settings[key]; value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+// resolve returns the path relative to dir, unless it is absolute or starts with ~/ (the user's home).
+func resolve(dir, path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// isolate clears the configuration environment, giving the test an empty home directory and
+// running it from an empty working directory. It returns the home directory.
+func isolate(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	for _, env := range []string{RootEnv, ConfigEnv, BackendEnv, "XDG_DATA_HOME", "XDG_CONFIG_HOME"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("HOME", home)
+	chdir(t, t.TempDir())
+
+	return home
+}
+
+// chdir changes the working directory for the rest of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// writeConfig writes a config file in its own directory and points the environment at it.
+func writeConfig(t *testing.T, text string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	fname := filepath.Join(dir, configName)
+	if err := os.WriteFile(fname, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigEnv, fname)
+
+	return dir
+}
+
+func mkdirs(t *testing.T, dirs ...string) {
+	t.Helper()
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func loadConfig(t *testing.T) Config {
+	t.Helper()
+
+	c, err := load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func assertPath(t *testing.T, name, got, want string) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("%s = %s, want %s", name, got, want)
+	}
+}
+
+func TestRootPrecedence(t *testing.T) {
+	home := isolate(t)
+
+	// The repo's resources directory, seen from a cmd directory.
+	repo := t.TempDir()
+	mkdirs(t, filepath.Join(repo, "cmd", "quote"), filepath.Join(repo, "resources"))
+	chdir(t, filepath.Join(repo, "cmd", "quote"))
+
+	xdg := filepath.Join(home, "xdg")
+	t.Setenv("XDG_DATA_HOME", xdg)
+	configDir := writeConfig(t, "root = data\n")
+	env := filepath.Join(home, "env")
+	t.Setenv(RootEnv, env)
+
+	assertPath(t, "root from the environment", loadConfig(t).Root, env)
+
+	t.Setenv(RootEnv, "")
+	assertPath(t, "root from the config file", loadConfig(t).Root, filepath.Join(configDir, "data"))
+
+	writeConfig(t, "# No root.\n")
+	assertPath(t, "repo root", loadConfig(t).Root, filepath.Join(repo, "resources"))
+
+	chdir(t, home)
+	assertPath(t, "XDG data root", loadConfig(t).Root, filepath.Join(xdg, appName))
+
+	t.Setenv("XDG_DATA_HOME", "")
+	assertPath(t, "default data root", loadConfig(t).Root, filepath.Join(home, ".local", "share", appName))
+}
+
+func TestRepoRootNeedsCmdDirectory(t *testing.T) {
+	home := isolate(t)
+
+	// A resources directory two levels up, outside of the repo.
+	dir := t.TempDir()
+	mkdirs(t, filepath.Join(dir, "a", "b"), filepath.Join(dir, "resources"))
+	chdir(t, filepath.Join(dir, "a", "b"))
+
+	assertPath(t, "root", loadConfig(t).Root, filepath.Join(home, ".local", "share", appName))
+}
+
+func TestDefaultConfigFile(t *testing.T) {
+	home := isolate(t)
+
+	mkdirs(t, filepath.Join(home, ".config", appName))
+	fname := filepath.Join(home, ".config", appName, configName)
+	if err := os.WriteFile(fname, []byte("root = ~/market\nbackend = SQLite\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := loadConfig(t)
+	assertPath(t, "root", c.Root, filepath.Join(home, "market"))
+	assertPath(t, "backend", c.Backend, "sqlite")
+
+	t.Setenv(BackendEnv, "gob")
+	assertPath(t, "backend from the environment", loadConfig(t).Backend, "gob")
+}
+
+func TestSettingsUnderRoot(t *testing.T) {
+	home := isolate(t)
+	root := filepath.Join(home, "root")
+	t.Setenv(RootEnv, root)
+
+	c := loadConfig(t)
+	assertPath(t, "default store", c.Store, filepath.Join(root, "store"))
+	assertPath(t, "default backend", c.Backend, "gob")
+	assertPath(t, "default symbols", c.Symbols, filepath.Join(root, "data", "symbols.dat"))
+	assertPath(t, "default credentials", c.Credentials, filepath.Join(root, "provider-auth"))
+
+	writeConfig(t, "store = db\nsymbols = /etc/symbols.dat\n  credentials   =   ~/auth  \n")
+	c = loadConfig(t)
+	assertPath(t, "store", c.Store, filepath.Join(root, "db"))
+	assertPath(t, "symbols", c.Symbols, "/etc/symbols.dat")
+	assertPath(t, "credentials", c.Credentials, filepath.Join(home, "auth"))
+}
+
+func TestConfigFileErrors(t *testing.T) {
+	isolate(t)
+
+	writeConfig(t, "root = data\ncolor = blue\n")
+	if _, err := load(); err == nil {
+		t.Error("unknown setting: want error")
+	}
+
+	writeConfig(t, "root\n")
+	if _, err := load(); err == nil {
+		t.Error("setting without a value: want error")
+	}
+
+	// A missing config file is an error only if it was named in the environment.
+	t.Setenv(ConfigEnv, filepath.Join(t.TempDir(), "missing"))
+	if _, err := load(); err == nil {
+		t.Error("missing config file from the environment: want error")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	home := isolate(t)
+
+	tests := []struct {
+		dir, path, want string
+	}{
+		{"/data", "store", "/data/store"},
+		{"/data", "../store", "/store"},
+		{"/data", "/var/store", "/var/store"},
+		{"/data", "~/store", filepath.Join(home, "store")},
+		{"/data", "~store", "/data/~store"},
+	}
+
+	for _, tt := range tests {
+		assertPath(t, "resolve("+tt.dir+", "+tt.path+")", resolve(tt.dir, tt.path), tt.want)
+	}
+}
@@ -0,0 +1,169 @@
+package markets
+
+import "time"
+
+// EarlyCloseHour is the hour the markets close on early close days (see IsEarlyClose).
+const EarlyCloseHour = 13
+
+// newYork is the markets' time zone, or a fixed Eastern Standard Time zone if the time zone database is not available.
+var newYork = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+
+	return loc
+}()
+
+// MarketTime returns the New York wall clock time at t, in UTC, as market data times are stored.
+func MarketTime(t time.Time) time.Time {
+	t = t.In(newYork)
+
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// IsTradingDay returns true if the US stock markets are open on the date.
+func IsTradingDay(date time.Time) bool {
+	dow := date.Weekday()
+	if dow == time.Saturday || dow == time.Sunday {
+		return false
+	}
+
+	return !IsHoliday(date)
+}
+
+// specialClosures are the unscheduled weekday NYSE closures, keyed by "YYYY-MM-DD".
+var specialClosures = map[string]string{
+	"2001-09-11": "September 11 attacks",
+	"2001-09-12": "September 11 attacks",
+	"2001-09-13": "September 11 attacks",
+	"2001-09-14": "September 11 attacks",
+	"2004-06-11": "National Day of Mourning for Ronald Reagan",
+	"2007-01-02": "National Day of Mourning for Gerald Ford",
+	"2012-10-29": "Hurricane Sandy",
+	"2012-10-30": "Hurricane Sandy",
+	"2018-12-05": "National Day of Mourning for George H. W. Bush",
+	"2025-01-09": "National Day of Mourning for Jimmy Carter",
+}
+
+// IsHoliday returns true if the date is a weekday on which the NYSE is closed for a holiday or a special closure.
+func IsHoliday(date time.Time) bool {
+	year, month, day := date.Date()
+
+	if _, ok := specialClosures[date.Format("2006-01-02")]; ok {
+		return true
+	}
+
+	for _, holiday := range holidays(year) {
+		hYear, hMonth, hDay := holiday.Date()
+		if hYear == year && hMonth == month && hDay == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// holidays returns the observed NYSE holidays for the year.
+func holidays(year int) []time.Time {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	list := []time.Time{
+		newYearsDay(year),
+		nthWeekday(year, time.January, time.Monday, 3),    // Martin Luther King Jr. Day
+		nthWeekday(year, time.February, time.Monday, 3),   // Washington's Birthday
+		easter(year).AddDate(0, 0, -2),                    // Good Friday
+		lastWeekday(year, time.May, time.Monday),          // Memorial Day
+		observed(date(time.July, 4)),                      // Independence Day
+		nthWeekday(year, time.September, time.Monday, 1),  // Labor Day
+		nthWeekday(year, time.November, time.Thursday, 4), // Thanksgiving Day
+		observed(date(time.December, 25)),                 // Christmas Day
+	}
+
+	if year >= 2022 {
+		list = append(list, observed(date(time.June, 19))) // Juneteenth
+	}
+
+	return list
+}
+
+// newYearsDay returns the observed New Year's Day, which is not moved back to Friday when on a Saturday.
+func newYearsDay(year int) time.Time {
+	day := time.Date(year, time.January, 1, 12, 0, 0, 0, time.UTC)
+	if day.Weekday() == time.Sunday {
+		return day.AddDate(0, 0, 1)
+	}
+
+	return day
+}
+
+// observed moves a holiday falling on a weekend to the nearest weekday.
+func observed(day time.Time) time.Time {
+	switch day.Weekday() {
+	case time.Saturday:
+		return day.AddDate(0, 0, -1)
+	case time.Sunday:
+		return day.AddDate(0, 0, 1)
+	}
+
+	return day
+}
+
+// nthWeekday returns the nth occurrence (starting at 1) of the weekday in the month.
+func nthWeekday(year int, month time.Month, dow time.Weekday, n int) time.Time {
+	day := time.Date(year, month, 1, 12, 0, 0, 0, time.UTC)
+	offset := (int(dow) - int(day.Weekday()) + 7) % 7
+
+	return day.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday returns the last occurrence of the weekday in the month.
+func lastWeekday(year int, month time.Month, dow time.Weekday) time.Time {
+	day := time.Date(year, month+1, 0, 12, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) - int(dow) + 7) % 7
+
+	return day.AddDate(0, 0, -offset)
+}
+
+// easter returns Easter Sunday using the anonymous Gregorian algorithm.
+func easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day, 12, 0, 0, 0, time.UTC)
+}
+
+// IsEarlyClose returns true if the US stock markets close early, at 1:00 pm, on the date.
+// They close early on July 3, the day after Thanksgiving and Christmas Eve when those are trading days.
+func IsEarlyClose(date time.Time) bool {
+	if !IsTradingDay(date) {
+		return false
+	}
+
+	year, month, day := date.Date()
+	switch {
+	case month == time.July && day == 3:
+		return true
+	case month == time.December && day == 24:
+		return true
+	case month == time.November:
+		_, _, thanksgiving := nthWeekday(year, time.November, time.Thursday, 4).Date()
+		return day == thanksgiving+1
+	}
+
+	return false
+}
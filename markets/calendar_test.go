@@ -0,0 +1,74 @@
+package markets
+
+import (
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestIsTradingDay(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"weekday", day(2024, time.March, 4), true},
+		{"Saturday", day(2024, time.March, 2), false},
+		{"New Year's Day on Sunday", day(2023, time.January, 2), false},
+		{"New Year's Day on Saturday", day(2021, time.December, 31), true},
+		{"Good Friday", day(2024, time.March, 29), false},
+		{"Juneteenth", day(2024, time.June, 19), false},
+		{"Juneteenth before 2022", day(2021, time.June, 18), true},
+		{"Independence Day on Saturday", day(2020, time.July, 3), false},
+		{"Thanksgiving", day(2024, time.November, 28), false},
+		{"Christmas on Sunday", day(2022, time.December, 26), false},
+		{"Hurricane Sandy", day(2012, time.October, 29), false},
+		{"Hurricane Sandy second day", day(2012, time.October, 30), false},
+		{"after Hurricane Sandy", day(2012, time.October, 31), true},
+		{"Bush mourning", day(2018, time.December, 5), false},
+		{"Carter mourning", day(2025, time.January, 9), false},
+		{"after Carter mourning", day(2025, time.January, 10), true},
+	}
+
+	for _, tt := range tests {
+		if got := IsTradingDay(tt.date); got != tt.want {
+			t.Errorf("%s: IsTradingDay(%s) = %v, want %v", tt.name, tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestSpecialClosuresAreWeekdays(t *testing.T) {
+	for date, reason := range specialClosures {
+		d, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			t.Fatalf("%s (%s): %v", date, reason, err)
+		}
+		if dow := d.Weekday(); dow == time.Saturday || dow == time.Sunday {
+			t.Errorf("%s (%s) is a %s", date, reason, dow)
+		}
+	}
+}
+
+func TestIsEarlyClose(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"July 3", day(2024, time.July, 3), true},
+		{"July 3 on Friday holiday", day(2020, time.July, 3), false},
+		{"day after Thanksgiving", day(2024, time.November, 29), true},
+		{"Christmas Eve", day(2024, time.December, 24), true},
+		{"Christmas Eve on Sunday", day(2023, time.December, 24), false},
+		{"regular day", day(2024, time.December, 23), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsEarlyClose(tt.date); got != tt.want {
+			t.Errorf("%s: IsEarlyClose(%s) = %v, want %v", tt.name, tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package option
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const DefaultBinomialSteps = 200
+
+// Dividend holds a discrete cash dividend paid by the underlying stock.
+type Dividend struct {
+	ExDate time.Time
+	Amount float64 // Cash amount per share.
+}
+
+// BinomialResult holds the value of an American option from a binomial tree.
+type BinomialResult struct {
+	Greeks                         // American value and sensitivities.
+	European             float64   // Value of the same contract without early exercise.
+	EarlyExercisePremium float64   // American value less European value.
+	ExerciseNow          bool      // Immediate exercise is worth more than holding the option.
+	ExerciseDate         time.Time // First time early exercise is optimal if the stock price is unchanged, zero if never.
+}
+
+// Binomial values an American option with a Cox-Ross-Rubinstein tree.
+// Discrete dividends going ex between the market time and expiration are handled with the escrowed
+// dividend model: the tree is built on the stock price less the present value of those dividends, and
+// the remaining dividends are added back when testing each node for early exercise.
+// Vega and Rho are not computed by the tree and are left at zero.
+func Binomial(o Option, m Market, dividends []Dividend, steps int) (r BinomialResult, err error) {
+	if err = m.Validate(); err != nil {
+		return
+	}
+
+	if steps < 2 {
+		return r, fmt.Errorf("binomial tree needs at least 2 steps: %d", steps)
+	}
+
+	for _, div := range dividends {
+		if div.Amount < 0 {
+			return r, fmt.Errorf("invalid dividend amount: %g; ex-date: %s", div.Amount, div.ExDate.Format("2006-01-02"))
+		}
+	}
+
+	t := o.YearsToExpiration(m.Time)
+	vol := m.volatility(o)
+	if t <= 0 || vol <= 0 {
+		r.Price = o.Intrinsic(m.Underlying)
+		r.European = r.Price
+		r.ExerciseNow = r.Price > 0
+		if r.ExerciseNow {
+			r.ExerciseDate = m.Time
+		}
+		return
+	}
+
+	dt := t / float64(steps)
+	u := math.Exp(vol * math.Sqrt(dt))
+	d := 1 / u
+	p := (math.Exp((m.Rate-m.Yield)*dt) - d) / (u - d)
+	if p <= 0 || p >= 1 {
+		return r, fmt.Errorf("binomial tree is unstable with %d steps; option: %s", steps, o)
+	}
+	disc := math.Exp(-m.Rate * dt)
+
+	// Present value at step i of the dividends going ex after step i.
+	divTimes := make([]float64, 0, len(dividends))
+	divAmounts := make([]float64, 0, len(dividends))
+	for _, div := range dividends {
+		tau := div.ExDate.Sub(m.Time).Hours() / 24 / daysPerYear
+		if tau > 0 && tau <= t {
+			divTimes = append(divTimes, tau)
+			divAmounts = append(divAmounts, div.Amount)
+		}
+	}
+	pvDividends := func(step int) float64 {
+		ti := float64(step) * dt
+		pv := 0.0
+		for k, tau := range divTimes {
+			if tau > ti {
+				pv += divAmounts[k] * math.Exp(-m.Rate*(tau-ti))
+			}
+		}
+		return pv
+	}
+
+	escrowed := m.Underlying - pvDividends(0)
+	if escrowed <= 0 {
+		return r, fmt.Errorf("dividends exceed the underlying price: %g", m.Underlying)
+	}
+
+	// Stock price at node j (number of up moves) of step i.
+	price := func(i, j int) float64 {
+		return escrowed*math.Pow(u, float64(2*j-i)) + pvDividends(i)
+	}
+
+	american := make([]float64, steps+1)
+	european := make([]float64, steps+1)
+	exercised := make([]bool, steps+1)
+	for j := 0; j <= steps; j++ {
+		american[j] = o.Intrinsic(price(steps, j))
+		european[j] = american[j]
+	}
+
+	// Values at steps 1 and 2 are kept for the greeks.
+	var step1, step2 [3]float64
+	for i := steps - 1; i >= 0; i-- {
+		for j := 0; j <= i; j++ {
+			european[j] = disc * (p*european[j+1] + (1-p)*european[j])
+			hold := disc * (p*american[j+1] + (1-p)*american[j])
+			exercise := o.Intrinsic(price(i, j))
+			american[j] = math.Max(hold, exercise)
+			exercised[j] = exercise > 0 && exercise >= hold
+		}
+
+		// Even steps have a node at the unchanged price. Odd steps have nodes either side of it, where exercise
+		// must be optimal at both.
+		if mid := i / 2; exercised[mid] && (i%2 == 0 || exercised[mid+1]) {
+			r.ExerciseDate = m.Time.Add(time.Duration(float64(i) * dt * daysPerYear * 24 * float64(time.Hour)))
+			r.ExerciseNow = i == 0
+		}
+		if i == 2 {
+			copy(step2[:], american[:3])
+		}
+		if i == 1 {
+			copy(step1[:2], american[:2])
+		}
+	}
+
+	r.Price = american[0]
+	r.European = european[0]
+	r.EarlyExercisePremium = r.Price - r.European
+
+	s10, s11 := price(1, 0), price(1, 1)
+	s20, s21, s22 := price(2, 0), price(2, 1), price(2, 2)
+	r.Delta = (step1[1] - step1[0]) / (s11 - s10)
+	deltaUp := (step2[2] - step2[1]) / (s22 - s21)
+	deltaDown := (step2[1] - step2[0]) / (s21 - s20)
+	r.Gamma = (deltaUp - deltaDown) / ((s22 - s20) / 2)
+	r.Theta = (step2[1] - r.Price) / (2 * dt) / daysPerYear
+
+	return
+}
@@ -0,0 +1,131 @@
+package option
+
+import (
+	"testing"
+	"time"
+)
+
+// Without dividends, early exercise of a call is never optimal, so the American call and the European value of
+// the tree both converge to the Black-Scholes price.
+func TestBinomialConvergesToBlackScholes(t *testing.T) {
+	for _, tc := range []struct {
+		underlying, strike, rate, vol, years float64
+		call                                 bool
+	}{
+		{42, 40, 0.10, 0.20, 0.5, true},
+		{42, 40, 0.10, 0.20, 0.5, false},
+		{100, 110, 0.03, 0.35, 1, true},
+		{100, 90, 0.03, 0.35, 1, false},
+	} {
+		m := testMarket(tc.underlying, tc.rate, 0, tc.vol, tc.years)
+		o := testOption(t, tc.strike, tc.call)
+
+		bs, err := BlackScholes(o, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		prevErr := 0.0
+		for i, steps := range []int{50, 200, 1000} {
+			r, err := Binomial(o, m, nil, steps)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// CRR prices oscillate around the limit, so the error is compared with a loose bound on each refinement.
+			errNow := r.European - bs.Price
+			if errNow < 0 {
+				errNow = -errNow
+			}
+			if i > 0 && errNow > prevErr*1.5 {
+				t.Errorf("%s with %d steps: error %.5f did not shrink from %.5f", o, steps, errNow, prevErr)
+			}
+			prevErr = errNow
+
+			if steps == 1000 {
+				assertNear(t, o.String()+" European", r.European, bs.Price, 0.005)
+				if tc.call {
+					assertNear(t, o.String()+" American call", r.Price, bs.Price, 0.005)
+					assertNear(t, o.String()+" delta", r.Delta, bs.Delta, 0.005)
+				}
+			}
+		}
+	}
+}
+
+func TestBinomialAmericanPutPremium(t *testing.T) {
+	for _, strike := range []float64{80, 100, 120} {
+		m := testMarket(100, 0.08, 0, 0.25, 1)
+		r, err := Binomial(testOption(t, strike, false), m, nil, DefaultBinomialSteps)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.Price < r.European {
+			t.Errorf("strike %g: American put %.4f is less than European put %.4f", strike, r.Price, r.European)
+		}
+		if r.EarlyExercisePremium < 0 {
+			t.Errorf("strike %g: early exercise premium %.4f is negative", strike, r.EarlyExercisePremium)
+		}
+	}
+
+	// Deep in the money with a high rate, the put is worth exercising now.
+	m := testMarket(50, 0.10, 0, 0.20, 1)
+	r, err := Binomial(testOption(t, 100, false), m, nil, DefaultBinomialSteps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.ExerciseNow || r.Price != 50 {
+		t.Errorf("deep in the money put = %.4f, exercise now %t, want 50, true", r.Price, r.ExerciseNow)
+	}
+}
+
+// A dividend paid before expiration makes early exercise of an in the money call worthwhile just before it.
+func TestBinomialCallDividend(t *testing.T) {
+	m := testMarket(100, 0.05, 0, 0.20, 0.5)
+	o := testOption(t, 80, true)
+	div := Dividend{ExDate: m.Time.Add(60 * 24 * time.Hour), Amount: 5}
+
+	r, err := Binomial(o, m, []Dividend{div}, DefaultBinomialSteps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.EarlyExercisePremium <= 0 {
+		t.Errorf("early exercise premium = %.4f, want positive", r.EarlyExercisePremium)
+	}
+	if r.ExerciseDate.IsZero() || r.ExerciseDate.After(div.ExDate) {
+		t.Errorf("exercise date = %s, want on or before the ex-date %s", r.ExerciseDate, div.ExDate)
+	}
+}
+
+// The exercise date is taken only at the unchanged stock price. Odd steps have no node there, and the node below it
+// is in the money for an at the money put.
+func TestBinomialExerciseDateUnchangedPrice(t *testing.T) {
+	m := testMarket(100, 0.10, 0, 0.20, 1)
+	o := testOption(t, 100, false)
+
+	for _, steps := range []int{DefaultBinomialSteps, DefaultBinomialSteps + 1} {
+		r, err := Binomial(o, m, nil, steps)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.EarlyExercisePremium <= 0 {
+			t.Errorf("%d steps: early exercise premium = %.4f, want positive", steps, r.EarlyExercisePremium)
+		}
+		if !r.ExerciseDate.IsZero() || r.ExerciseNow {
+			t.Errorf("%d steps: exercise date = %s, want none, since the put is never in the money at the unchanged price",
+				steps, r.ExerciseDate)
+		}
+	}
+
+	// In the money, exercise becomes optimal at the unchanged price before expiration.
+	r, err := Binomial(testOption(t, 120, false), m, nil, DefaultBinomialSteps+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ExerciseDate.IsZero() || !r.ExerciseDate.Before(o.ExpirationDate()) {
+		t.Errorf("in the money put exercise date = %s, want before expiration %s", r.ExerciseDate, o.ExpirationDate())
+	}
+}
@@ -0,0 +1,128 @@
+package option
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const daysPerYear = 365
+
+// Market holds the market conditions used to value an option.
+type Market struct {
+	Underlying float64   // Price of the underlying stock.
+	Rate       float64   // Annualized risk-free interest rate (0.02 = 2%).
+	Yield      float64   // Annualized continuous dividend yield (0.01 = 1%).
+	Volatility float64   // Annualized volatility used when the option has no implied volatility.
+	Time       time.Time // Valuation time.
+}
+
+func (m Market) Validate() error {
+	if m.Underlying <= 0 {
+		return fmt.Errorf("invalid underlying price: %g", m.Underlying)
+	}
+
+	if m.Volatility < 0 {
+		return fmt.Errorf("invalid volatility: %g", m.Volatility)
+	}
+
+	if m.Time.IsZero() {
+		return fmt.Errorf("valuation time is not set")
+	}
+
+	return nil
+}
+
+// volatility returns the option's implied volatility if known, otherwise the market volatility.
+func (m Market) volatility(o Option) float64 {
+	if o.IV > 0 {
+		return o.IV
+	}
+
+	return m.Volatility
+}
+
+// Greeks holds the theoretical value of one option share and its sensitivities.
+// Theta is per calendar day, Vega and Rho are per 1 percentage point change.
+type Greeks struct {
+	Price float64
+	Delta float64
+	Gamma float64
+	Theta float64
+	Vega  float64
+	Rho   float64
+}
+
+// Add returns the sum of two sets of greeks, with g2 weighted by qty.
+func (g Greeks) Add(g2 Greeks, qty float64) Greeks {
+	g.Price += g2.Price * qty
+	g.Delta += g2.Delta * qty
+	g.Gamma += g2.Gamma * qty
+	g.Theta += g2.Theta * qty
+	g.Vega += g2.Vega * qty
+	g.Rho += g2.Rho * qty
+
+	return g
+}
+
+// YearsToExpiration returns the time remaining until expiration as a fraction of a year.
+func (o Option) YearsToExpiration(now time.Time) float64 {
+	return o.expDate.Sub(now).Hours() / 24 / daysPerYear
+}
+
+// BlackScholes values a European option with the Black-Scholes-Merton model.
+// Options at or past expiration are valued at intrinsic value.
+func BlackScholes(o Option, m Market) (g Greeks, err error) {
+	if err = m.Validate(); err != nil {
+		return
+	}
+
+	s := m.Underlying
+	k := o.strike
+	t := o.YearsToExpiration(m.Time)
+	vol := m.volatility(o)
+
+	if t <= 0 || vol <= 0 {
+		g.Price = o.Intrinsic(s)
+		if g.Price > 0 {
+			g.Delta = 1
+			if !o.call {
+				g.Delta = -1
+			}
+		}
+		return
+	}
+
+	sqrtT := math.Sqrt(t)
+	d1 := (math.Log(s/k) + (m.Rate-m.Yield+vol*vol/2)*t) / (vol * sqrtT)
+	d2 := d1 - vol*sqrtT
+	discR := math.Exp(-m.Rate * t)
+	discQ := math.Exp(-m.Yield * t)
+	pdf := normPDF(d1)
+
+	g.Gamma = discQ * pdf / (s * vol * sqrtT)
+	g.Vega = s * discQ * pdf * sqrtT / 100
+
+	common := -s * discQ * pdf * vol / (2 * sqrtT)
+	if o.call {
+		g.Price = s*discQ*normCDF(d1) - k*discR*normCDF(d2)
+		g.Delta = discQ * normCDF(d1)
+		g.Theta = (common - m.Rate*k*discR*normCDF(d2) + m.Yield*s*discQ*normCDF(d1)) / daysPerYear
+		g.Rho = k * t * discR * normCDF(d2) / 100
+	} else {
+		g.Price = k*discR*normCDF(-d2) - s*discQ*normCDF(-d1)
+		g.Delta = -discQ * normCDF(-d1)
+		g.Theta = (common + m.Rate*k*discR*normCDF(-d2) - m.Yield*s*discQ*normCDF(-d1)) / daysPerYear
+		g.Rho = -k * t * discR * normCDF(-d2) / 100
+	}
+
+	return
+}
+
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
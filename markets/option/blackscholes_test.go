@@ -0,0 +1,116 @@
+package option
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var testExpiration = time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)
+
+// testMarket returns market conditions valued the given number of years before testExpiration.
+func testMarket(underlying, rate, yield, vol, years float64) Market {
+	before := time.Duration(years * daysPerYear * 24 * float64(time.Hour))
+
+	return Market{Underlying: underlying, Rate: rate, Yield: yield, Volatility: vol, Time: testExpiration.Add(-before)}
+}
+
+func testOption(t *testing.T, strike float64, call bool) Option {
+	t.Helper()
+
+	o, err := NewOption("TEST", testExpiration, strike, call, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return o
+}
+
+func assertNear(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %.6f, want %.6f (tolerance %g)", name, got, want, tolerance)
+	}
+}
+
+// Hull, Options, Futures, and Other Derivatives, example 15.6: S = 42, K = 40, r = 10%, vol = 20%, T = 0.5.
+func TestBlackScholesHullPrices(t *testing.T) {
+	m := testMarket(42, 0.10, 0, 0.20, 0.5)
+
+	call, err := BlackScholes(testOption(t, 40, true), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	put, err := BlackScholes(testOption(t, 40, false), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertNear(t, "call price", call.Price, 4.76, 0.005)
+	assertNear(t, "put price", put.Price, 0.81, 0.005)
+}
+
+// Hull, chapter 19: S = 49, K = 50, r = 5%, vol = 20%, T = 20 weeks. Theta, Vega and Rho are scaled as Greeks
+// documents: per calendar day and per percentage point.
+func TestBlackScholesHullGreeks(t *testing.T) {
+	m := testMarket(49, 0.05, 0, 0.20, 20.0/52)
+
+	g, err := BlackScholes(testOption(t, 50, true), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertNear(t, "price", g.Price, 2.40, 0.005)
+	assertNear(t, "delta", g.Delta, 0.522, 0.0005)
+	assertNear(t, "gamma", g.Gamma, 0.066, 0.0005)
+	assertNear(t, "theta", g.Theta, -4.31/daysPerYear, 0.0001)
+	assertNear(t, "vega", g.Vega, 12.1/100, 0.0005)
+	assertNear(t, "rho", g.Rho, 8.91/100, 0.0005)
+}
+
+func TestBlackScholesPutCallParity(t *testing.T) {
+	for _, tc := range []struct {
+		underlying, strike, rate, yield, vol, years float64
+	}{
+		{100, 100, 0.05, 0, 0.25, 1},
+		{42, 40, 0.10, 0, 0.20, 0.5},
+		{150, 170, 0.02, 0.015, 0.35, 0.25},
+		{20, 15, 0.01, 0.03, 0.60, 2},
+	} {
+		m := testMarket(tc.underlying, tc.rate, tc.yield, tc.vol, tc.years)
+
+		call, err := BlackScholes(testOption(t, tc.strike, true), m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		put, err := BlackScholes(testOption(t, tc.strike, false), m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		years := testOption(t, tc.strike, true).YearsToExpiration(m.Time)
+		want := tc.underlying*math.Exp(-tc.yield*years) - tc.strike*math.Exp(-tc.rate*years)
+		assertNear(t, "call - put", call.Price-put.Price, want, 1e-9)
+		assertNear(t, "call delta - put delta", call.Delta-put.Delta, math.Exp(-tc.yield*years), 1e-9)
+		assertNear(t, "call gamma - put gamma", call.Gamma-put.Gamma, 0, 1e-12)
+	}
+}
+
+func TestBlackScholesAtExpiration(t *testing.T) {
+	m := testMarket(105, 0.05, 0, 0.30, 0)
+
+	call, err := BlackScholes(testOption(t, 100, true), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	put, err := BlackScholes(testOption(t, 100, false), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertNear(t, "call price", call.Price, 5, 1e-12)
+	assertNear(t, "call delta", call.Delta, 1, 1e-12)
+	assertNear(t, "put price", put.Price, 0, 1e-12)
+	assertNear(t, "put delta", put.Delta, 0, 1e-12)
+}
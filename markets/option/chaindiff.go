@@ -0,0 +1,175 @@
+package option
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DiffThresholds sets the minimum changes reported when comparing chain snapshots.
+type DiffThresholds struct {
+	MinOpenInterestChange int     // Smallest absolute change in open interest to report.
+	MinPremiumChangePct   float64 // Smallest absolute change in mid price, in percent, to report.
+}
+
+// StrikeKey identifies a strike price within an expiration.
+type StrikeKey struct {
+	Expiration time.Time
+	Strike     float64
+}
+
+func (sk StrikeKey) String() string {
+	return fmt.Sprintf("%s %g", sk.Expiration.Format("2006-01-02"), sk.Strike)
+}
+
+// OptionChange holds the old and new values of a call or put between two snapshots.
+type OptionChange struct {
+	StrikeKey
+	Call bool
+	Old  float64
+	New  float64
+}
+
+func (oc OptionChange) String() string {
+	kind := "Put "
+	if oc.Call {
+		kind = "Call"
+	}
+
+	return fmt.Sprintf("%s %s %10.2f -> %10.2f", oc.StrikeKey, kind, oc.Old, oc.New)
+}
+
+// ChainDiff holds the differences between two option chain snapshots of the same stock.
+type ChainDiff struct {
+	Symbol              string
+	From                time.Time
+	To                  time.Time
+	NewExpirations      []time.Time
+	RemovedExpirations  []time.Time
+	NewStrikes          []StrikeKey
+	RemovedStrikes      []StrikeKey
+	OpenInterestChanges []OptionChange
+	PremiumMoves        []OptionChange
+}
+
+// DiffChains compares two snapshots of the same stock's option chain.
+// Strikes are only compared for expirations found in both snapshots.
+func DiffChains(from, to ChainSnapshot, th DiffThresholds) (cd ChainDiff, err error) {
+	if from.symbol != to.symbol {
+		return cd, fmt.Errorf("cannot compare option chains for different symbols: %s, %s", from.symbol, to.symbol)
+	}
+
+	cd.Symbol = to.symbol
+	cd.From = from.time
+	cd.To = to.time
+
+	fromExps := make(map[time.Time]Expiration, len(from.expirations))
+	for _, exp := range from.expirations {
+		fromExps[exp.date] = exp
+	}
+
+	toExps := make(map[time.Time]bool, len(to.expirations))
+	for _, exp := range to.expirations {
+		toExps[exp.date] = true
+
+		prev, ok := fromExps[exp.date]
+		if !ok {
+			cd.NewExpirations = append(cd.NewExpirations, exp.date)
+			continue
+		}
+
+		cd.diffStrikes(prev, exp, th)
+	}
+
+	for _, exp := range from.expirations {
+		if !toExps[exp.date] {
+			cd.RemovedExpirations = append(cd.RemovedExpirations, exp.date)
+		}
+	}
+
+	return
+}
+
+func (cd *ChainDiff) diffStrikes(from, to Expiration, th DiffThresholds) {
+	fromStrikes := make(map[float64]Strike, len(from.strikes))
+	for _, strike := range from.strikes {
+		fromStrikes[strike.price] = strike
+	}
+
+	toStrikes := make(map[float64]bool, len(to.strikes))
+	for _, strike := range to.strikes {
+		toStrikes[strike.price] = true
+		key := StrikeKey{Expiration: to.date, Strike: strike.price}
+
+		prev, ok := fromStrikes[strike.price]
+		if !ok {
+			cd.NewStrikes = append(cd.NewStrikes, key)
+			continue
+		}
+
+		cd.diffOption(key, true, prev.call, strike.call, th)
+		cd.diffOption(key, false, prev.put, strike.put, th)
+	}
+
+	for _, strike := range from.strikes {
+		if !toStrikes[strike.price] {
+			cd.RemovedStrikes = append(cd.RemovedStrikes, StrikeKey{Expiration: from.date, Strike: strike.price})
+		}
+	}
+}
+
+func (cd *ChainDiff) diffOption(key StrikeKey, call bool, from, to Option, th DiffThresholds) {
+	// Skip options not loaded in either snapshot.
+	if len(from.symbol) == 0 || len(to.symbol) == 0 {
+		return
+	}
+
+	oiChange := to.open - from.open
+	if oiChange != 0 && abs(oiChange) >= th.MinOpenInterestChange {
+		cd.OpenInterestChanges = append(cd.OpenInterestChanges,
+			OptionChange{StrikeKey: key, Call: call, Old: float64(from.open), New: float64(to.open)})
+	}
+
+	oldMid, newMid := from.Mid(), to.Mid()
+	if oldMid > 0 && newMid > 0 {
+		changePct := (newMid - oldMid) / oldMid * 100
+		if changePct != 0 && math.Abs(changePct) >= th.MinPremiumChangePct {
+			cd.PremiumMoves = append(cd.PremiumMoves,
+				OptionChange{StrikeKey: key, Call: call, Old: oldMid, New: newMid})
+		}
+	}
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+func (cd ChainDiff) String() string {
+	str := fmt.Sprintf("%s option chain changes from %s to %s\n", cd.Symbol,
+		cd.From.Format("2006-01-02 15:04:05"), cd.To.Format("2006-01-02 15:04:05"))
+
+	for _, date := range cd.NewExpirations {
+		str += fmt.Sprintf("  New expiration:      %s\n", date.Format("2006-01-02"))
+	}
+	for _, date := range cd.RemovedExpirations {
+		str += fmt.Sprintf("  Removed expiration:  %s\n", date.Format("2006-01-02"))
+	}
+	for _, key := range cd.NewStrikes {
+		str += fmt.Sprintf("  New strike:          %s\n", key)
+	}
+	for _, key := range cd.RemovedStrikes {
+		str += fmt.Sprintf("  Removed strike:      %s\n", key)
+	}
+	for _, oc := range cd.OpenInterestChanges {
+		str += fmt.Sprintf("  Open interest:       %s\n", oc)
+	}
+	for _, oc := range cd.PremiumMoves {
+		str += fmt.Sprintf("  Premium move:        %s\n", oc)
+	}
+
+	return str
+}
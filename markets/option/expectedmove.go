@@ -0,0 +1,94 @@
+package option
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ExpectedMove holds the market's expected move of the underlying stock by an expiration date.
+type ExpectedMove struct {
+	Expiration   time.Time
+	Years        float64 // Time until expiration as a fraction of a year.
+	Underlying   float64 // Underlying price used for the calculation.
+	Strike       float64 // At-the-money strike price.
+	Straddle     float64 // Mid price of the at-the-money call plus put.
+	IV           float64 // Average implied volatility of the at-the-money call and put.
+	StraddleMove float64 // Expected move implied by the straddle price.
+	IVMove       float64 // One standard deviation move implied by IV.
+}
+
+// ExpectedMove computes the expected move by the expiration date from the at-the-money straddle and IV.
+// The chain must have been loaded (see Expiration.SetOption).
+func (e Expiration) ExpectedMove(underlying float64, now time.Time) (em ExpectedMove, err error) {
+	if underlying <= 0 {
+		return em, fmt.Errorf("invalid underlying price: %g", underlying)
+	}
+
+	em.Expiration = e.date
+	em.Underlying = underlying
+	em.Years = e.date.Sub(now).Hours() / 24 / daysPerYear
+	if em.Years <= 0 {
+		return em, fmt.Errorf("expiration date has passed: %s", e.ExpirationDateStr())
+	}
+
+	// Find the strike closest to the underlying price with both a call and put quoted.
+	atm := -1
+	for i, strike := range e.strikes {
+		if strike.call.Mid() <= 0 || strike.put.Mid() <= 0 {
+			continue
+		}
+		if atm < 0 || math.Abs(strike.price-underlying) < math.Abs(e.strikes[atm].price-underlying) {
+			atm = i
+		}
+	}
+	if atm < 0 {
+		return em, fmt.Errorf("no quoted calls and puts for expiration date %s", e.ExpirationDateStr())
+	}
+
+	strike := e.strikes[atm]
+	em.Strike = strike.price
+	em.Straddle = strike.call.Mid() + strike.put.Mid()
+	em.StraddleMove = em.Straddle
+
+	switch {
+	case strike.call.IV > 0 && strike.put.IV > 0:
+		em.IV = (strike.call.IV + strike.put.IV) / 2
+	case strike.call.IV > 0:
+		em.IV = strike.call.IV
+	default:
+		em.IV = strike.put.IV
+	}
+	em.IVMove = underlying * em.IV * math.Sqrt(em.Years)
+
+	return
+}
+
+// StraddleRange returns the lower and upper price bands of the straddle-implied move.
+func (em ExpectedMove) StraddleRange() (float64, float64) {
+	return em.Underlying - em.StraddleMove, em.Underlying + em.StraddleMove
+}
+
+// IVRange returns the lower and upper price bands of the IV-implied move.
+// Both are zero if no implied volatility was available.
+func (em ExpectedMove) IVRange() (float64, float64) {
+	if em.IVMove == 0 {
+		return 0, 0
+	}
+
+	return em.Underlying - em.IVMove, em.Underlying + em.IVMove
+}
+
+func ExpectedMoveHeader() string {
+	return fmt.Sprintln("Expiration  Strike  Straddle   Move    Lower    Upper     IV   IV Move    Lower    Upper")
+}
+
+func (em ExpectedMove) String() string {
+	sLow, sHigh := em.StraddleRange()
+	ivLow, ivHigh := em.IVRange()
+
+	return fmt.Sprintf("%s %7.2f %8.2f %6.2f %8.2f %8.2f %5.1f%% %8.2f %8.2f %8.2f",
+		em.Expiration.Format("2006-01-02"), em.Strike, em.Straddle, em.StraddleMove, sLow, sHigh,
+		em.IV*100, em.IVMove, ivLow, ivHigh,
+	)
+}
@@ -0,0 +1,132 @@
+package option
+
+import (
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets"
+)
+
+const (
+	ExpirationWeekly ExpirationType = iota
+	ExpirationMonthly
+	ExpirationQuarterly
+	ExpirationLEAPS
+)
+
+// Expirations more than this many days away are classified as LEAPS.
+const LEAPSMinDays = 365
+
+// ExpirationType classifies an expiration date by the listing cycle it belongs to.
+type ExpirationType int
+
+func (et ExpirationType) String() string {
+	switch et {
+	case ExpirationWeekly:
+		return "Weekly"
+	case ExpirationMonthly:
+		return "Monthly"
+	case ExpirationQuarterly:
+		return "Quarterly"
+	case ExpirationLEAPS:
+		return "LEAPS"
+	}
+
+	return "Unknown"
+}
+
+// DaysToExpiration returns the number of calendar days from today until the expiration date.
+func (e Expiration) DaysToExpiration() int {
+	today := time.Now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 12, 0, 0, 0, time.UTC)
+
+	return int(e.date.Sub(today).Hours() / 24)
+}
+
+// Type classifies the expiration date.
+// Standard monthly expirations are the third Friday of the month, or the Thursday before when that Friday
+// is a holiday. Quarterly expirations are the last trading day of March, June, September and December.
+// Any monthly, quarterly or weekly expiration more than LEAPSMinDays away is classified as LEAPS.
+func (e Expiration) Type() ExpirationType {
+	if e.DaysToExpiration() > LEAPSMinDays {
+		return ExpirationLEAPS
+	}
+
+	if isMonthlyExpiration(e.date) {
+		return ExpirationMonthly
+	}
+
+	if isQuarterlyExpiration(e.date) {
+		return ExpirationQuarterly
+	}
+
+	return ExpirationWeekly
+}
+
+func isMonthlyExpiration(date time.Time) bool {
+	if date.Weekday() == time.Thursday && markets.IsHoliday(date.AddDate(0, 0, 1)) {
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return date.Weekday() == time.Friday && date.Day() >= 15 && date.Day() <= 21
+}
+
+func isQuarterlyExpiration(date time.Time) bool {
+	if date.Month()%3 != 0 {
+		return false
+	}
+
+	// No later trading day in the same month.
+	next := date.AddDate(0, 0, 1)
+	for !markets.IsTradingDay(next) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next.Month() != date.Month()
+}
+
+// ExpirationFilter selects which expiration dates to load.
+type ExpirationFilter struct {
+	MinDays int              // Minimum days to expiration, 0 for no minimum.
+	MaxDays int              // Maximum days to expiration, 0 for no maximum.
+	Types   []ExpirationType // Expiration types to keep, all types if empty.
+}
+
+// DefaultExpirationFilter keeps expirations within the next two months.
+var DefaultExpirationFilter = ExpirationFilter{MaxDays: 62}
+
+// Allow returns true if the expiration passes the filter.
+func (f ExpirationFilter) Allow(e Expiration) bool {
+	days := e.DaysToExpiration()
+	if days < f.MinDays {
+		return false
+	}
+
+	if f.MaxDays > 0 && days > f.MaxDays {
+		return false
+	}
+
+	if len(f.Types) == 0 {
+		return true
+	}
+
+	expType := e.Type()
+	for _, t := range f.Types {
+		if t == expType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the expirations that pass the filter, in their original order.
+func (f ExpirationFilter) Filter(exps []Expiration) []Expiration {
+	filtered := make([]Expiration, 0, len(exps))
+	for _, exp := range exps {
+		if f.Allow(exp) {
+			filtered = append(filtered, exp)
+		}
+	}
+
+	return filtered
+}
@@ -2,20 +2,19 @@ package option
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"time"
 )
 
-const MaxDaysUntilExpiration = 62
-
 // Option holds the description of a stock option and its realtime values.
 type Option struct {
-	symbol    string
-	expDate   time.Time
+	symbol    string    // Symbol of the underlying stock.
+	expDate   time.Time // Noon UTC of the expiration date.
 	strike    float64
 	call      bool
-	size      int
-	open      int
+	size      int // Number of shares per contract.
+	open      int // Open interest.
 	Bid       float64
 	BidSize   int
 	Ask       float64
@@ -23,6 +22,98 @@ type Option struct {
 	Last      float64
 	Change    float64
 	ChangePct float64
+	IV        float64 // Implied volatility (0.25 = 25%), 0 if not provided.
+}
+
+func NewOption(
+	symbol string,
+	expDate time.Time,
+	strike float64,
+	call bool,
+	size int,
+	openInterest int,
+) (o Option, err error) {
+	o.symbol = symbol
+	o.expDate = time.Date(expDate.Year(), expDate.Month(), expDate.Day(), 12, 0, 0, 0, time.UTC)
+	o.strike = strike
+	o.call = call
+	o.size = size
+	o.open = openInterest
+
+	err = o.Validate()
+
+	return
+}
+
+func (o Option) Validate() error {
+	if len(o.symbol) == 0 {
+		return fmt.Errorf("option symbol is missing")
+	}
+
+	if o.strike <= 0.0 {
+		return fmt.Errorf("invalid strike price: %g; symbol: %s", o.strike, o.symbol)
+	}
+
+	if o.size <= 0 {
+		return fmt.Errorf("invalid contract size: %d; symbol: %s", o.size, o.symbol)
+	}
+
+	if o.open < 0 {
+		return fmt.Errorf("invalid open interest: %d; symbol: %s", o.open, o.symbol)
+	}
+
+	return nil
+}
+
+func (o Option) Symbol() string {
+	return o.symbol
+}
+
+func (o Option) ExpirationDate() time.Time {
+	return o.expDate
+}
+
+func (o Option) StrikePrice() float64 {
+	return o.strike
+}
+
+func (o Option) IsCall() bool {
+	return o.call
+}
+
+func (o Option) Size() int {
+	return o.size
+}
+
+func (o Option) OpenInterest() int {
+	return o.open
+}
+
+// Mid returns the midpoint of the bid and ask, or the last price if either side is missing.
+func (o Option) Mid() float64 {
+	if o.Bid <= 0 || o.Ask <= 0 {
+		return o.Last
+	}
+
+	return (o.Bid + o.Ask) / 2
+}
+
+// Intrinsic returns the value of the option if exercised at the given underlying price.
+func (o Option) Intrinsic(underlying float64) float64 {
+	if o.call {
+		return math.Max(underlying-o.strike, 0)
+	}
+
+	return math.Max(o.strike-underlying, 0)
+}
+
+func (o Option) String() string {
+	kind := "Put"
+	if o.call {
+		kind = "Call"
+	}
+
+	return fmt.Sprintf("%s %s %g %s", o.symbol, o.expDate.Format("2006-01-02"), o.strike, kind)
 }
 
 // Strike holds the call and put options for a specific strike price.
@@ -36,6 +127,16 @@ func (s Strike) Price() float64 {
 	return s.price
 }
 
+// Call returns the call option at the strike price, or the zero Option if the chain has not been loaded.
+func (s Strike) Call() Option {
+	return s.call
+}
+
+// Put returns the put option at the strike price, or the zero Option if the chain has not been loaded.
+func (s Strike) Put() Option {
+	return s.put
+}
+
 // Expiration holds details of all options for a specific stock and expiration date.
 type Expiration struct {
 	date    time.Time
@@ -70,11 +171,11 @@ func (e Expiration) Validate() error {
 		return fmt.Errorf("%w: %s", ExpirationWarning("expiration date has passed"), e.date.Format("2006-01-02"))
 	}
 
-	// Reject expirations with too much time remaining as a warning.
-	if e.date.Sub(today) > MaxDaysUntilExpiration*24*time.Hour {
-		return fmt.Errorf("%w: %s", ExpirationWarning("expiration has too much time remaining"), e.date.Format("2006-01-02"))
-	}
+	return e.validateStrikes()
+}
 
+// validateStrikes checks the strike prices without regard to the current date.
+func (e Expiration) validateStrikes() error {
 	if len(e.strikes) == 0 {
 		return fmt.Errorf("expiration date %s provided without strike prices", e.date.Format("2006-01-02"))
 	}
@@ -104,13 +205,33 @@ func (e Expiration) Strikes() []Strike {
 	return e.strikes
 }
 
+// SetOption adds a call or put to the chain at the option's strike price.
+func (e *Expiration) SetOption(o Option) error {
+	if !o.expDate.Equal(e.date) {
+		return fmt.Errorf("option %s does not belong to expiration date %s", o, e.ExpirationDateStr())
+	}
+
+	i := sort.Search(len(e.strikes), func(i int) bool { return e.strikes[i].price >= o.strike })
+	if i == len(e.strikes) || e.strikes[i].price != o.strike {
+		return fmt.Errorf("option %s has a strike price not offered for expiration date %s", o, e.ExpirationDateStr())
+	}
+
+	if o.call {
+		e.strikes[i].call = o
+	} else {
+		e.strikes[i].put = o
+	}
+
+	return nil
+}
+
 // ExpirationDateStr returns the expiration date as "YYYY-MM-DD".
 func (e Expiration) ExpirationDateStr() string {
 	return e.date.Format("2006-01-02")
 }
 
 func (e Expiration) String() (s string) {
-	s = fmt.Sprintf("Date: %s", e.date.Format("2006-01-02"))
+	s = fmt.Sprintf("Date: %s %-9s", e.date.Format("2006-01-02"), e.Type())
 	if len(e.strikes) > 0 {
 		s += fmt.Sprintf("   Strike Range: %6.1f - %6.1f", e.strikes[0].price, e.strikes[len(e.strikes)-1].price)
 	}
@@ -1,6 +1,13 @@
 package option
 
-import "time"
+import (
+	"sort"
+	"time"
+)
+
+// ExpirationGobVersion is the version of the ExpirationGob fields, kept in stored files.
+// Changing the fields needs a new version and a migration in package persist.
+const ExpirationGobVersion = 1
 
 // ExpirationGob is the type used to persist option expiration dates and strike prices.
 type ExpirationGob struct {
@@ -8,8 +15,20 @@ type ExpirationGob struct {
 	Strikes []float64
 }
 
-func (eg ExpirationGob) ToExpiration() (Expiration, error) {
-	return NewExpiration(eg.Date, eg.Strikes)
+// ToExpiration converts a persisted expiration. Unlike NewExpiration, expirations that have passed are allowed.
+func (eg ExpirationGob) ToExpiration() (e Expiration, err error) {
+	e.date = time.Date(eg.Date.Year(), eg.Date.Month(), eg.Date.Day(), 12, 0, 0, 0, time.UTC)
+
+	strikes := make([]float64, len(eg.Strikes))
+	copy(strikes, eg.Strikes)
+	sort.Float64s(strikes)
+	for _, strike := range strikes {
+		e.strikes = append(e.strikes, Strike{price: strike})
+	}
+
+	err = e.validateStrikes()
+
+	return
 }
 
 func (e Expiration) ToGob() ExpirationGob {
@@ -21,3 +40,119 @@ func (e Expiration) ToGob() ExpirationGob {
 
 	return eg
 }
+
+// OptionGob is the type used to persist the realtime values of a call or put.
+type OptionGob struct {
+	Size         int
+	OpenInterest int
+	Bid          float64
+	BidSize      int
+	Ask          float64
+	AskSize      int
+	Last         float64
+	Change       float64
+	ChangePct    float64
+	IV           float64
+}
+
+// StrikeGob is the type used to persist a strike price and its options.
+// Call and Put are nil if the option was not loaded.
+type StrikeGob struct {
+	Price float64
+	Call  *OptionGob
+	Put   *OptionGob
+}
+
+// ChainExpirationGob is the type used to persist all options for an expiration date.
+type ChainExpirationGob struct {
+	Date    time.Time
+	Strikes []StrikeGob
+}
+
+// ChainSnapshotGobVersion is the version of the fields of ChainSnapshotGob and the types it holds.
+const ChainSnapshotGobVersion = 1
+
+// ChainSnapshotGob is the type used to persist a full option chain snapshot.
+type ChainSnapshotGob struct {
+	Symbol      string
+	Time        time.Time
+	Underlying  float64
+	Expirations []ChainExpirationGob
+}
+
+func (csg ChainSnapshotGob) ToChainSnapshot() (ChainSnapshot, error) {
+	exps := make([]Expiration, 0, len(csg.Expirations))
+	for _, eg := range csg.Expirations {
+		exp := Expiration{date: eg.Date}
+		for _, sg := range eg.Strikes {
+			strike := Strike{price: sg.Price}
+			if sg.Call != nil {
+				strike.call = sg.Call.toOption(csg.Symbol, eg.Date, sg.Price, true)
+			}
+			if sg.Put != nil {
+				strike.put = sg.Put.toOption(csg.Symbol, eg.Date, sg.Price, false)
+			}
+			exp.strikes = append(exp.strikes, strike)
+		}
+		exps = append(exps, exp)
+	}
+
+	return NewChainSnapshot(csg.Symbol, csg.Time, csg.Underlying, exps)
+}
+
+func (og OptionGob) toOption(symbol string, expDate time.Time, strike float64, call bool) Option {
+	return Option{
+		symbol:    symbol,
+		expDate:   expDate,
+		strike:    strike,
+		call:      call,
+		size:      og.Size,
+		open:      og.OpenInterest,
+		Bid:       og.Bid,
+		BidSize:   og.BidSize,
+		Ask:       og.Ask,
+		AskSize:   og.AskSize,
+		Last:      og.Last,
+		Change:    og.Change,
+		ChangePct: og.ChangePct,
+		IV:        og.IV,
+	}
+}
+
+func (cs ChainSnapshot) ToGob() ChainSnapshotGob {
+	csg := ChainSnapshotGob{}
+	csg.Symbol = cs.symbol
+	csg.Time = cs.time
+	csg.Underlying = cs.underlying
+	for _, exp := range cs.expirations {
+		eg := ChainExpirationGob{Date: exp.date}
+		for _, strike := range exp.strikes {
+			sg := StrikeGob{Price: strike.price}
+			if len(strike.call.symbol) > 0 {
+				sg.Call = strike.call.toGob()
+			}
+			if len(strike.put.symbol) > 0 {
+				sg.Put = strike.put.toGob()
+			}
+			eg.Strikes = append(eg.Strikes, sg)
+		}
+		csg.Expirations = append(csg.Expirations, eg)
+	}
+
+	return csg
+}
+
+func (o Option) toGob() *OptionGob {
+	return &OptionGob{
+		Size:         o.size,
+		OpenInterest: o.open,
+		Bid:          o.Bid,
+		BidSize:      o.BidSize,
+		Ask:          o.Ask,
+		AskSize:      o.AskSize,
+		Last:         o.Last,
+		Change:       o.Change,
+		ChangePct:    o.ChangePct,
+		IV:           o.IV,
+	}
+}
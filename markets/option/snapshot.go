@@ -0,0 +1,77 @@
+package option
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChainSnapshot holds the full option chain of a stock as it was at a point in time.
+type ChainSnapshot struct {
+	symbol      string
+	time        time.Time
+	underlying  float64 // Last price of the underlying stock when the snapshot was taken.
+	expirations []Expiration
+}
+
+func NewChainSnapshot(symbol string, taken time.Time, underlying float64, exps []Expiration) (cs ChainSnapshot, err error) {
+	cs.symbol = symbol
+	cs.time = taken.UTC()
+	cs.underlying = underlying
+	cs.expirations = exps
+
+	err = cs.Validate()
+
+	return
+}
+
+// Validate checks the snapshot's structure only, so snapshots remain valid after their expirations pass.
+func (cs ChainSnapshot) Validate() error {
+	if len(cs.symbol) == 0 {
+		return fmt.Errorf("chain snapshot symbol is missing")
+	}
+
+	if cs.time.IsZero() {
+		return fmt.Errorf("chain snapshot time is not set; symbol: %s", cs.symbol)
+	}
+
+	if cs.underlying < 0 {
+		return fmt.Errorf("invalid underlying price: %g; symbol: %s", cs.underlying, cs.symbol)
+	}
+
+	for i, exp := range cs.expirations {
+		if i > 0 && !exp.date.After(cs.expirations[i-1].date) {
+			return fmt.Errorf("chain snapshot expirations are not in chronological order; symbol: %s", cs.symbol)
+		}
+
+		if err := exp.validateStrikes(); err != nil {
+			return fmt.Errorf("chain snapshot for %s: %w", cs.symbol, err)
+		}
+	}
+
+	return nil
+}
+
+func (cs ChainSnapshot) Symbol() string {
+	return cs.symbol
+}
+
+func (cs ChainSnapshot) Time() time.Time {
+	return cs.time
+}
+
+func (cs ChainSnapshot) Underlying() float64 {
+	return cs.underlying
+}
+
+func (cs ChainSnapshot) Expirations() []Expiration {
+	return cs.expirations
+}
+
+func (cs ChainSnapshot) String() string {
+	str := fmt.Sprintf("%s chain at %s (underlying %.2f)\n", cs.symbol, cs.time.Format("2006-01-02 15:04:05"), cs.underlying)
+	for _, exp := range cs.expirations {
+		str += fmt.Sprintf("  %s\n", exp)
+	}
+
+	return str
+}
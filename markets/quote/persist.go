@@ -0,0 +1,88 @@
+package quote
+
+import "time"
+
+// QuoteGob is the type used to persist quotes.
+type QuoteGob struct {
+	Symbol    string
+	TradeDate time.Time
+	PrevClose float64
+	Change    float64
+	ChangePct float64
+	Bid       float64
+	BidSize   int
+	Ask       float64
+	AskSize   int
+	Last      float64
+	High      float64
+	Low       float64
+	Volume    int
+	AvgVolume int
+	YearHigh  float64
+	YearLow   float64
+}
+
+// ToQuote converts a persisted quote. Unlike NewQuote, quotes of any age are allowed.
+func (qg QuoteGob) ToQuote() (q Quote, err error) {
+	q.symbol = qg.Symbol
+	q.tradeDate = qg.TradeDate
+	q.prevClose = qg.PrevClose
+	q.change = qg.Change
+	q.changePct = qg.ChangePct
+	q.bid = qg.Bid
+	q.bidSize = qg.BidSize
+	q.ask = qg.Ask
+	q.askSize = qg.AskSize
+	q.last = qg.Last
+	q.high = qg.High
+	q.low = qg.Low
+	q.volume = qg.Volume
+	q.avgVolume = qg.AvgVolume
+	q.yearHigh = qg.YearHigh
+	q.yearLow = qg.YearLow
+
+	err = q.validateStored()
+
+	return
+}
+
+func (q Quote) ToGob() QuoteGob {
+	return QuoteGob{
+		Symbol:    q.symbol,
+		TradeDate: q.tradeDate,
+		PrevClose: q.prevClose,
+		Change:    q.change,
+		ChangePct: q.changePct,
+		Bid:       q.bid,
+		BidSize:   q.bidSize,
+		Ask:       q.ask,
+		AskSize:   q.askSize,
+		Last:      q.last,
+		High:      q.high,
+		Low:       q.low,
+		Volume:    q.volume,
+		AvgVolume: q.avgVolume,
+		YearHigh:  q.yearHigh,
+		YearLow:   q.yearLow,
+	}
+}
+
+// SnapshotGobVersion is the version of the fields of SnapshotGob and QuoteGob, kept in stored files.
+// Changing the fields needs a new version and a migration in package persist.
+const SnapshotGobVersion = 1
+
+// SnapshotGob is the type used to persist quote snapshots.
+type SnapshotGob struct {
+	Received time.Time
+	Quote    QuoteGob
+}
+
+func (sg SnapshotGob) ToSnapshot() (Snapshot, error) {
+	q, err := sg.Quote.ToQuote()
+
+	return Snapshot{Received: sg.Received, Quote: q}, err
+}
+
+func (s Snapshot) ToGob() SnapshotGob {
+	return SnapshotGob{Received: s.Received, Quote: s.Quote.ToGob()}
+}
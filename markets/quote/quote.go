@@ -80,6 +80,29 @@ func (q Quote) ChangePct() float64 {
 	return q.changePct
 }
 
+// Volume returns the number of shares traded so far in the session.
+func (q Quote) Volume() int {
+	return q.volume
+}
+
+// AvgVolume returns the average daily volume as given by the data provider.
+func (q Quote) AvgVolume() int {
+	return q.avgVolume
+}
+
+// TradeDate returns the time of the last trade.
+func (q Quote) TradeDate() time.Time {
+	return q.tradeDate
+}
+
+func (q Quote) BidAsk() (float64, float64) {
+	return q.bid, q.ask
+}
+
+func (q Quote) HighLow() (float64, float64) {
+	return q.high, q.low
+}
+
 // Validate quote fields.
 // Missing numeric values are stored as 0 and do not invalidate the quote.
 // Values may be missing for several reasons, including:
@@ -88,6 +111,19 @@ func (q Quote) ChangePct() float64 {
 //   Temporarily not given by data provider for unspecified reasons.
 // Clients of quote data are expected to check for zero values.
 func (q Quote) Validate() error {
+	if err := q.validateStored(); err != nil {
+		return err
+	}
+
+	if q.tradeDate.Before(time.Now().Add(-24 * maxQuoteAgeInDays * time.Hour)) {
+		return fmt.Errorf("quote cannot be older than %d days; trade date: %s; symbol: %s", maxQuoteAgeInDays, q.tradeDate.Format("Jan 2, 2006 15:04:05"), q.symbol)
+	}
+
+	return nil
+}
+
+// validateStored checks everything except the age of the quote, which only limits newly retrieved quotes.
+func (q Quote) validateStored() error {
 	if len(q.symbol) == 0 {
 		return errors.New("symbol is missing")
 	}
@@ -96,13 +132,9 @@ func (q Quote) Validate() error {
 		return errors.New("trade date is not set")
 	}
 
-	now := time.Now()
-	if q.tradeDate.After(now) {
+	if q.tradeDate.After(time.Now()) {
 		return fmt.Errorf("trade date cannot be in the future; trade date: %s; symbol: %s", q.tradeDate.Format("Jan 2, 2006 15:04:05"), q.symbol)
 	}
-	if q.tradeDate.Before(now.Add(-24 * maxQuoteAgeInDays * time.Hour)) {
-		return fmt.Errorf("quote cannot be older than %d days; trade date: %s; symbol: %s", maxQuoteAgeInDays, q.tradeDate.Format("Jan 2, 2006 15:04:05"), q.symbol)
-	}
 
 	if q.prevClose < 0.0 {
 		return fmt.Errorf("invalid previous close value: %g; symbol: %s", q.prevClose, q.symbol)
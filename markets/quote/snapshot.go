@@ -0,0 +1,69 @@
+package quote
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets"
+)
+
+// Snapshot is a quote with the time it was received from the data provider.
+type Snapshot struct {
+	Received time.Time
+	Quote    Quote
+}
+
+// NewSnapshots stamps quotes received together with the receive time.
+func NewSnapshots(quotes []Quote, received time.Time) []Snapshot {
+	snapshots := make([]Snapshot, 0, len(quotes))
+	for _, q := range quotes {
+		snapshots = append(snapshots, Snapshot{Received: received, Quote: q})
+	}
+
+	return snapshots
+}
+
+// Spread returns the ask less the bid, or 0 if either is missing.
+func (s Snapshot) Spread() float64 {
+	if s.Quote.bid == 0 || s.Quote.ask == 0 {
+		return 0
+	}
+
+	return s.Quote.ask - s.Quote.bid
+}
+
+// Glitches describes problems with the snapshot's quote data, such as a crossed market or
+// a last price outside of the day's range. Missing (zero) values are not reported.
+func (s Snapshot) Glitches() []string {
+	q := s.Quote
+
+	var glitches []string
+	if q.bid > 0 && q.ask > 0 && q.bid > q.ask {
+		glitches = append(glitches, "crossed market")
+	}
+	if q.last > 0 && q.high > 0 && q.last > q.high {
+		glitches = append(glitches, "last above high")
+	}
+	if q.last > 0 && q.low > 0 && q.last < q.low {
+		glitches = append(glitches, "last below low")
+	}
+	if q.tradeDate.After(s.Received) {
+		glitches = append(glitches, "trade after receipt")
+	}
+
+	return glitches
+}
+
+// SnapshotHeader heads snapshots, which show times in New York.
+func SnapshotHeader() string {
+	return fmt.Sprintln("Received  Stock     Last      Bid      Ask   Spread   %Change       Volume  Trade")
+}
+
+func (s Snapshot) String() string {
+	q := s.Quote
+
+	return fmt.Sprintf("%s %5s %8.2f %8.2f %8.2f %8.2f %8.2f%% %12d  %s",
+		markets.MarketTime(s.Received).Format("15:04:05"), q.symbol, q.last, q.bid, q.ask, s.Spread(), q.changePct, q.volume,
+		markets.MarketTime(q.tradeDate).Format("15:04:05"),
+	)
+}
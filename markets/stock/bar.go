@@ -0,0 +1,176 @@
+package stock
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Bar holds price and volume data for a period of trading activity of any length,
+// such as a day from DailyPrice, a minute from OneMinSale, or several of either combined.
+type Bar struct {
+	start  time.Time // Time of the first period in the bar.
+	end    time.Time // Time of the last period in the bar.
+	open   float64
+	close  float64
+	high   float64
+	low    float64
+	volume int64
+	vwap   float64 // Volume-weighted average price, 0 if not known.
+}
+
+func NewBar(
+	start, end time.Time,
+	open, close, high, low float64,
+	volume int64,
+	vwap float64,
+) (b Bar, err error) {
+	b.start = start
+	b.end = end
+	b.open = open
+	b.close = close
+	b.high = high
+	b.low = low
+	b.volume = volume
+	b.vwap = vwap
+
+	err = b.Validate()
+
+	return
+}
+
+func (b Bar) Validate() error {
+	if b.start.IsZero() {
+		return fmt.Errorf("bar start time is not set")
+	}
+
+	if b.end.Before(b.start) {
+		return fmt.Errorf("bar end time %s is before start time %s",
+			b.end.Format("2006-01-02 15:04"), b.start.Format("2006-01-02 15:04"))
+	}
+
+	if b.open <= 0 {
+		return fmt.Errorf("invalid open price: %g", b.open)
+	}
+
+	if b.close <= 0 {
+		return fmt.Errorf("invalid close price: %g", b.close)
+	}
+
+	if b.high <= 0 {
+		return fmt.Errorf("invalid high price: %g", b.high)
+	}
+
+	if b.low <= 0 {
+		return fmt.Errorf("invalid low price: %g", b.low)
+	}
+
+	if b.volume < 0 {
+		return fmt.Errorf("invalid Volume: %d", b.volume)
+	}
+
+	if b.vwap < 0 {
+		return fmt.Errorf("invalid vwap: %g", b.vwap)
+	}
+
+	return nil
+}
+
+func (dp DailyPrice) Bar() Bar {
+	return Bar{
+		start:  dp.date,
+		end:    dp.date,
+		open:   dp.open,
+		close:  dp.close,
+		high:   dp.high,
+		low:    dp.low,
+		volume: dp.volume,
+	}
+}
+
+func (oms OneMinSale) Bar() Bar {
+	return Bar{
+		start:  oms.startTime,
+		end:    oms.startTime,
+		open:   oms.open,
+		close:  oms.close,
+		high:   oms.high,
+		low:    oms.low,
+		volume: oms.volume,
+		vwap:   oms.vwap,
+	}
+}
+
+// DailyPriceBars converts daily prices to bars.
+func DailyPriceBars(dps []DailyPrice) []Bar {
+	bars := make([]Bar, 0, len(dps))
+	for _, dp := range dps {
+		bars = append(bars, dp.Bar())
+	}
+
+	return bars
+}
+
+// OneMinSaleBars converts one minute sales to bars.
+func OneMinSaleBars(omss []OneMinSale) []Bar {
+	bars := make([]Bar, 0, len(omss))
+	for _, oms := range omss {
+		bars = append(bars, oms.Bar())
+	}
+
+	return bars
+}
+
+func (b Bar) Start() time.Time {
+	return b.start
+}
+
+func (b Bar) End() time.Time {
+	return b.end
+}
+
+func (b Bar) OpenClose() (float64, float64) {
+	return b.open, b.close
+}
+
+func (b Bar) HighLow() (float64, float64) {
+	return b.high, b.low
+}
+
+func (b Bar) Volume() int64 {
+	return b.volume
+}
+
+// VWAP returns the volume-weighted average price, or 0 if it is not known (as for daily prices).
+func (b Bar) VWAP() float64 {
+	return b.vwap
+}
+
+// TypicalPrice returns the average of the high, low and close.
+func (b Bar) TypicalPrice() float64 {
+	return (b.high + b.low + b.close) / 3
+}
+
+// AveragePrice returns the VWAP if known, otherwise the typical price.
+func (b Bar) AveragePrice() float64 {
+	if b.vwap > 0 {
+		return b.vwap
+	}
+
+	return b.TypicalPrice()
+}
+
+func (b Bar) String() string {
+	p := message.NewPrinter(language.English)
+
+	return fmt.Sprintf("%s %7.2f %7.2f %7.2f %7.2f %s %9.4f",
+		b.start.Format("2006-01-02 15:04"), b.open, b.close, b.high, b.low,
+		p.Sprintf("%12d", b.volume), b.vwap,
+	)
+}
+
+func BarHeader() string {
+	return fmt.Sprintln("                        Open   Close    High     Low       Volume      VWAP")
+}
@@ -2,6 +2,7 @@ package stock
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"golang.org/x/text/language"
@@ -110,3 +111,23 @@ func (dp DailyPrice) String() string {
 func DailyPriceHeader() string {
 	return fmt.Sprintln("              Open   Close    High     Low       Volume")
 }
+
+// MergeDailyPrices combines two daily price histories in chronological order.
+// Prices in updates replace prices in existing with the same date.
+func MergeDailyPrices(existing, updates []DailyPrice) []DailyPrice {
+	byDate := make(map[string]DailyPrice, len(existing)+len(updates))
+	for _, dp := range existing {
+		byDate[dp.DateStr()] = dp
+	}
+	for _, dp := range updates {
+		byDate[dp.DateStr()] = dp
+	}
+
+	merged := make([]DailyPrice, 0, len(byDate))
+	for _, dp := range byDate {
+		merged = append(merged, dp)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].date.Before(merged[j].date) })
+
+	return merged
+}
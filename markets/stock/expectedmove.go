@@ -0,0 +1,120 @@
+package stock
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/option"
+)
+
+// MoveComparison compares an expected move with the stock's realized moves over the same number of sessions.
+// Realized moves are absolute close-to-close changes in percent.
+type MoveComparison struct {
+	option.ExpectedMove
+	Sessions         int     // Trading sessions until expiration.
+	Samples          int     // Number of realized moves in the price history.
+	MedianMovePct    float64 // Median realized move.
+	MeanMovePct      float64 // Mean realized move.
+	StraddleMovePct  float64 // Straddle-implied move.
+	IVMovePct        float64 // IV-implied move.
+	ExceededStraddle float64 // Fraction of realized moves larger than the straddle-implied move.
+	ExceededIV       float64 // Fraction of realized moves larger than the IV-implied move.
+}
+
+// CompareExpectedMove measures the expected move against realized moves in the daily price history.
+func CompareExpectedMove(em option.ExpectedMove, prices []DailyPrice, now time.Time) (mc MoveComparison, err error) {
+	mc.ExpectedMove = em
+	mc.StraddleMovePct = em.StraddleMove / em.Underlying * 100
+	mc.IVMovePct = em.IVMove / em.Underlying * 100
+
+	// Count trading sessions after today through expiration.
+	today := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+	for day := today.AddDate(0, 0, 1); !day.After(em.Expiration); day = day.AddDate(0, 0, 1) {
+		if markets.IsTradingDay(day) {
+			mc.Sessions++
+		}
+	}
+	if mc.Sessions == 0 {
+		mc.Sessions = 1
+	}
+
+	if len(prices) <= mc.Sessions {
+		return mc, fmt.Errorf("not enough price history for a %d session move: %d days", mc.Sessions, len(prices))
+	}
+
+	moves := make([]float64, 0, len(prices)-mc.Sessions)
+	for i := mc.Sessions; i < len(prices); i++ {
+		_, start := prices[i-mc.Sessions].OpenClose()
+		_, end := prices[i].OpenClose()
+		moves = append(moves, math.Abs(end/start-1)*100)
+	}
+	sort.Float64s(moves)
+
+	mc.Samples = len(moves)
+	sum := 0.0
+	straddleCnt, ivCnt := 0, 0
+	for _, move := range moves {
+		sum += move
+		if move > mc.StraddleMovePct {
+			straddleCnt++
+		}
+		if mc.IVMovePct > 0 && move > mc.IVMovePct {
+			ivCnt++
+		}
+	}
+	mc.MeanMovePct = sum / float64(len(moves))
+	mc.ExceededStraddle = float64(straddleCnt) / float64(len(moves))
+	mc.ExceededIV = float64(ivCnt) / float64(len(moves))
+
+	mid := len(moves) / 2
+	if len(moves)%2 == 0 {
+		mc.MedianMovePct = (moves[mid-1] + moves[mid]) / 2
+	} else {
+		mc.MedianMovePct = moves[mid]
+	}
+
+	return
+}
+
+// ExpectedMoves compares the expected move of each loaded expiration with the stock's realized moves.
+// The quote, option chain and daily prices must have been loaded. Expirations that cannot be compared, such as
+// those without quoted at-the-money strikes or with more sessions than the price history, are skipped and their
+// errors returned with the comparisons of the others.
+func (s *Stock) ExpectedMoves(now time.Time) ([]MoveComparison, []error) {
+	s.Lock()
+	defer s.Unlock()
+
+	comparisons := make([]MoveComparison, 0, len(s.optionChain))
+	var errs []error
+	for _, exp := range s.optionChain {
+		em, err := exp.ExpectedMove(s.quote.Last(), now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("expected move for %s %s: %w", s.symbol, exp.ExpirationDateStr(), err))
+			continue
+		}
+
+		mc, err := CompareExpectedMove(em, s.dailyPrices, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("expected move for %s %s: %w", s.symbol, exp.ExpirationDateStr(), err))
+			continue
+		}
+
+		comparisons = append(comparisons, mc)
+	}
+
+	return comparisons, errs
+}
+
+func MoveComparisonHeader() string {
+	return fmt.Sprintln("Expiration  Sessions  Straddle%   IV%   Median%   Mean%   >Straddle    >IV   Samples")
+}
+
+func (mc MoveComparison) String() string {
+	return fmt.Sprintf("%s %8d %9.2f %6.2f %8.2f %7.2f %10.0f%% %5.0f%% %9d",
+		mc.Expiration.Format("2006-01-02"), mc.Sessions, mc.StraddleMovePct, mc.IVMovePct,
+		mc.MedianMovePct, mc.MeanMovePct, mc.ExceededStraddle*100, mc.ExceededIV*100, mc.Samples,
+	)
+}
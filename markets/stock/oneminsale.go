@@ -54,6 +54,11 @@ func (oms OneMinSale) Validate() error {
 		return fmt.Errorf("one minute sales time cannot be more than %d days old", maxDaysOld)
 	}
 
+	return oms.validateStored()
+}
+
+// validateStored checks everything except the age of the sale, which only limits newly retrieved sales.
+func (oms OneMinSale) validateStored() error {
 	if oms.startTime.After(time.Now()) {
 		return fmt.Errorf("one minute sales time cannot be in the future")
 	}
@@ -1,5 +1,11 @@
 package stock
 
+import "time"
+
+// DailyPriceGobVersion is the version of the DailyPriceGob fields, kept in stored files. Increment it when changing
+// the fields, and register a migration from the previous version in package persist so stored prices can be upgraded.
+const DailyPriceGobVersion = 1
+
 // DailyPriceGob is the type used to persist daily price data.
 type DailyPriceGob struct {
 	Year   int
@@ -29,3 +35,56 @@ func (dp DailyPrice) ToGob() DailyPriceGob {
 
 	return dpg
 }
+
+// OneMinSaleGob is the type used to persist one minute sales data.
+// Package persist stores its fields in columns, so changing them needs a new version of its chunks.
+type OneMinSaleGob struct {
+	Year   int
+	Month  int
+	Day    int
+	Hour   int
+	Minute int
+	Open   float64
+	Close  float64
+	High   float64
+	Low    float64
+	Volume int64
+	VWAP   float64
+}
+
+// ToOneMinSale converts a persisted one minute sale. Unlike NewOneMinSale, sales of any age are allowed.
+func (omsg OneMinSaleGob) ToOneMinSale() (oms OneMinSale, err error) {
+	oms.startTime = omsg.StartTime()
+	oms.open = omsg.Open
+	oms.close = omsg.Close
+	oms.high = omsg.High
+	oms.low = omsg.Low
+	oms.volume = omsg.Volume
+	oms.vwap = omsg.VWAP
+
+	err = oms.validateStored()
+
+	return
+}
+
+// StartTime returns the time of the persisted one minute sale.
+func (omsg OneMinSaleGob) StartTime() time.Time {
+	return time.Date(omsg.Year, time.Month(omsg.Month), omsg.Day, omsg.Hour, omsg.Minute, 0, 0, time.UTC)
+}
+
+func (oms OneMinSale) ToGob() OneMinSaleGob {
+	omsg := OneMinSaleGob{}
+	omsg.Year = oms.startTime.Year()
+	omsg.Month = int(oms.startTime.Month())
+	omsg.Day = oms.startTime.Day()
+	omsg.Hour = oms.startTime.Hour()
+	omsg.Minute = oms.startTime.Minute()
+	omsg.Open = oms.open
+	omsg.Close = oms.close
+	omsg.High = oms.high
+	omsg.Low = oms.low
+	omsg.Volume = oms.volume
+	omsg.VWAP = oms.vwap
+
+	return omsg
+}
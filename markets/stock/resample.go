@@ -0,0 +1,143 @@
+package stock
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	PeriodWeekly Period = iota
+	PeriodMonthly
+	PeriodQuarterly
+)
+
+// SessionMinutes is the number of one minute periods in a regular trading session.
+const SessionMinutes = (MarketCloseHour-MarketOpenHour)*60 + MarketCloseMinute - MarketOpenMinute + 1
+
+// Period is a multi-day bar length.
+type Period int
+
+func (p Period) String() string {
+	switch p {
+	case PeriodWeekly:
+		return "Weekly"
+	case PeriodMonthly:
+		return "Monthly"
+	case PeriodQuarterly:
+		return "Quarterly"
+	}
+
+	return "Unknown"
+}
+
+// ResampleIntraday combines intraday bars into bars of the given number of minutes.
+// Bars are aligned to the market open, so 60 minute bars start at 9:30, 10:30, and so on,
+// and each bar's start time is the start of its interval rather than the first minute traded.
+func ResampleIntraday(bars []Bar, minutes int) ([]Bar, error) {
+	if minutes < 1 || minutes > SessionMinutes {
+		return nil, fmt.Errorf("invalid intraday bar length: %d minutes", minutes)
+	}
+
+	return resample(bars, func(b Bar) time.Time {
+		t := b.start
+		open := time.Date(t.Year(), t.Month(), t.Day(), MarketOpenHour, MarketOpenMinute, 0, 0, t.Location())
+		offset := int(t.Sub(open).Minutes())
+		if offset < 0 {
+			offset -= minutes - 1 // Round down before the open.
+		}
+
+		return open.Add(time.Duration(offset/minutes*minutes) * time.Minute)
+	}, true)
+}
+
+// ResampleSessions combines intraday bars into one bar per trading day.
+func ResampleSessions(bars []Bar) ([]Bar, error) {
+	return resample(bars, func(b Bar) time.Time {
+		return SessionDay(b.start)
+	}, false)
+}
+
+// SessionDay returns midnight of the trading day of an intraday time, to group times by session.
+func SessionDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// ResamplePeriods combines daily bars into weekly, monthly or quarterly bars.
+// Each bar starts and ends on the first and last sessions traded in the period, not on calendar boundaries.
+func ResamplePeriods(bars []Bar, period Period) ([]Bar, error) {
+	var key func(b Bar) time.Time
+	switch period {
+	case PeriodWeekly:
+		key = func(b Bar) time.Time {
+			year, week := b.start.ISOWeek()
+			return time.Date(year, 1, week, 0, 0, 0, 0, time.UTC)
+		}
+	case PeriodMonthly:
+		key = func(b Bar) time.Time {
+			return time.Date(b.start.Year(), b.start.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+	case PeriodQuarterly:
+		key = func(b Bar) time.Time {
+			return time.Date(b.start.Year(), (b.start.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+		}
+	default:
+		return nil, fmt.Errorf("invalid bar period: %d", period)
+	}
+
+	return resample(bars, key, false)
+}
+
+// resample combines consecutive bars with the same key.
+// If keyStart is true the key is used as the start time of each combined bar.
+func resample(bars []Bar, key func(Bar) time.Time, keyStart bool) ([]Bar, error) {
+	resampled := make([]Bar, 0)
+
+	first := 0
+	for i := range bars {
+		if i > 0 && !bars[i].start.After(bars[i-1].start) {
+			return nil, fmt.Errorf("bars must be in chronological order - previous time = %s, time = %s",
+				bars[i-1].start.Format("2006-01-02 15:04"), bars[i].start.Format("2006-01-02 15:04"))
+		}
+
+		if i == len(bars)-1 || !key(bars[i+1]).Equal(key(bars[i])) {
+			b := combine(bars[first : i+1])
+			if keyStart {
+				b.start = key(bars[first])
+			}
+			resampled = append(resampled, b)
+			first = i + 1
+		}
+	}
+
+	return resampled, nil
+}
+
+// combine aggregates bars into one. The VWAP is weighted by each bar's volume, and is left unknown (0)
+// if any bar's VWAP is unknown or there was no volume.
+func combine(bars []Bar) Bar {
+	b := Bar{
+		start: bars[0].start,
+		end:   bars[len(bars)-1].end,
+		open:  bars[0].open,
+		close: bars[len(bars)-1].close,
+		high:  math.Inf(-1),
+		low:   math.Inf(1),
+	}
+
+	pv := 0.0
+	vwapKnown := true
+	for _, bar := range bars {
+		b.high = math.Max(b.high, bar.high)
+		b.low = math.Min(b.low, bar.low)
+		b.volume += bar.volume
+		pv += bar.vwap * float64(bar.volume)
+		vwapKnown = vwapKnown && bar.vwap > 0
+	}
+
+	if vwapKnown && b.volume > 0 {
+		b.vwap = pv / float64(b.volume)
+	}
+
+	return b
+}
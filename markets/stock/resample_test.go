@@ -0,0 +1,215 @@
+package stock
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func testMinuteBar(t *testing.T, day, hour, minute int, open, close, high, low float64, volume int64, vwap float64) Bar {
+	t.Helper()
+
+	start := time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+	b, err := NewBar(start, start, open, close, high, low, volume, vwap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+// testDailyBars returns a bar for each weekday from the first through the last date.
+func testDailyBars(t *testing.T, first, last time.Time) []Bar {
+	t.Helper()
+
+	var bars []Bar
+	for day, i := first, 0; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+
+		price := 100 + float64(i)
+		b, err := NewBar(day, day, price, price+0.5, price+1, price-1, 1000, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bars = append(bars, b)
+		i++
+	}
+
+	return bars
+}
+
+func assertBarDates(t *testing.T, name string, b Bar, start, end string) {
+	t.Helper()
+
+	if got := b.Start().Format("2006-01-02"); got != start {
+		t.Errorf("%s start = %s, want %s", name, got, start)
+	}
+	if got := b.End().Format("2006-01-02"); got != end {
+		t.Errorf("%s end = %s, want %s", name, got, end)
+	}
+}
+
+func TestResampleIntradayVWAP(t *testing.T) {
+	bars := []Bar{
+		testMinuteBar(t, 4, 9, 30, 10.00, 10.20, 10.25, 9.95, 100, 10.10),
+		testMinuteBar(t, 4, 9, 31, 10.20, 10.40, 10.50, 10.15, 300, 10.30),
+		testMinuteBar(t, 4, 9, 33, 10.40, 10.10, 10.45, 10.05, 600, 10.20),
+		testMinuteBar(t, 4, 9, 35, 10.10, 10.30, 10.35, 10.00, 200, 10.25),
+	}
+
+	resampled, err := ResampleIntraday(bars, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resampled) != 2 {
+		t.Fatalf("got %d bars, want 2", len(resampled))
+	}
+
+	b := resampled[0]
+	if !b.Start().Equal(bars[0].Start()) || !b.End().Equal(bars[2].End()) {
+		t.Errorf("bar covers %s - %s, want 09:30 - 09:33", b.Start().Format("15:04"), b.End().Format("15:04"))
+	}
+	open, close := b.OpenClose()
+	high, low := b.HighLow()
+	if open != 10.00 || close != 10.10 || high != 10.50 || low != 9.95 || b.Volume() != 1000 {
+		t.Errorf("bar = %s, want open 10.00, close 10.10, high 10.50, low 9.95, volume 1000", b)
+	}
+
+	// (10.10 * 100 + 10.30 * 300 + 10.20 * 600) / 1000
+	if want := 10.22; math.Abs(b.VWAP()-want) > 1e-9 {
+		t.Errorf("VWAP = %.4f, want %.4f", b.VWAP(), want)
+	}
+
+	if got := resampled[1].Start().Format("15:04"); got != "09:35" {
+		t.Errorf("second bar start = %s, want 09:35", got)
+	}
+}
+
+func TestResampleIntradayUnknownVWAP(t *testing.T) {
+	bars := []Bar{
+		testMinuteBar(t, 4, 9, 30, 10.00, 10.20, 10.25, 9.95, 100, 10.10),
+		testMinuteBar(t, 4, 9, 31, 10.20, 10.40, 10.50, 10.15, 300, 0),
+	}
+
+	resampled, err := ResampleIntraday(bars, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resampled[0].VWAP() != 0 {
+		t.Errorf("VWAP = %.4f, want 0 when a bar's VWAP is unknown", resampled[0].VWAP())
+	}
+}
+
+// Hourly bars are aligned to the open, and start at the start of their interval.
+func TestResampleIntradayAlignment(t *testing.T) {
+	bars := []Bar{
+		testMinuteBar(t, 4, 10, 15, 10, 10, 10, 10, 100, 10),
+		testMinuteBar(t, 4, 10, 29, 10, 10, 10, 10, 100, 10),
+		testMinuteBar(t, 4, 10, 30, 10, 10, 10, 10, 100, 10),
+		testMinuteBar(t, 5, 9, 45, 10, 10, 10, 10, 100, 10),
+	}
+
+	resampled, err := ResampleIntraday(bars, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"2024-03-04 09:30", "2024-03-04 10:30", "2024-03-05 09:30"}
+	if len(resampled) != len(want) {
+		t.Fatalf("got %d bars, want %d", len(resampled), len(want))
+	}
+	for i, w := range want {
+		if got := resampled[i].Start().Format("2006-01-02 15:04"); got != w {
+			t.Errorf("bar %d start = %s, want %s", i, got, w)
+		}
+	}
+
+	if _, err := ResampleIntraday(bars, SessionMinutes+1); err == nil {
+		t.Error("resampling to more than a session succeeded, want an error")
+	}
+}
+
+func TestResampleSessions(t *testing.T) {
+	bars := []Bar{
+		testMinuteBar(t, 4, 9, 30, 10.00, 10.20, 10.25, 9.95, 100, 10.10),
+		testMinuteBar(t, 4, 15, 59, 10.20, 10.40, 10.50, 10.15, 300, 10.30),
+		testMinuteBar(t, 5, 9, 30, 10.40, 10.10, 10.45, 10.05, 600, 10.20),
+	}
+
+	resampled, err := ResampleSessions(bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resampled) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(resampled))
+	}
+	if resampled[0].Volume() != 400 || !SessionDay(resampled[0].End()).Equal(SessionDay(bars[0].Start())) {
+		t.Errorf("first session = %s, want the two bars of March 4", resampled[0])
+	}
+}
+
+// Weeks are ISO weeks, so the week starting Monday, December 30, 2024 includes the first days of 2025.
+func TestResamplePeriodsWeekly(t *testing.T) {
+	bars := testDailyBars(t,
+		time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC), time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC))
+
+	resampled, err := ResamplePeriods(bars, PeriodWeekly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resampled) != 3 {
+		t.Fatalf("got %d weeks, want 3", len(resampled))
+	}
+
+	assertBarDates(t, "week 1", resampled[0], "2024-12-25", "2024-12-27")
+	assertBarDates(t, "week 2", resampled[1], "2024-12-30", "2025-01-03")
+	assertBarDates(t, "week 3", resampled[2], "2025-01-06", "2025-01-08")
+
+	open, close := resampled[1].OpenClose()
+	if first, _ := bars[3].OpenClose(); open != first {
+		t.Errorf("week 2 open = %g, want the open of December 30, %g", open, first)
+	}
+	if _, last := bars[7].OpenClose(); close != last {
+		t.Errorf("week 2 close = %g, want the close of January 3, %g", close, last)
+	}
+	if resampled[1].Volume() != 5000 {
+		t.Errorf("week 2 volume = %d, want 5000", resampled[1].Volume())
+	}
+}
+
+// Months start and end on the first and last sessions of the month, not on calendar boundaries.
+func TestResamplePeriodsMonthly(t *testing.T) {
+	bars := testDailyBars(t,
+		time.Date(2024, 8, 28, 12, 0, 0, 0, time.UTC), time.Date(2024, 10, 2, 12, 0, 0, 0, time.UTC))
+
+	resampled, err := ResamplePeriods(bars, PeriodMonthly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resampled) != 3 {
+		t.Fatalf("got %d months, want 3", len(resampled))
+	}
+
+	assertBarDates(t, "August", resampled[0], "2024-08-28", "2024-08-30")
+	assertBarDates(t, "September", resampled[1], "2024-09-02", "2024-09-30")
+	assertBarDates(t, "October", resampled[2], "2024-10-01", "2024-10-02")
+
+	high, low := resampled[1].HighLow()
+	if high != 124 || low != 102 {
+		t.Errorf("September high, low = %g, %g, want 124, 102", high, low)
+	}
+}
+
+func TestResampleOutOfOrder(t *testing.T) {
+	bars := []Bar{
+		testMinuteBar(t, 4, 9, 31, 10, 10, 10, 10, 100, 10),
+		testMinuteBar(t, 4, 9, 30, 10, 10, 10, 10, 100, 10),
+	}
+
+	if _, err := ResampleIntraday(bars, 5); err == nil {
+		t.Error("resampling bars out of order succeeded, want an error")
+	}
+}
@@ -51,6 +51,16 @@ func (s *Stock) Prices() []DailyPrice {
 	return dps
 }
 
+func (s *Stock) OneMinSales() []OneMinSale {
+	s.Lock()
+	defer s.Unlock()
+
+	omss := make([]OneMinSale, len(s.oneMinSales))
+	copy(omss, s.oneMinSales)
+
+	return omss
+}
+
 func (s *Stock) SetQuote(q quote.Quote) {
 	s.Lock()
 	defer s.Unlock()
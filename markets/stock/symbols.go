@@ -4,12 +4,12 @@ import (
 	"io/ioutil"
 	"log"
 	"strings"
-)
 
-const symbolsFile = "../../resources/data/symbols.dat"
+	"github.com/tsilvers/realtime-securities/config"
+)
 
 func GetSymbols() []string {
-	allSymbols, err := ioutil.ReadFile(symbolsFile)
+	allSymbols, err := ioutil.ReadFile(config.SymbolsFile())
 	if err != nil {
 		log.Fatalln("Error reading list of stock symbols:", err)
 	}
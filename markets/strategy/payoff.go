@@ -0,0 +1,199 @@
+package strategy
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+)
+
+// Slopes smaller than this at the edge of the price grid are treated as flat.
+const flatSlope = 1e-6
+
+// CurvePoint holds the strategy's profit or loss at one underlying price.
+type CurvePoint struct {
+	Price       float64 // Underlying price.
+	Expiration  float64 // Profit or loss at the strategy's earliest expiration.
+	Theoretical float64 // Theoretical profit or loss at the curve's valuation time.
+}
+
+// Curve holds the profit and loss of a strategy across a grid of underlying prices.
+type Curve struct {
+	at     time.Time
+	expiry time.Time
+	points []CurvePoint
+}
+
+// PriceGrid returns count evenly spaced prices from low to high inclusive.
+func PriceGrid(low, high float64, count int) ([]float64, error) {
+	if low <= 0 || high <= low {
+		return nil, fmt.Errorf("invalid price grid range: %g - %g", low, high)
+	}
+
+	if count < 2 {
+		return nil, fmt.Errorf("price grid needs at least 2 points: %d", count)
+	}
+
+	step := (high - low) / float64(count-1)
+	grid := make([]float64, 0, count)
+	for i := 0; i < count; i++ {
+		grid = append(grid, low+step*float64(i))
+	}
+
+	return grid, nil
+}
+
+// Curve computes the expiration and theoretical profit or loss at each price in the grid.
+// The theoretical values use the market conditions with the underlying price replaced by each grid price.
+func (s Strategy) Curve(grid []float64, m option.Market) (c Curve, err error) {
+	c.at = m.Time
+	c.expiry = s.Expiration()
+	c.points = make([]CurvePoint, 0, len(grid))
+
+	for _, price := range grid {
+		pt := CurvePoint{Price: price}
+
+		if pt.Expiration, err = s.PayoffAtExpiration(price, m); err != nil {
+			return
+		}
+
+		m.Underlying = price
+		if pt.Theoretical, err = s.ProfitLoss(m); err != nil {
+			return
+		}
+
+		c.points = append(c.points, pt)
+	}
+
+	return
+}
+
+func (c Curve) Points() []CurvePoint {
+	return c.points
+}
+
+// Breakevens returns the underlying prices where the expiration profit or loss crosses zero.
+// Prices between grid points are linearly interpolated.
+func (c Curve) Breakevens() []float64 {
+	var breakevens []float64
+	for i, pt := range c.points {
+		if pt.Expiration == 0 {
+			breakevens = append(breakevens, pt.Price)
+			continue
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := c.points[i-1]
+		if (prev.Expiration < 0 && pt.Expiration > 0) || (prev.Expiration > 0 && pt.Expiration < 0) {
+			frac := prev.Expiration / (prev.Expiration - pt.Expiration)
+			breakevens = append(breakevens, prev.Price+frac*(pt.Price-prev.Price))
+		}
+	}
+
+	return breakevens
+}
+
+// MaxProfit returns the largest expiration profit over the grid, or below it down to a zero underlying.
+// Unlimited is true if the profit is still rising at the top of the grid.
+func (c Curve) MaxProfit() (profit float64, unlimited bool) {
+	profit = math.Inf(-1)
+	for _, pt := range c.points {
+		profit = math.Max(profit, pt.Expiration)
+	}
+	if zero, ok := c.atZero(); ok {
+		profit = math.Max(profit, zero)
+	}
+
+	return profit, c.edgeSlope() > flatSlope
+}
+
+// MaxLoss returns the largest expiration loss over the grid, or below it down to a zero underlying, as a negative number.
+// Unlimited is true if the loss is still growing at the top of the grid.
+func (c Curve) MaxLoss() (loss float64, unlimited bool) {
+	loss = math.Inf(1)
+	for _, pt := range c.points {
+		loss = math.Min(loss, pt.Expiration)
+	}
+	if zero, ok := c.atZero(); ok {
+		loss = math.Min(loss, zero)
+	}
+
+	return loss, c.edgeSlope() < -flatSlope
+}
+
+// edgeSlope returns the slope of the expiration profit or loss at the top of the grid.
+func (c Curve) edgeSlope() float64 {
+	n := len(c.points)
+	if n < 2 {
+		return 0
+	}
+
+	p0, p1 := c.points[n-2], c.points[n-1]
+
+	return (p1.Expiration - p0.Expiration) / (p1.Price - p0.Price)
+}
+
+// atZero returns the expiration profit or loss at a zero underlying, extending the slope at the bottom of the grid,
+// since the underlying cannot fall further. Strikes are expected to lie within the grid.
+func (c Curve) atZero() (float64, bool) {
+	if len(c.points) < 2 {
+		return 0, false
+	}
+
+	p0, p1 := c.points[0], c.points[1]
+	slope := (p1.Expiration - p0.Expiration) / (p1.Price - p0.Price)
+
+	return p0.Expiration - slope*p0.Price, true
+}
+
+func CurveHeader() string {
+	return fmt.Sprintln("   Price   Expiration  Theoretical")
+}
+
+func (pt CurvePoint) String() string {
+	return fmt.Sprintf("%8.2f %12.2f %12.2f", pt.Price, pt.Expiration, pt.Theoretical)
+}
+
+func (c Curve) String() string {
+	str := fmt.Sprintf("Valued: %s   Expiration: %s\n", c.at.Format("2006-01-02 15:04"), c.expiry.Format("2006-01-02"))
+	str += CurveHeader()
+	for _, pt := range c.points {
+		str += fmt.Sprintf("%s\n", pt)
+	}
+
+	return str
+}
+
+// WriteCSV writes the curve as CSV with a header row.
+func (c Curve) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"price", "expiration", "theoretical"}); err != nil {
+		return fmt.Errorf("could not write payoff curve: %w", err)
+	}
+
+	for _, pt := range c.points {
+		record := []string{
+			strconv.FormatFloat(pt.Price, 'f', 4, 64),
+			strconv.FormatFloat(pt.Expiration, 'f', 2, 64),
+			strconv.FormatFloat(pt.Theoretical, 'f', 2, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("could not write payoff curve: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("could not write payoff curve: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+)
+
+// Leg holds one option contract of a strategy and the position taken in it.
+type Leg struct {
+	option   option.Option
+	quantity int     // Number of contracts, positive for long and negative for short.
+	premium  float64 // Price paid or received per share when the position was opened.
+}
+
+func NewLeg(o option.Option, quantity int, premium float64) (l Leg, err error) {
+	l.option = o
+	l.quantity = quantity
+	l.premium = premium
+
+	err = l.Validate()
+
+	return
+}
+
+func (l Leg) Validate() error {
+	if err := l.option.Validate(); err != nil {
+		return err
+	}
+
+	if l.quantity == 0 {
+		return fmt.Errorf("leg quantity cannot be zero: %s", l.option)
+	}
+
+	if l.premium < 0 {
+		return fmt.Errorf("invalid leg premium: %g; option: %s", l.premium, l.option)
+	}
+
+	return nil
+}
+
+func (l Leg) Option() option.Option {
+	return l.option
+}
+
+func (l Leg) Quantity() int {
+	return l.quantity
+}
+
+func (l Leg) Premium() float64 {
+	return l.premium
+}
+
+// shares returns the signed number of shares controlled by the leg.
+func (l Leg) shares() float64 {
+	return float64(l.quantity * l.option.Size())
+}
+
+// value returns the theoretical value of the leg's position under the given market conditions.
+func (l Leg) value(m option.Market) (float64, error) {
+	g, err := option.BlackScholes(l.option, m)
+	if err != nil {
+		return 0, err
+	}
+
+	return g.Price * l.shares(), nil
+}
+
+func (l Leg) String() string {
+	return fmt.Sprintf("%+4d %s @ %.2f", l.quantity, l.option, l.premium)
+}
+
+// Strategy holds the legs of a multi-leg option position on a single underlying stock.
+type Strategy struct {
+	name string
+	legs []Leg
+}
+
+func NewStrategy(name string, legs ...Leg) (s Strategy, err error) {
+	s.name = name
+	s.legs = legs
+
+	err = s.Validate()
+
+	return
+}
+
+func (s Strategy) Validate() error {
+	if len(s.legs) == 0 {
+		return fmt.Errorf("strategy %s has no legs", s.name)
+	}
+
+	symbol := s.legs[0].option.Symbol()
+	for _, leg := range s.legs {
+		if err := leg.Validate(); err != nil {
+			return fmt.Errorf("strategy %s: %w", s.name, err)
+		}
+
+		if leg.option.Symbol() != symbol {
+			return fmt.Errorf("strategy %s has legs on more than one underlying: %s, %s", s.name, symbol, leg.option.Symbol())
+		}
+	}
+
+	return nil
+}
+
+func (s Strategy) Name() string {
+	return s.name
+}
+
+func (s Strategy) Legs() []Leg {
+	return s.legs
+}
+
+// Expiration returns the earliest expiration date of the strategy's legs.
+func (s Strategy) Expiration() time.Time {
+	exp := s.legs[0].option.ExpirationDate()
+	for _, leg := range s.legs[1:] {
+		if leg.option.ExpirationDate().Before(exp) {
+			exp = leg.option.ExpirationDate()
+		}
+	}
+
+	return exp
+}
+
+// NetPremium returns the cost of opening the strategy.
+// A positive value is a net debit, a negative value is a net credit.
+func (s Strategy) NetPremium() float64 {
+	premium := 0.0
+	for _, leg := range s.legs {
+		premium += leg.premium * leg.shares()
+	}
+
+	return premium
+}
+
+// Value returns the theoretical value of all legs under the given market conditions.
+// Legs that have expired by the market time are valued at intrinsic value.
+func (s Strategy) Value(m option.Market) (float64, error) {
+	total := 0.0
+	for _, leg := range s.legs {
+		v, err := leg.value(m)
+		if err != nil {
+			return 0, fmt.Errorf("strategy %s: %w", s.name, err)
+		}
+		total += v
+	}
+
+	return total, nil
+}
+
+// ProfitLoss returns the theoretical profit or loss of the strategy under the given market conditions.
+func (s Strategy) ProfitLoss(m option.Market) (float64, error) {
+	v, err := s.Value(m)
+	if err != nil {
+		return 0, err
+	}
+
+	return v - s.NetPremium(), nil
+}
+
+// PayoffAtExpiration returns the profit or loss at the strategy's earliest expiration for the given underlying price.
+// Legs expiring later (calendars and diagonals) are valued with the remaining market conditions.
+func (s Strategy) PayoffAtExpiration(underlying float64, m option.Market) (float64, error) {
+	m.Underlying = underlying
+	m.Time = s.Expiration()
+
+	return s.ProfitLoss(m)
+}
+
+// Greeks returns the position greeks of the strategy, in dollars per share moved.
+func (s Strategy) Greeks(m option.Market) (option.Greeks, error) {
+	total := option.Greeks{}
+	for _, leg := range s.legs {
+		g, err := option.BlackScholes(leg.option, m)
+		if err != nil {
+			return total, fmt.Errorf("strategy %s: %w", s.name, err)
+		}
+		total = total.Add(g, leg.shares())
+	}
+
+	return total, nil
+}
+
+func (s Strategy) String() string {
+	str := fmt.Sprintf("Strategy: %s\n", s.name)
+	for _, leg := range s.legs {
+		str += fmt.Sprintf("  %s\n", leg)
+	}
+
+	return str
+}
@@ -0,0 +1,156 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+)
+
+var testExpiration = time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)
+
+func testLeg(t *testing.T, strike float64, call bool, quantity int, premium float64) Leg {
+	t.Helper()
+
+	o, err := option.NewOption("TEST", testExpiration, strike, call, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leg, err := NewLeg(o, quantity, premium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return leg
+}
+
+func testSummary(t *testing.T, s Strategy) Summary {
+	t.Helper()
+
+	grid, err := PriceGrid(70, 130, 61)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := option.Market{Underlying: 100, Rate: 0.02, Volatility: 0.25, Time: testExpiration.AddDate(0, 0, -30)}
+
+	sum, err := s.Summarize(grid, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sum
+}
+
+func assertBreakevens(t *testing.T, got, want []float64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("breakevens = %v, want %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("breakevens = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBullCallSpread(t *testing.T) {
+	s, err := NewStrategy("bull call spread", testLeg(t, 100, true, 1, 5), testLeg(t, 110, true, -1, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := testSummary(t, s)
+	if sum.NetPremium != 300 {
+		t.Errorf("net premium = %g, want 300", sum.NetPremium)
+	}
+	assertBreakevens(t, sum.Breakevens, []float64{103})
+	if math.Abs(sum.MaxProfit-700) > 1e-9 || sum.UnlimitedProfit {
+		t.Errorf("max profit = %g (unlimited %t), want 700", sum.MaxProfit, sum.UnlimitedProfit)
+	}
+	if math.Abs(sum.MaxLoss+300) > 1e-9 || sum.UnlimitedLoss {
+		t.Errorf("max loss = %g (unlimited %t), want -300", sum.MaxLoss, sum.UnlimitedLoss)
+	}
+
+	// Long the lower strike: long delta, with the short call partly offsetting it.
+	if sum.Greeks.Delta <= 0 || sum.Greeks.Delta >= 100 {
+		t.Errorf("delta = %g, want between 0 and 100", sum.Greeks.Delta)
+	}
+}
+
+func TestIronCondor(t *testing.T) {
+	s, err := NewStrategy("iron condor",
+		testLeg(t, 90, false, 1, 1),
+		testLeg(t, 95, false, -1, 2),
+		testLeg(t, 105, true, -1, 2),
+		testLeg(t, 110, true, 1, 1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := testSummary(t, s)
+	if sum.NetPremium != -200 {
+		t.Errorf("net premium = %g, want -200", sum.NetPremium)
+	}
+	assertBreakevens(t, sum.Breakevens, []float64{93, 107})
+	if math.Abs(sum.MaxProfit-200) > 1e-9 || sum.UnlimitedProfit {
+		t.Errorf("max profit = %g (unlimited %t), want 200", sum.MaxProfit, sum.UnlimitedProfit)
+	}
+	if math.Abs(sum.MaxLoss+300) > 1e-9 || sum.UnlimitedLoss {
+		t.Errorf("max loss = %g (unlimited %t), want -300", sum.MaxLoss, sum.UnlimitedLoss)
+	}
+}
+
+func TestNakedCallUnlimitedLoss(t *testing.T) {
+	s, err := NewStrategy("short call", testLeg(t, 100, true, -1, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := testSummary(t, s)
+	assertBreakevens(t, sum.Breakevens, []float64{103})
+	if !sum.UnlimitedLoss || sum.UnlimitedProfit {
+		t.Errorf("unlimited loss = %t, unlimited profit = %t, want true, false", sum.UnlimitedLoss, sum.UnlimitedProfit)
+	}
+}
+
+func TestCurveTheoreticalAtExpiration(t *testing.T) {
+	s, err := NewStrategy("long put", testLeg(t, 100, false, 2, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := option.Market{Underlying: 100, Rate: 0.02, Volatility: 0.25, Time: testExpiration}
+	c, err := s.Curve([]float64{80, 100, 120}, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pt := range c.Points() {
+		if math.Abs(pt.Theoretical-pt.Expiration) > 1e-9 {
+			t.Errorf("at %g theoretical = %g, expiration = %g, want equal at expiration", pt.Price, pt.Theoretical, pt.Expiration)
+		}
+	}
+	if got := c.Points()[0].Expiration; math.Abs(got-(2*100*20-800)) > 1e-9 {
+		t.Errorf("expiration P&L at 80 = %g, want 3200", got)
+	}
+}
+
+// A long put's profit is capped by the underlying falling to zero, below the bottom of the grid.
+func TestLongPutProfitAtZero(t *testing.T) {
+	s, err := NewStrategy("long put", testLeg(t, 100, false, 1, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := testSummary(t, s)
+	assertBreakevens(t, sum.Breakevens, []float64{96})
+	if math.Abs(sum.MaxProfit-9600) > 1e-9 || sum.UnlimitedProfit {
+		t.Errorf("max profit = %g (unlimited %t), want 9600", sum.MaxProfit, sum.UnlimitedProfit)
+	}
+	if math.Abs(sum.MaxLoss+400) > 1e-9 || sum.UnlimitedLoss {
+		t.Errorf("max loss = %g (unlimited %t), want -400", sum.MaxLoss, sum.UnlimitedLoss)
+	}
+}
@@ -0,0 +1,66 @@
+package strategy
+
+import (
+	"fmt"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+)
+
+// Summary holds the risk profile of a strategy over a price grid.
+type Summary struct {
+	NetPremium      float64 // Positive for a net debit, negative for a net credit.
+	Breakevens      []float64
+	MaxProfit       float64
+	UnlimitedProfit bool
+	MaxLoss         float64
+	UnlimitedLoss   bool
+	Greeks          option.Greeks // Position greeks at the market time.
+}
+
+// Summarize computes the strategy's risk profile over the price grid under the given market conditions.
+func (s Strategy) Summarize(grid []float64, m option.Market) (sum Summary, err error) {
+	c, err := s.Curve(grid, m)
+	if err != nil {
+		return
+	}
+
+	sum.NetPremium = s.NetPremium()
+	sum.Breakevens = c.Breakevens()
+	sum.MaxProfit, sum.UnlimitedProfit = c.MaxProfit()
+	sum.MaxLoss, sum.UnlimitedLoss = c.MaxLoss()
+	sum.Greeks, err = s.Greeks(m)
+
+	return
+}
+
+func (sum Summary) String() string {
+	str := ""
+	if sum.NetPremium >= 0 {
+		str += fmt.Sprintf("Net Debit:   %10.2f\n", sum.NetPremium)
+	} else {
+		str += fmt.Sprintf("Net Credit:  %10.2f\n", -sum.NetPremium)
+	}
+
+	if sum.UnlimitedProfit {
+		str += "Max Profit:   unlimited\n"
+	} else {
+		str += fmt.Sprintf("Max Profit:  %10.2f\n", sum.MaxProfit)
+	}
+
+	if sum.UnlimitedLoss {
+		str += "Max Loss:     unlimited\n"
+	} else {
+		str += fmt.Sprintf("Max Loss:    %10.2f\n", sum.MaxLoss)
+	}
+
+	str += "Breakevens: "
+	for _, be := range sum.Breakevens {
+		str += fmt.Sprintf(" %.2f", be)
+	}
+	str += "\n"
+
+	g := sum.Greeks
+	str += fmt.Sprintf("Delta: %.2f  Gamma: %.4f  Theta: %.2f  Vega: %.2f  Rho: %.2f\n", g.Delta, g.Gamma, g.Theta, g.Vega, g.Rho)
+
+	return str
+}
@@ -0,0 +1,56 @@
+package persist
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic replaces the file with data by writing a temporary file in the same directory and renaming it,
+// so the file holds either its old or its new data if writing fails or the system crashes.
+func writeFileAtomic(fname string, data []byte) (err error) {
+	dir, base := filepath.Split(fname)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), fname); err != nil {
+		return err
+	}
+
+	syncDir(dir)
+
+	return nil
+}
+
+// syncDir flushes the directory, so a rename in it survives a crash. Not all systems support it,
+// so failures are ignored.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+}
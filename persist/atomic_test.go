@@ -0,0 +1,70 @@
+package persist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// assertOnlyFile checks that the directory holds only the named file, with no temporary files left behind.
+func assertOnlyFile(t *testing.T, dir, name string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != name {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("directory holds %v, want only %s", names, name)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "MSFT")
+
+	for _, data := range []string{"first version", "second"} {
+		if err := writeFileAtomic(fname, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := os.ReadFile(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != data {
+			t.Errorf("file holds %q, want %q", got, data)
+		}
+	}
+
+	info, err := os.Stat(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("file mode %v, want %v", info.Mode().Perm(), os.FileMode(0644))
+	}
+	assertOnlyFile(t, dir, "MSFT")
+}
+
+func TestWriteFileAtomicFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "MSFT"), []byte("data")); err == nil {
+		t.Error("missing directory: want error")
+	}
+
+	// A directory cannot be replaced by a file, so the rename fails and the temporary file is removed.
+	target := filepath.Join(dir, "MSFT")
+	if err := os.Mkdir(target, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(target, []byte("data")); err == nil {
+		t.Error("directory in the way: want error")
+	}
+	assertOnlyFile(t, dir, "MSFT")
+}
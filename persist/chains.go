@@ -0,0 +1,57 @@
+package persist
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+)
+
+// SaveChainSnapshot appends an option chain snapshot to the symbol's snapshot history.
+// Earlier snapshots are never modified.
+func SaveChainSnapshot(symbol string, snapshot option.ChainSnapshotGob) error {
+	unlock, err := lockStore(ChainSnapshotRecords, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	fname := chainsDir() + stockFilename(symbol)
+
+	if err := os.MkdirAll(chainsDir(), 0755); err != nil {
+		return fmt.Errorf("could not persist option chain snapshot for %s to file %s: %w", symbol, fname, err)
+	}
+
+	if err := appendRecord(fname, ChainSnapshotRecords, snapshot); err != nil {
+		return fmt.Errorf("could not persist option chain snapshot for %s to file %s: %w", symbol, fname, err)
+	}
+
+	return nil
+}
+
+// LoadChainSnapshots loads all option chain snapshots for the symbol in the order they were saved.
+func LoadChainSnapshots(symbol string) ([]*option.ChainSnapshotGob, error) {
+	var snapshots []*option.ChainSnapshotGob
+
+	unlock, err := lockStore(ChainSnapshotRecords, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fname := chainsDir() + stockFilename(symbol)
+	err = readRecords(fname, ChainSnapshotRecords, func(dec *gob.Decoder) error {
+		snapshot := &option.ChainSnapshotGob{}
+		if err := dec.Decode(snapshot); err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snapshot)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load option chain snapshots for %s from file %s: %w", symbol, fname, err)
+	}
+
+	return snapshots, nil
+}
@@ -5,56 +5,160 @@ import (
 	"errors"
 	"fmt"
 	"github.com/tsilvers/realtime-securities/markets/stock"
-	"io"
+	"io/fs"
 	"os"
+	"sort"
+	"time"
 )
 
-func InitPricesStore() {
-	_ = os.RemoveAll(pricesDir)
-	_ = os.MkdirAll(pricesDir, 0755)
+// InitPricesStore removes all stored prices, for a full rebuild.
+func InitPricesStore() error {
+	unlock, err := lockStore(PriceRecords, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.RemoveAll(pricesDir()); err != nil {
+		return fmt.Errorf("could not remove prices store %s: %w", pricesDir(), err)
+	}
+	if err := os.MkdirAll(pricesDir(), 0755); err != nil {
+		return fmt.Errorf("could not create prices store %s: %w", pricesDir(), err)
+	}
+
+	return nil
 }
 
+// EnsurePricesStore creates the prices store if it does not exist, keeping any stored prices.
+func EnsurePricesStore() error {
+	if err := os.MkdirAll(pricesDir(), 0755); err != nil {
+		return fmt.Errorf("could not create prices store %s: %w", pricesDir(), err)
+	}
+
+	return nil
+}
+
+// SavePrices replaces the symbol's stored prices.
 func SavePrices(symbol string, prices []stock.DailyPriceGob) error {
-	fname := pricesDir + stockFilename(symbol)
-	file, err := os.Create(fname)
+	unlock, err := lockStore(PriceRecords, true)
 	if err != nil {
-		return fmt.Errorf("could not persist prices for %s to file %s: %w", symbol, fname, err)
+		return err
+	}
+	defer unlock()
+
+	return savePrices(symbol, prices)
+}
+
+func LoadPrices(symbol string) ([]*stock.DailyPriceGob, error) {
+	unlock, err := lockStore(PriceRecords, false)
+	if err != nil {
+		return nil, err
 	}
-	defer file.Close()
+	defer unlock()
 
-	enc := gob.NewEncoder(file)
+	return loadPrices(symbol)
+}
 
-	for _, price := range prices {
-		if err := enc.Encode(price); err != nil {
-			return fmt.Errorf("could not persist prices for %s to file %s: %w", symbol, fname, err)
+func savePrices(symbol string, prices []stock.DailyPriceGob) error {
+	fname := pricesDir() + stockFilename(symbol)
+	err := writeRecords(fname, PriceRecords, func(enc *gob.Encoder) error {
+		for _, price := range prices {
+			if err := enc.Encode(price); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist prices for %s to file %s: %w", symbol, fname, err)
 	}
 
 	return nil
 }
 
-func LoadPrices(symbol string) ([]*stock.DailyPriceGob, error) {
+func loadPrices(symbol string) ([]*stock.DailyPriceGob, error) {
 	var prices []*stock.DailyPriceGob
 
-	fname := pricesDir + stockFilename(symbol)
-	file, err := os.Open(fname)
+	fname := pricesDir() + stockFilename(symbol)
+	err := readRecords(fname, PriceRecords, func(dec *gob.Decoder) error {
+		price := &stock.DailyPriceGob{}
+		if err := dec.Decode(price); err != nil {
+			return err
+		}
+		prices = append(prices, price)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load prices for %s from file %s: %w", symbol, fname, err)
+	}
+
+	return prices, nil
+}
+
+// LastPriceDate returns the date of the latest stored price for the symbol, or the zero time if none are stored.
+func LastPriceDate(symbol string) (time.Time, error) {
+	unlock, err := lockStore(PriceRecords, false)
 	if err != nil {
-		return prices, fmt.Errorf("could not load prices for %s from file %s: %w", symbol, fname, err)
+		return time.Time{}, err
 	}
-	defer file.Close()
+	defer unlock()
 
-	enc := gob.NewDecoder(file)
+	prices, err := loadStoredPrices(symbol)
+	if err != nil || len(prices) == 0 {
+		return time.Time{}, err
+	}
 
-	for err == nil {
-		price := &stock.DailyPriceGob{}
-		if err = enc.Decode(price); err == nil {
-			prices = append(prices, price)
+	last := time.Time{}
+	for _, price := range prices {
+		if date := priceDate(*price); date.After(last) {
+			last = date
 		}
 	}
 
-	if !errors.Is(io.EOF, err) {
-		return nil, fmt.Errorf("could not load prices for %s from file %s: %w", symbol, fname, err)
+	return last, nil
+}
+
+// UpsertPrices merges prices into the symbol's stored prices, replacing stored prices with the same date,
+// and saves them in date order. Prices of other symbols are not affected.
+func UpsertPrices(symbol string, prices []stock.DailyPriceGob) error {
+	unlock, err := lockStore(PriceRecords, true)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	return prices, nil
+	stored, err := loadStoredPrices(symbol)
+	if err != nil {
+		return err
+	}
+
+	byDate := make(map[time.Time]stock.DailyPriceGob, len(stored)+len(prices))
+	for _, price := range stored {
+		byDate[priceDate(*price)] = *price
+	}
+	for _, price := range prices {
+		byDate[priceDate(price)] = price
+	}
+
+	merged := make([]stock.DailyPriceGob, 0, len(byDate))
+	for _, price := range byDate {
+		merged = append(merged, price)
+	}
+	sort.Slice(merged, func(i, j int) bool { return priceDate(merged[i]).Before(priceDate(merged[j])) })
+
+	return savePrices(symbol, merged)
+}
+
+// loadStoredPrices loads the symbol's prices, returning none if no prices have been stored.
+func loadStoredPrices(symbol string) ([]*stock.DailyPriceGob, error) {
+	prices, err := loadPrices(symbol)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	return prices, err
+}
+
+func priceDate(price stock.DailyPriceGob) time.Time {
+	return time.Date(price.Year, time.Month(price.Month), price.Day, 0, 0, 0, 0, time.UTC)
 }
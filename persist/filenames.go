@@ -1,10 +1,102 @@
 package persist
 
-import "strings"
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
-const optionsDir = "../../resources/store/options/"
-const pricesDir = "../../resources/store/prices/"
+	"github.com/tsilvers/realtime-securities/config"
+)
+
+func chainsDir() string  { return storeDir("chains") }
+func minutesDir() string { return storeDir("minutes") }
+func optionsDir() string { return storeDir("options") }
+func pricesDir() string  { return storeDir("prices") }
+func quotesDir() string  { return storeDir("quotes") }
+
+// storeDir returns the named directory of the store, with a trailing separator.
+func storeDir(name string) string {
+	return filepath.Join(config.StoreDir(), name) + string(filepath.Separator)
+}
+
+// dayFileFmt is the name format of files holding a day of data.
+const dayFileFmt = "2006-01-02"
 
 func stockFilename(symbol string) string {
 	return strings.Replace(symbol, "/", ".", 1)
 }
+
+// storeFiles returns the files of the record type in the store: a file for each symbol, or for
+// one minute sales and quote snapshots, a file for each symbol and day. Temporary files are skipped,
+// as are symbols without a one minute sales index.
+func storeFiles(rt RecordType) ([]string, error) {
+	dir := rt.dir()
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fnames []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		if rt != OneMinSaleRecords && rt != QuoteSnapshotRecords && rt != MinuteIndexRecords {
+			if !entry.IsDir() {
+				fnames = append(fnames, dir+entry.Name())
+			}
+			continue
+		}
+
+		if !entry.IsDir() {
+			continue
+		}
+		symbolDir := dir + entry.Name() + string(filepath.Separator)
+		if rt == MinuteIndexRecords {
+			if _, err := os.Stat(symbolDir + minuteIndexName); err == nil {
+				fnames = append(fnames, symbolDir+minuteIndexName)
+			}
+			continue
+		}
+
+		days, err := listDays(symbolDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range days {
+			fnames = append(fnames, symbolDir+day.Format(dayFileFmt))
+		}
+	}
+
+	return fnames, nil
+}
+
+// listDays returns the days of the day files in the directory, in date order.
+// No days are returned if the directory does not exist.
+func listDays(dir string) ([]time.Time, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var days []time.Time
+	for _, entry := range entries {
+		day, err := time.Parse(dayFileFmt, entry.Name())
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
@@ -0,0 +1,259 @@
+package persist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/quote"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Stored files start with a header identifying the file format and what it holds:
+//
+//	magic   4 bytes  "RSDF"
+//	version uint16   version of the record type's fields, as in stock.DailyPriceGobVersion
+//	type    uint16   record type
+//	crc     uint32   CRC-32 (IEEE) of the preceding 8 bytes
+//
+// followed by frames, each holding a gob stream, or for one minute sales a chunk (see minutechunk.go):
+//
+//	length  uint32   payload length
+//	crc     uint32   CRC-32 (IEEE) of the payload
+//	payload
+//
+// Files written before the header was added are legacy files, version 0 of their record type. Legacy prices and
+// expirations files are a single gob stream; legacy appended files are frames without the crc.
+// Files older than the current version of their record type are upgraded by migrations (see migrate.go)
+// when they are read or appended to.
+
+const (
+	headerSize = 12
+	frameSize  = 8
+	maxFrame   = 1 << 30 // Larger frame lengths are corrupt.
+)
+
+var magic = [4]byte{'R', 'S', 'D', 'F'}
+
+var (
+	ErrCorrupt            = errors.New("stored file is corrupt")
+	ErrLegacyFormat       = errors.New("stored file has the legacy format")
+	ErrOutdated           = errors.New("stored file has an older version")
+	ErrUnsupportedVersion = errors.New("stored file has a newer version")
+)
+
+// errChecksum is returned for a frame whose payload does not match its checksum. The frame has been read whole,
+// so the frames after it can still be read.
+var errChecksum = fmt.Errorf("%w: frame checksum mismatch", ErrCorrupt)
+
+// RecordType identifies the data held by a stored file.
+type RecordType uint16
+
+const (
+	PriceRecords RecordType = iota + 1
+	ExpirationRecords
+	ChainSnapshotRecords
+	OneMinSaleRecords
+	QuoteSnapshotRecords
+	MinuteIndexRecords
+)
+
+// recordTypes are all record types, in the order they are migrated.
+var recordTypes = []RecordType{PriceRecords, ExpirationRecords, ChainSnapshotRecords, OneMinSaleRecords, QuoteSnapshotRecords, MinuteIndexRecords}
+
+func (rt RecordType) String() string {
+	switch rt {
+	case PriceRecords:
+		return "daily prices"
+	case ExpirationRecords:
+		return "option expirations"
+	case ChainSnapshotRecords:
+		return "option chain snapshots"
+	case OneMinSaleRecords:
+		return "one minute sales"
+	case QuoteSnapshotRecords:
+		return "quote snapshots"
+	case MinuteIndexRecords:
+		return "one minute sales index"
+	default:
+		return fmt.Sprintf("record type %d", uint16(rt))
+	}
+}
+
+// Version returns the current version of the record type's fields.
+func (rt RecordType) Version() uint16 {
+	switch rt {
+	case PriceRecords:
+		return stock.DailyPriceGobVersion
+	case ExpirationRecords:
+		return option.ExpirationGobVersion
+	case ChainSnapshotRecords:
+		return option.ChainSnapshotGobVersion
+	case OneMinSaleRecords:
+		return minuteChunkVersion
+	case MinuteIndexRecords:
+		return minuteIndexVersion
+	default:
+		return quote.SnapshotGobVersion
+	}
+}
+
+// countRecords decodes a frame as records of the current version and returns their number.
+func (rt RecordType) countRecords(payload []byte) (n int, err error) {
+	if rt == OneMinSaleRecords {
+		sales, err := decodeMinuteChunk(payload)
+		return len(sales), err
+	}
+
+	dec := gob.NewDecoder(bytes.NewReader(payload))
+	for {
+		cnt := 1
+		switch rt {
+		case PriceRecords:
+			err = dec.Decode(&stock.DailyPriceGob{})
+		case ExpirationRecords:
+			err = dec.Decode(&option.ExpirationGob{})
+		case ChainSnapshotRecords:
+			err = dec.Decode(&option.ChainSnapshotGob{})
+		case MinuteIndexRecords:
+			var index []minuteChunkInfo
+			err = dec.Decode(&index)
+			cnt = len(index)
+		default:
+			var snapshots []quote.SnapshotGob
+			err = dec.Decode(&snapshots)
+			cnt = len(snapshots)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n += cnt
+	}
+}
+
+// dir returns the store directory holding files of the record type.
+func (rt RecordType) dir() string {
+	switch rt {
+	case PriceRecords:
+		return pricesDir()
+	case ExpirationRecords:
+		return optionsDir()
+	case ChainSnapshotRecords:
+		return chainsDir()
+	case OneMinSaleRecords, MinuteIndexRecords:
+		return minutesDir()
+	default:
+		return quotesDir()
+	}
+}
+
+// legacyStream returns true if legacy files of the record type are a single gob stream rather than frames.
+func (rt RecordType) legacyStream() bool {
+	return rt == PriceRecords || rt == ExpirationRecords
+}
+
+// Header is the header of a stored file.
+type Header struct {
+	Version uint16
+	Type    RecordType
+}
+
+// check returns an error if the file does not hold the record type or has a version newer than the current version.
+func (h Header) check(rt RecordType) error {
+	if h.Type != rt {
+		return fmt.Errorf("%w: file holds %s, not %s", ErrCorrupt, h.Type, rt)
+	}
+	if h.Version > rt.Version() {
+		return fmt.Errorf("%w: %s version %d, current version %d", ErrUnsupportedVersion, rt, h.Version, rt.Version())
+	}
+
+	return nil
+}
+
+func header(rt RecordType) []byte {
+	buf := make([]byte, headerSize)
+	copy(buf, magic[:])
+	binary.BigEndian.PutUint16(buf[4:], rt.Version())
+	binary.BigEndian.PutUint16(buf[6:], uint16(rt))
+	binary.BigEndian.PutUint32(buf[8:], crc32.ChecksumIEEE(buf[:8]))
+
+	return buf
+}
+
+// readHeader reads the header of a stored file, returning ErrLegacyFormat without reading anything if the
+// file has no header. Empty files are legacy files.
+func readHeader(r *bufio.Reader) (h Header, err error) {
+	start, err := r.Peek(len(magic))
+	if errors.Is(err, io.EOF) || (err == nil && !bytes.Equal(start, magic[:])) {
+		return h, ErrLegacyFormat
+	}
+	if err != nil {
+		return h, err
+	}
+
+	buf := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return h, fmt.Errorf("%w: header is truncated", ErrCorrupt)
+	}
+
+	if crc32.ChecksumIEEE(buf[:8]) != binary.BigEndian.Uint32(buf[8:]) {
+		return h, fmt.Errorf("%w: header checksum mismatch", ErrCorrupt)
+	}
+
+	h.Version = binary.BigEndian.Uint16(buf[4:])
+	h.Type = RecordType(binary.BigEndian.Uint16(buf[6:]))
+
+	return h, nil
+}
+
+// frame returns the payload in a frame.
+func frame(payload []byte) []byte {
+	buf := make([]byte, frameSize, frameSize+len(payload))
+	binary.BigEndian.PutUint32(buf, uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(payload))
+
+	return append(buf, payload...)
+}
+
+// readFrame returns the payload of the next frame, or io.EOF if there are no more frames.
+// Legacy frames have no crc.
+func readFrame(r io.Reader, legacy bool) ([]byte, error) {
+	size := frameSize
+	if legacy {
+		size = 4
+	}
+
+	buf := make([]byte, size)
+	if n, err := io.ReadFull(r, buf); err != nil {
+		if n == 0 && errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: frame is truncated", ErrCorrupt)
+	}
+
+	length := binary.BigEndian.Uint32(buf)
+	if length > maxFrame {
+		return nil, fmt.Errorf("%w: frame length %d", ErrCorrupt, length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, fmt.Errorf("%w: frame is truncated", ErrCorrupt)
+	}
+
+	if !legacy && crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(buf[4:]) {
+		return nil, errChecksum
+	}
+
+	return payload, nil
+}
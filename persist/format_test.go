@@ -0,0 +1,174 @@
+package persist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	for _, rt := range recordTypes {
+		data := header(rt)
+		if len(data) != headerSize {
+			t.Fatalf("%s header is %d bytes, want %d", rt, len(data), headerSize)
+		}
+
+		h, err := readHeader(bufio.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Fatalf("%s: %v", rt, err)
+		}
+		if h.Type != rt || h.Version != rt.Version() {
+			t.Errorf("%s header = %+v, want type %d version %d", rt, h, rt, rt.Version())
+		}
+		if err := h.check(rt); err != nil {
+			t.Errorf("%s: %v", rt, err)
+		}
+	}
+}
+
+func TestHeaderCheck(t *testing.T) {
+	if err := (Header{Version: 1, Type: ExpirationRecords}).check(PriceRecords); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("other record type: %v, want %v", err, ErrCorrupt)
+	}
+
+	newer := Header{Version: PriceRecords.Version() + 1, Type: PriceRecords}
+	if err := newer.check(PriceRecords); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("newer version: %v, want %v", err, ErrUnsupportedVersion)
+	}
+}
+
+func TestReadHeaderLegacy(t *testing.T) {
+	if _, err := readHeader(bufio.NewReader(bytes.NewReader(nil))); !errors.Is(err, ErrLegacyFormat) {
+		t.Errorf("empty file: %v, want %v", err, ErrLegacyFormat)
+	}
+
+	var stream bytes.Buffer
+	if err := gob.NewEncoder(&stream).Encode(testPrices(4)[0]); err != nil {
+		t.Fatal(err)
+	}
+	legacy := stream.Bytes()
+
+	r := bufio.NewReader(bytes.NewReader(legacy))
+	if _, err := readHeader(r); !errors.Is(err, ErrLegacyFormat) {
+		t.Fatalf("gob stream: %v, want %v", err, ErrLegacyFormat)
+	}
+
+	// Nothing is consumed, so the stream can still be decoded.
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rest, legacy) {
+		t.Errorf("%d bytes left after the legacy header check, want %d", len(rest), len(legacy))
+	}
+}
+
+func TestReadHeaderCorrupt(t *testing.T) {
+	data := header(PriceRecords)
+	data[5]++ // Version changed without updating the checksum.
+	if _, err := readHeader(bufio.NewReader(bytes.NewReader(data))); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("checksum mismatch: %v, want %v", err, ErrCorrupt)
+	}
+
+	truncated := header(PriceRecords)[:headerSize-1]
+	if _, err := readHeader(bufio.NewReader(bytes.NewReader(truncated))); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("truncated header: %v, want %v", err, ErrCorrupt)
+	}
+}
+
+// readAllFrames reads all frames of the data, returning the payloads and the error ending the reads.
+func readAllFrames(data []byte, legacy bool) ([][]byte, error) {
+	var payloads [][]byte
+
+	r := bytes.NewReader(data)
+	for {
+		payload, err := readFrame(r, legacy)
+		if err != nil {
+			return payloads, err
+		}
+		payloads = append(payloads, payload)
+	}
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	want := [][]byte{[]byte("first"), {}, []byte("third")}
+
+	var data []byte
+	for _, payload := range want {
+		data = append(data, frame(payload)...)
+	}
+
+	got, err := readAllFrames(data, false)
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("after the last frame: %v, want %v", err, io.EOF)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d frames, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("frame %d = %q, want %q", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestReadFrameChecksum(t *testing.T) {
+	data := append(frame([]byte("first")), frame([]byte("second"))...)
+	data[frameSize]++ // First byte of the first payload.
+
+	r := bytes.NewReader(data)
+	if _, err := readFrame(r, false); !errors.Is(err, errChecksum) || !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("changed payload: %v, want %v", err, errChecksum)
+	}
+
+	// The bad frame was read whole, so the next one can be read.
+	payload, err := readFrame(r, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(payload) != "second" {
+		t.Errorf("frame after the bad one = %q, want %q", payload, "second")
+	}
+}
+
+func TestReadFrameTruncated(t *testing.T) {
+	data := frame([]byte("payload"))
+
+	for _, size := range []int{1, frameSize - 1, frameSize, len(data) - 1} {
+		if _, err := readFrame(bytes.NewReader(data[:size]), false); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("frame truncated to %d bytes: %v, want %v", size, err, ErrCorrupt)
+		}
+	}
+
+	long := make([]byte, frameSize)
+	binary.BigEndian.PutUint32(long, maxFrame+1)
+	if _, err := readFrame(bytes.NewReader(long), false); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("frame length over the maximum: %v, want %v", err, ErrCorrupt)
+	}
+}
+
+func TestReadLegacyFrame(t *testing.T) {
+	// Legacy frames are the payload length and the payload, without a checksum.
+	var data []byte
+	for _, payload := range []string{"first", "second"} {
+		length := make([]byte, 4)
+		binary.BigEndian.PutUint32(length, uint32(len(payload)))
+		data = append(append(data, length...), payload...)
+	}
+
+	got, err := readAllFrames(data, true)
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("after the last frame: %v, want %v", err, io.EOF)
+	}
+	if len(got) != 2 || string(got[0]) != "first" || string(got[1]) != "second" {
+		t.Errorf("legacy frames = %q, want first and second", got)
+	}
+
+	if got, err = readAllFrames(data[:len(data)-1], true); len(got) != 1 || !errors.Is(err, ErrCorrupt) {
+		t.Errorf("truncated legacy frame: %d frames and %v, want 1 and %v", len(got), err, ErrCorrupt)
+	}
+}
@@ -0,0 +1,20 @@
+package persist_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tsilvers/realtime-securities/config"
+	"github.com/tsilvers/realtime-securities/persist"
+	"github.com/tsilvers/realtime-securities/persist/storetest"
+)
+
+func TestGobStoreConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) persist.Store {
+		if err := os.RemoveAll(config.StoreDir()); err != nil {
+			t.Fatal(err)
+		}
+
+		return persist.NewGobStore()
+	})
+}
@@ -0,0 +1,40 @@
+package persist
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lockStore takes an advisory lock on the store directory of the record type, shared by readers or exclusive
+// to a writer, and returns a function releasing it. The lock file is beside the directory, so the lock is
+// kept while the directory is removed and rebuilt. Readers go unlocked if the lock file cannot be opened,
+// as when nothing has been stored yet.
+func lockStore(rt RecordType, exclusive bool) (unlock func(), err error) {
+	fname := strings.TrimSuffix(rt.dir(), string(filepath.Separator)) + ".lock"
+
+	if exclusive {
+		if err := os.MkdirAll(filepath.Dir(fname), 0755); err != nil {
+			return nil, fmt.Errorf("could not lock %s store %s: %w", rt, fname, err)
+		}
+	}
+
+	file, err := os.OpenFile(fname, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		if !exclusive {
+			return func() {}, nil
+		}
+		return nil, fmt.Errorf("could not lock %s store %s: %w", rt, fname, err)
+	}
+
+	if err := lockFile(file, exclusive); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("could not lock %s store %s: %w", rt, fname, err)
+	}
+
+	return func() {
+		_ = unlockFile(file)
+		_ = file.Close()
+	}, nil
+}
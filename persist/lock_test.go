@@ -0,0 +1,131 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package persist
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/config"
+)
+
+// acquire takes the lock in the background, returning a channel closed once it is held and the lock's release.
+func acquire(t *testing.T, exclusive bool) (<-chan struct{}, func() func()) {
+	t.Helper()
+
+	locked := make(chan struct{})
+	var unlock func()
+	var err error
+	go func() {
+		unlock, err = lockStore(PriceRecords, exclusive)
+		close(locked)
+	}()
+
+	return locked, func() func() {
+		<-locked
+		if err != nil {
+			t.Fatal(err)
+		}
+		return unlock
+	}
+}
+
+// held reports whether the lock is taken within a short time.
+func held(locked <-chan struct{}) bool {
+	select {
+	case <-locked:
+		return true
+	case <-time.After(100 * time.Millisecond):
+		return false
+	}
+}
+
+func TestLockStoreExclusive(t *testing.T) {
+	resetStore(t)
+
+	unlock, err := lockStore(PriceRecords, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked, release := acquire(t, false)
+	if held(locked) {
+		t.Error("shared lock taken while the exclusive lock is held")
+	}
+
+	unlock()
+	if !held(locked) {
+		t.Error("shared lock not taken after the exclusive lock was released")
+	}
+	release()()
+}
+
+func TestLockStoreShared(t *testing.T) {
+	resetStore(t)
+
+	unlock, err := lockStore(PriceRecords, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shared, releaseShared := acquire(t, false)
+	if !held(shared) {
+		t.Error("second shared lock not taken while a shared lock is held")
+	}
+
+	exclusive, releaseExclusive := acquire(t, true)
+	if held(exclusive) {
+		t.Error("exclusive lock taken while shared locks are held")
+	}
+
+	unlock()
+	releaseShared()()
+	if !held(exclusive) {
+		t.Error("exclusive lock not taken after the shared locks were released")
+	}
+	releaseExclusive()()
+}
+
+func TestLockStoreFile(t *testing.T) {
+	resetStore(t)
+
+	// Readers of an empty store go unlocked rather than failing.
+	if err := os.RemoveAll(config.StoreDir()); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := lockStore(PriceRecords, false)
+	if err != nil {
+		t.Fatalf("reader of a missing store: %v", err)
+	}
+	unlock()
+
+	// The lock file is beside the prices directory, so it outlives a rebuild of the directory.
+	if err := InitPricesStore(); err != nil {
+		t.Fatal(err)
+	}
+	lockFile := strings.TrimSuffix(pricesDir(), string(os.PathSeparator)) + ".lock"
+	if _, err := os.Stat(lockFile); err != nil {
+		t.Errorf("lock file: %v", err)
+	}
+}
+
+func TestInitPricesStoreLockError(t *testing.T) {
+	resetStore(t)
+	defer resetStore(t)
+
+	// A file in place of the store directory keeps the lock file from being created.
+	if err := os.RemoveAll(config.StoreDir()); err != nil {
+		t.Fatal(err)
+	}
+	writeStoreFile(t, config.StoreDir(), []byte("not a directory"))
+
+	if err := InitPricesStore(); err == nil {
+		t.Error("lock file cannot be created: want error")
+	}
+	if err := InitExpirationsStore(); err == nil {
+		t.Error("lock file cannot be created: want error")
+	}
+}
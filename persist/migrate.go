@@ -0,0 +1,179 @@
+package persist
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsilvers/realtime-securities/config"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Migration upgrades stored records of a record type from one version to the next.
+// Upgrade is called with the gob stream of each frame of a file and returns it re-encoded as the next version.
+// A nil Upgrade leaves the records unchanged, for migrations of the file layout alone.
+type Migration struct {
+	Type        RecordType
+	From        uint16 // Version upgraded from, to version From+1.
+	Description string
+	Upgrade     func(payload []byte) ([]byte, error)
+}
+
+var migrations = make(map[RecordType]map[uint16]Migration)
+
+// RegisterMigration adds a migration to those applied when older files are read, appended to or migrated.
+// It panics if a migration is already registered for the record type and version.
+func RegisterMigration(m Migration) {
+	if migrations[m.Type] == nil {
+		migrations[m.Type] = make(map[uint16]Migration)
+	}
+	if _, ok := migrations[m.Type][m.From]; ok {
+		panic(fmt.Sprintf("migration of %s from version %d registered twice", m.Type, m.From))
+	}
+
+	migrations[m.Type][m.From] = m
+}
+
+func init() {
+	// Legacy files are read as frames of the version 1 records, so only the layout changes.
+	for _, rt := range []RecordType{PriceRecords, ExpirationRecords, ChainSnapshotRecords, OneMinSaleRecords, QuoteSnapshotRecords} {
+		RegisterMigration(Migration{Type: rt, From: 0, Description: "add file header and record checksums"})
+	}
+
+	RegisterMigration(Migration{Type: OneMinSaleRecords, From: 1, Description: "store as compressed chunks", Upgrade: gobToMinuteChunk})
+	RegisterMigration(Migration{Type: MinuteIndexRecords, From: 0, Description: "build index"})
+}
+
+// gobToMinuteChunk converts the gob records of a day's one minute sales to a chunk.
+func gobToMinuteChunk(payload []byte) ([]byte, error) {
+	var sales []stock.OneMinSaleGob
+	dec := gob.NewDecoder(bytes.NewReader(payload))
+	for {
+		var group []stock.OneMinSaleGob
+		if err := dec.Decode(&group); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		sales = append(sales, group...)
+	}
+
+	return encodeMinuteChunk(sales)
+}
+
+// upgradeFrames applies the migrations from the version to the current version of the record type.
+func upgradeFrames(rt RecordType, version uint16, frames [][]byte) ([][]byte, error) {
+	for v := version; v < rt.Version(); v++ {
+		m, ok := migrations[rt][v]
+		if !ok {
+			return nil, fmt.Errorf("no migration of %s from version %d", rt, v)
+		}
+		if m.Upgrade == nil {
+			continue
+		}
+
+		for i := range frames {
+			payload, err := m.Upgrade(frames[i])
+			if err != nil {
+				return nil, fmt.Errorf("could not migrate %s from version %d, record %d: %w", rt, v, i+1, err)
+			}
+			frames[i] = payload
+		}
+	}
+
+	return frames, nil
+}
+
+// FileMigration is the result of migrating a stored file to the current version of its record type.
+type FileMigration struct {
+	File    string // Path in the store directory.
+	Type    RecordType
+	From    uint16
+	To      uint16
+	Records int
+	Err     error
+}
+
+// Steps returns the descriptions of the migrations applied to the file.
+func (fm FileMigration) Steps() []string {
+	var steps []string
+	for v := fm.From; v < fm.To; v++ {
+		if m, ok := migrations[fm.Type][v]; ok {
+			steps = append(steps, m.Description)
+		}
+	}
+
+	return steps
+}
+
+func MigrationHeader() string {
+	return fmt.Sprintln("File                                     Records  Version  Migration")
+}
+
+func (fm FileMigration) String() string {
+	result := strings.Join(fm.Steps(), "; ")
+	if fm.Err != nil {
+		result = "ERROR: " + fm.Err.Error()
+	}
+
+	return fmt.Sprintf("%-40s %7d  %2d -> %d  %s", fm.File, fm.Records, fm.From, fm.To, result)
+}
+
+// Migrate upgrades every file in the gob store that is older than the current version of its record type,
+// replacing each file atomically. With dryRun, files are upgraded in memory and checked but left unchanged.
+// Files already at the current version are not returned. Files that cannot be migrated are returned with
+// an error and left unchanged, and the others are still migrated.
+func Migrate(dryRun bool) ([]FileMigration, error) {
+	var results []FileMigration
+
+	for _, rt := range recordTypes {
+		rtResults, err := migrateType(rt, dryRun)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, rtResults...)
+	}
+
+	indexResults, err := buildMissingMinuteIndexes(dryRun)
+	results = append(results, indexResults...)
+
+	return results, err
+}
+
+func migrateType(rt RecordType, dryRun bool) ([]FileMigration, error) {
+	unlock, err := lockStore(rt, !dryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fnames, err := storeFiles(rt)
+	if err != nil {
+		return nil, fmt.Errorf("could not list %s store: %w", rt, err)
+	}
+
+	var results []FileMigration
+	for _, fname := range fnames {
+		from, records, err := upgradeFile(fname, rt, dryRun)
+		if err == nil && from == rt.Version() {
+			continue
+		}
+
+		results = append(results, FileMigration{File: storePath(fname), Type: rt, From: from, To: rt.Version(), Records: records, Err: err})
+	}
+
+	return results, nil
+}
+
+// storePath returns the path of the file in the store directory.
+func storePath(fname string) string {
+	if path, err := filepath.Rel(config.StoreDir(), fname); err == nil {
+		return path
+	}
+
+	return fname
+}
@@ -0,0 +1,297 @@
+package persist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"hash/crc32"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/quote"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// versionHeader returns the header of a file holding the record type at an older version.
+func versionHeader(rt RecordType, version uint16) []byte {
+	buf := header(rt)
+	binary.BigEndian.PutUint16(buf[4:], version)
+	binary.BigEndian.PutUint32(buf[8:], crc32.ChecksumIEEE(buf[:8]))
+
+	return buf
+}
+
+// gobStream returns the values in a single gob stream, as legacy prices and expirations files were written.
+func gobStream(t *testing.T, values ...interface{}) []byte {
+	t.Helper()
+
+	var stream bytes.Buffer
+	enc := gob.NewEncoder(&stream)
+	for _, value := range values {
+		if err := enc.Encode(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return stream.Bytes()
+}
+
+// legacyFile is what a file written by writeLegacyStore holds.
+type legacyFile struct {
+	rt      RecordType
+	version uint16
+	records int
+}
+
+// legacyStore holds the files written by writeLegacyStore and the records they hold.
+type legacyStore struct {
+	files     map[string]legacyFile
+	prices    []stock.DailyPriceGob
+	exps      []option.ExpirationGob
+	chains    []option.ChainSnapshotGob
+	sales     []stock.OneMinSaleGob
+	snapshots []quote.SnapshotGob
+}
+
+// writeLegacyStore writes a version 0 file of each record type for MSFT, and a version 1 day of one minute sales,
+// which is upgraded from gob records to a chunk. The one minute sales have no index.
+func writeLegacyStore(t *testing.T) legacyStore {
+	t.Helper()
+
+	days := testDays(2)
+	ls := legacyStore{
+		files:  make(map[string]legacyFile),
+		prices: testPrices(4, 5, 6),
+		exps: []option.ExpirationGob{
+			{Date: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC), Strikes: []float64{400, 405}},
+			{Date: time.Date(2024, 3, 22, 12, 0, 0, 0, time.UTC), Strikes: []float64{410}},
+		},
+		sales: append(testSales(1, days[0]), testSales(1, days[1])...),
+	}
+	for i, underlying := range []float64{401.5, 402.5} {
+		ls.chains = append(ls.chains, option.ChainSnapshotGob{
+			Symbol: "MSFT", Time: days[0].Add(time.Duration(15+i) * time.Hour), Underlying: underlying,
+			Expirations: []option.ChainExpirationGob{{Date: ls.exps[0].Date, Strikes: []option.StrikeGob{
+				{Price: 400, Call: &option.OptionGob{Size: 100, Bid: 5, Ask: 5.1, IV: 0.25}},
+			}}},
+		})
+	}
+	for i := 0; i < 3; i++ {
+		received := days[0].Add(14*time.Hour + 30*time.Minute + time.Duration(i)*time.Minute)
+		ls.snapshots = append(ls.snapshots, quote.SnapshotGob{Received: received, Quote: quote.QuoteGob{Symbol: "MSFT", Last: float64(400 + i)}})
+	}
+
+	write := func(fname string, rt RecordType, version uint16, records int, data []byte) {
+		writeStoreFile(t, fname, data)
+		ls.files[fname] = legacyFile{rt: rt, version: version, records: records}
+	}
+
+	var values []interface{}
+	for _, price := range ls.prices {
+		values = append(values, price)
+	}
+	write(pricesDir()+"MSFT", PriceRecords, 0, len(ls.prices), gobStream(t, values...))
+
+	values = nil
+	for _, exp := range ls.exps {
+		values = append(values, exp)
+	}
+	write(optionsDir()+"MSFT", ExpirationRecords, 0, len(ls.exps), gobStream(t, values...))
+
+	write(chainsDir()+"MSFT", ChainSnapshotRecords, 0, len(ls.chains),
+		append(legacyFrame(t, ls.chains[0]), legacyFrame(t, ls.chains[1])...))
+
+	// The first day is legacy frames of groups of sales, the second a version 1 file of checksummed frames.
+	first, second := ls.sales[:stock.SessionMinutes], ls.sales[stock.SessionMinutes:]
+	write(minutesSymbolDir("MSFT")+days[0].Format(dayFileFmt), OneMinSaleRecords, 0, len(first),
+		append(legacyFrame(t, first[:100]), legacyFrame(t, first[100:])...))
+	write(minutesSymbolDir("MSFT")+days[1].Format(dayFileFmt), OneMinSaleRecords, 1, len(second),
+		append(versionHeader(OneMinSaleRecords, 1), frame(gobStream(t, second))...))
+
+	write(quotesSymbolDir("MSFT")+days[0].Format(dayFileFmt), QuoteSnapshotRecords, 0, len(ls.snapshots),
+		append(legacyFrame(t, ls.snapshots[:1]), legacyFrame(t, ls.snapshots[1:])...))
+
+	return ls
+}
+
+// readFiles returns the contents of the files.
+func readFiles(t *testing.T, files map[string]legacyFile) map[string][]byte {
+	t.Helper()
+
+	contents := make(map[string][]byte, len(files))
+	for fname := range files {
+		data, err := os.ReadFile(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[fname] = data
+	}
+
+	return contents
+}
+
+// assertMigrations checks that there is a migration of each file and of the missing index.
+func assertMigrations(t *testing.T, ls legacyStore, results []FileMigration) {
+	t.Helper()
+
+	if len(results) != len(ls.files)+1 {
+		t.Fatalf("%d migrations, want %d: %v", len(results), len(ls.files)+1, results)
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.File, result.Err)
+			continue
+		}
+
+		if result.Type == MinuteIndexRecords {
+			if result.File != storePath(minuteIndexFile("MSFT")) || result.Records != 2 {
+				t.Errorf("index migration = %+v, want %s with 2 days", result, storePath(minuteIndexFile("MSFT")))
+			}
+			continue
+		}
+
+		var fname string
+		for f := range ls.files {
+			if storePath(f) == result.File {
+				fname = f
+			}
+		}
+		if fname == "" {
+			t.Errorf("unexpected migration of %s", result.File)
+			continue
+		}
+
+		want := ls.files[fname]
+		if result.Type != want.rt || result.From != want.version || result.To != want.rt.Version() || result.Records != want.records {
+			t.Errorf("migration = %+v, want %s from version %d to %d with %d records",
+				result, want.rt, want.version, want.rt.Version(), want.records)
+		}
+		if len(result.Steps()) != int(result.To-result.From) {
+			t.Errorf("%s steps %v, want %d", result.File, result.Steps(), result.To-result.From)
+		}
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	resetStore(t)
+	ls := writeLegacyStore(t)
+	before := readFiles(t, ls.files)
+
+	results, err := Migrate(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMigrations(t, ls, results)
+
+	for fname, data := range readFiles(t, ls.files) {
+		if !bytes.Equal(data, before[fname]) {
+			t.Errorf("%s changed by a dry run", storePath(fname))
+		}
+	}
+	if _, err := os.Stat(minuteIndexFile("MSFT")); !os.IsNotExist(err) {
+		t.Errorf("index written by a dry run: %v", err)
+	}
+
+	// The dry run found the same work left to do.
+	if results, err = Migrate(true); err != nil || len(results) != len(ls.files)+1 {
+		t.Errorf("second dry run: %d migrations, %v; want %d", len(results), err, len(ls.files)+1)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	resetStore(t)
+	ls := writeLegacyStore(t)
+
+	results, err := Migrate(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMigrations(t, ls, results)
+
+	// Every file now has the current header.
+	for fname, data := range readFiles(t, ls.files) {
+		rt := ls.files[fname].rt
+		if !bytes.HasPrefix(data, header(rt)) {
+			t.Errorf("%s does not start with the %s header", storePath(fname), rt)
+		}
+	}
+
+	assertPrices(t, "MSFT", ls.prices)
+
+	exps, err := LoadExpirations("MSFT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exps) != len(ls.exps) || !reflect.DeepEqual(*exps[0], ls.exps[0]) || !reflect.DeepEqual(*exps[1], ls.exps[1]) {
+		t.Errorf("expirations = %+v, want %+v", exps, ls.exps)
+	}
+
+	chains, err := LoadChainSnapshots("MSFT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chains) != len(ls.chains) || chains[1].Underlying != ls.chains[1].Underlying || !chains[0].Time.Equal(ls.chains[0].Time) {
+		t.Errorf("chain snapshots = %+v, want %+v", chains, ls.chains)
+	}
+
+	sales, err := LoadOneMinSales("MSFT", time.Time{}, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sales) != len(ls.sales) {
+		t.Fatalf("%d one minute sales, want %d", len(sales), len(ls.sales))
+	}
+	for i := range sales {
+		if *sales[i] != ls.sales[i] {
+			t.Fatalf("sale %d = %+v, want %+v", i, *sales[i], ls.sales[i])
+		}
+	}
+
+	snapshots, err := LoadQuoteSnapshots("MSFT", testDays(1)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLasts(t, "migrated snapshots", snapshots, 400, 401, 402)
+
+	// Nothing is left to migrate.
+	if results, err = Migrate(false); err != nil || len(results) != 0 {
+		t.Errorf("second migration: %v, %v; want none", results, err)
+	}
+}
+
+func TestMigrateLeavesFailedFiles(t *testing.T) {
+	resetStore(t)
+
+	// A legacy chain snapshots file whose only frame is not a gob stream.
+	bad := append([]byte{0, 0, 0, 4}, "junk"...)
+	badFile := chainsDir() + "NFLX"
+	writeStoreFile(t, badFile, bad)
+	goodFile := pricesDir() + "MSFT"
+	writeStoreFile(t, goodFile, gobStream(t, testPrices(4)[0]))
+
+	results, err := Migrate(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("%d migrations, want 2: %v", len(results), results)
+	}
+	for _, result := range results {
+		if (result.Err != nil) != (result.File == storePath(badFile)) {
+			t.Errorf("%s: error %v", result.File, result.Err)
+		}
+	}
+
+	data, err := os.ReadFile(badFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, bad) {
+		t.Error("file that failed to migrate was changed")
+	}
+	assertPrices(t, "MSFT", testPrices(4))
+}
@@ -0,0 +1,384 @@
+package persist
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// One minute sales are stored in chunks, each holding a day's sales of a symbol in columns rather than as gob
+// records. The chunk starts with a prefix describing it, so it can be indexed without decoding the columns:
+//
+//	day     uvarint  days since 1970-01-01
+//	count   uvarint  number of sales
+//	first   uvarint  minute of the day of the first sale
+//	last    uvarint  minute of the day of the last sale
+//	places  5 bytes  decimal places of each price column, or rawPrices
+//
+// followed by the flate compressed columns, each value delta-encoded against the previous sale's:
+//
+//	minutes       count-1 uvarints, minutes since the previous sale
+//	open, close,  count varints each, the change in the price in units of its decimal places, or for
+//	high, low,    rawPrices, count uvarints each, the float64 bits XORed with the previous price's bits
+//	vwap
+//	volume        count varints, the change in volume
+//
+// Each price column is scaled by the fewest decimal places that keep all its prices exact, so chunks are
+// lossless. Columns needing more than maxPlaces, as a computed VWAP may, are stored raw.
+
+// minuteChunkVersion is the version of the OneMinSaleRecords files, which hold chunks rather than gob records.
+// Increment it when changing the chunk layout or the stock.OneMinSaleGob fields, and register a migration.
+const minuteChunkVersion = 2
+
+const (
+	minutesInDay  = 24 * 60
+	minuteColumns = 5 // Price columns: open, close, high, low and VWAP.
+	maxPlaces     = 8
+	rawPrices     = 0xff
+)
+
+// minuteChunkInfo describes a chunk from its prefix.
+type minuteChunkInfo struct {
+	Day   time.Time
+	First time.Time
+	Last  time.Time
+	Count int
+}
+
+// encodeMinuteChunk encodes sales of one day as a chunk, in time order. A minute given more than once is
+// encoded from its last sale.
+func encodeMinuteChunk(sales []stock.OneMinSaleGob) (chunk []byte, err error) {
+	sales = sortSales(sales)
+	if len(sales) == 0 {
+		return nil, errors.New("no one minute sales to encode")
+	}
+
+	day := saleDay(sales[0])
+	minutes := make([]uint64, len(sales))
+	for i, sale := range sales {
+		if !saleDay(sale).Equal(day) {
+			return nil, fmt.Errorf("one minute sales of %s and %s in one chunk", day.Format(dayFileFmt), saleDay(sale).Format(dayFileFmt))
+		}
+		minutes[i] = uint64(sale.Hour*60 + sale.Minute)
+	}
+
+	columns := [minuteColumns][]float64{}
+	for _, sale := range sales {
+		for i, price := range []float64{sale.Open, sale.Close, sale.High, sale.Low, sale.VWAP} {
+			columns[i] = append(columns[i], price)
+		}
+	}
+
+	var prefix columnWriter
+	prefix.uvarint(uint64(day.Unix() / 86400))
+	prefix.uvarint(uint64(len(sales)))
+	prefix.uvarint(minutes[0])
+	prefix.uvarint(minutes[len(minutes)-1])
+
+	var body columnWriter
+	for i := 1; i < len(minutes); i++ {
+		body.uvarint(minutes[i] - minutes[i-1])
+	}
+
+	var places [minuteColumns]byte
+	for i, column := range columns {
+		if p, scaled, ok := scalePrices(column); ok {
+			places[i] = byte(p)
+			prev := int64(0)
+			for _, price := range scaled {
+				body.varint(price - prev)
+				prev = price
+			}
+		} else {
+			places[i] = rawPrices
+			prev := uint64(0)
+			for _, price := range column {
+				bits := math.Float64bits(price)
+				body.uvarint(bits ^ prev)
+				prev = bits
+			}
+		}
+	}
+
+	prev := int64(0)
+	for _, sale := range sales {
+		body.varint(sale.Volume - prev)
+		prev = sale.Volume
+	}
+
+	buf := bytes.NewBuffer(prefix.Bytes())
+	buf.Write(places[:])
+
+	zw, ok := deflaters.Get().(*flate.Writer)
+	if ok {
+		zw.Reset(buf)
+	} else if zw, err = flate.NewWriter(buf, flate.DefaultCompression); err != nil {
+		return nil, err
+	}
+	defer deflaters.Put(zw)
+
+	if _, err := zw.Write(body.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeMinuteChunk decodes a chunk's sales, in time order.
+func decodeMinuteChunk(chunk []byte) ([]stock.OneMinSaleGob, error) {
+	info, places, columns, err := readChunkPrefix(chunk)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := inflate(columns, info.Count)
+	if err != nil {
+		return nil, fmt.Errorf("%w: chunk columns: %v", ErrCorrupt, err)
+	}
+	cr := columnReader{buf: body}
+
+	sales := make([]stock.OneMinSaleGob, info.Count)
+	minute := info.First.Hour()*60 + info.First.Minute()
+	year, month, day := info.Day.Date()
+	for i := range sales {
+		if i > 0 {
+			delta := cr.uvarint()
+			if delta == 0 || delta >= minutesInDay {
+				return nil, fmt.Errorf("%w: chunk minutes out of order", ErrCorrupt)
+			}
+			minute += int(delta)
+		}
+		sales[i] = stock.OneMinSaleGob{Year: year, Month: int(month), Day: day, Hour: minute / 60, Minute: minute % 60}
+	}
+
+	for c := 0; c < minuteColumns; c++ {
+		scaled, bits := int64(0), uint64(0)
+		scale := math.Pow10(int(places[c]))
+		for i := range sales {
+			var price float64
+			if places[c] != rawPrices {
+				scaled += cr.varint()
+				price = float64(scaled) / scale
+			} else {
+				bits ^= cr.uvarint()
+				price = math.Float64frombits(bits)
+			}
+
+			switch c {
+			case 0:
+				sales[i].Open = price
+			case 1:
+				sales[i].Close = price
+			case 2:
+				sales[i].High = price
+			case 3:
+				sales[i].Low = price
+			default:
+				sales[i].VWAP = price
+			}
+		}
+	}
+
+	volume := int64(0)
+	for i := range sales {
+		volume += cr.varint()
+		sales[i].Volume = volume
+	}
+
+	if cr.err != nil || len(cr.rest()) > 0 || minute != info.Last.Hour()*60+info.Last.Minute() {
+		return nil, fmt.Errorf("%w: chunk columns do not match its prefix", ErrCorrupt)
+	}
+
+	return sales, nil
+}
+
+// readMinuteChunkInfo reads a chunk's prefix, without decoding its columns.
+func readMinuteChunkInfo(chunk []byte) (minuteChunkInfo, error) {
+	info, _, _, err := readChunkPrefix(chunk)
+
+	return info, err
+}
+
+func readChunkPrefix(chunk []byte) (info minuteChunkInfo, places [minuteColumns]byte, columns []byte, err error) {
+	cr := columnReader{buf: chunk}
+	days := cr.uvarint()
+	count := cr.uvarint()
+	first := cr.uvarint()
+	last := cr.uvarint()
+	for i := range places {
+		places[i] = cr.byte()
+		if places[i] > maxPlaces && places[i] != rawPrices {
+			cr.err = errors.New("invalid decimal places")
+		}
+	}
+
+	if cr.err != nil || count == 0 || count > minutesInDay || first > last || last >= minutesInDay || days > math.MaxInt32 {
+		return info, places, nil, fmt.Errorf("%w: invalid chunk prefix", ErrCorrupt)
+	}
+
+	info.Day = time.Unix(int64(days)*86400, 0).UTC()
+	info.First = info.Day.Add(time.Duration(first) * time.Minute)
+	info.Last = info.Day.Add(time.Duration(last) * time.Minute)
+	info.Count = int(count)
+
+	return info, places, cr.rest(), nil
+}
+
+// sortSales returns the sales in time order, keeping the last sale given for each minute.
+func sortSales(sales []stock.OneMinSaleGob) []stock.OneMinSaleGob {
+	sorted := true
+	for i := 1; i < len(sales) && sorted; i++ {
+		sorted = saleMinute(sales[i-1]) < saleMinute(sales[i])
+	}
+	if sorted {
+		return sales
+	}
+
+	ordered := make([]stock.OneMinSaleGob, len(sales))
+	copy(ordered, sales)
+	sort.SliceStable(ordered, func(i, j int) bool { return saleMinute(ordered[i]) < saleMinute(ordered[j]) })
+
+	unique := ordered[:0]
+	for i, sale := range ordered {
+		if i+1 < len(ordered) && saleMinute(ordered[i+1]) == saleMinute(sale) {
+			continue
+		}
+		unique = append(unique, sale)
+	}
+
+	return unique
+}
+
+// saleMinute returns a number ordering sales by time, cheaper to compute than their start times.
+func saleMinute(sale stock.OneMinSaleGob) int {
+	return (((sale.Year*13+sale.Month)*32+sale.Day)*24+sale.Hour)*60 + sale.Minute
+}
+
+func saleDay(sale stock.OneMinSaleGob) time.Time {
+	return time.Date(sale.Year, time.Month(sale.Month), sale.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// scalePrices returns the fewest decimal places, up to maxPlaces, that keep all prices exact as integers,
+// and the prices in those units.
+func scalePrices(prices []float64) (places int, scaled []int64, ok bool) {
+	scaled = make([]int64, len(prices))
+	for places = 0; places <= maxPlaces; places++ {
+		scale := math.Pow10(places)
+		ok = true
+		for i, price := range prices {
+			n := math.Round(price * scale)
+			if math.Abs(n) > 1<<53 || n/scale != price {
+				ok = false
+				break
+			}
+			scaled[i] = int64(n)
+		}
+		if ok {
+			return places, scaled, true
+		}
+	}
+
+	return 0, nil, false
+}
+
+type columnWriter struct {
+	bytes.Buffer
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (w *columnWriter) uvarint(u uint64) {
+	w.Write(w.buf[:binary.PutUvarint(w.buf[:], u)])
+}
+
+func (w *columnWriter) varint(i int64) {
+	w.Write(w.buf[:binary.PutVarint(w.buf[:], i)])
+}
+
+// columnReader reads varints, keeping the first error and returning zeros after it.
+type columnReader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (r *columnReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	u, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		r.err = errors.New("invalid varint")
+		return 0
+	}
+	r.pos += n
+
+	return u
+}
+
+func (r *columnReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	i, n := binary.Varint(r.buf[r.pos:])
+	if n <= 0 {
+		r.err = errors.New("invalid varint")
+		return 0
+	}
+	r.pos += n
+
+	return i
+}
+
+func (r *columnReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos >= len(r.buf) {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	r.pos++
+
+	return r.buf[r.pos-1]
+}
+
+// rest returns the bytes not yet read.
+func (r *columnReader) rest() []byte {
+	return r.buf[r.pos:]
+}
+
+// Flate writers and readers are kept for reuse, since each allocates large buffers.
+var deflaters, inflaters sync.Pool
+
+// inflate decompresses the columns of a chunk of count sales.
+func inflate(columns []byte, count int) ([]byte, error) {
+	zr, ok := inflaters.Get().(io.ReadCloser)
+	if ok {
+		if err := zr.(flate.Resetter).Reset(bytes.NewReader(columns), nil); err != nil {
+			return nil, err
+		}
+	} else {
+		zr = flate.NewReader(bytes.NewReader(columns))
+	}
+	defer inflaters.Put(zr)
+
+	// Sales take about a dozen bytes uncompressed.
+	body := bytes.NewBuffer(make([]byte, 0, 16*count))
+	if _, err := body.ReadFrom(zr); err != nil {
+		return nil, err
+	}
+
+	return body.Bytes(), nil
+}
@@ -0,0 +1,407 @@
+package persist
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/config"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+func roundTrip(t *testing.T, sales []stock.OneMinSaleGob) ([]stock.OneMinSaleGob, [minuteColumns]byte) {
+	t.Helper()
+
+	chunk, err := encodeMinuteChunk(sales)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, places, _, err := readChunkPrefix(chunk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := decodeMinuteChunk(chunk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return decoded, places
+}
+
+func TestMinuteChunkScaledColumns(t *testing.T) {
+	sales := testSales(1, testDays(1)[0])
+
+	decoded, places := roundTrip(t, sales)
+	if !reflect.DeepEqual(decoded, sales) {
+		t.Error("decoded sales differ from the encoded sales")
+	}
+
+	// Prices are in cents and the VWAP has at most six decimal places.
+	for c, p := range places[:4] {
+		if p > 2 {
+			t.Errorf("price column %d scaled by %d places, want at most 2", c, p)
+		}
+	}
+	if places[4] == rawPrices || places[4] > 6 {
+		t.Errorf("VWAP column scaled by %d places, want at most 6", places[4])
+	}
+}
+
+func TestMinuteChunkRawPrices(t *testing.T) {
+	sales := testSales(2, testDays(1)[0])[:30]
+	for i := range sales {
+		sales[i].VWAP = (sales[i].High + sales[i].Low + sales[i].Close) / 3
+	}
+	sales[10].VWAP = 1.0 / 3
+
+	decoded, places := roundTrip(t, sales)
+	if places[4] != rawPrices {
+		t.Errorf("VWAP column scaled by %d places, want raw prices", places[4])
+	}
+	if !reflect.DeepEqual(decoded, sales) {
+		t.Error("decoded sales differ from the encoded sales")
+	}
+}
+
+// Sales given out of order are encoded in time order, keeping the last sale given for a minute.
+func TestMinuteChunkDuplicateMinutes(t *testing.T) {
+	sales := testSales(3, testDays(1)[0])[:10]
+
+	replacement := sales[4]
+	replacement.Close += 0.5
+	replacement.Volume += 1000
+
+	given := append([]stock.OneMinSaleGob{sales[7], sales[4]}, sales...)
+	given = append(given, replacement)
+
+	want := append([]stock.OneMinSaleGob{}, sales...)
+	want[4] = replacement
+
+	decoded, _ := roundTrip(t, given)
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("decoded %d sales, want %d in time order with the replacement of %02d:%02d",
+			len(decoded), len(want), replacement.Hour, replacement.Minute)
+	}
+}
+
+func TestMinuteChunkOneDay(t *testing.T) {
+	days := testDays(2)
+	sales := append(testSales(4, days[0])[:5], testSales(4, days[1])[:5]...)
+
+	if _, err := encodeMinuteChunk(sales); err == nil {
+		t.Error("encoded sales of two days in one chunk, want an error")
+	}
+	if _, err := encodeMinuteChunk(nil); err == nil {
+		t.Error("encoded a chunk without sales, want an error")
+	}
+}
+
+func TestMinuteChunkCorrupt(t *testing.T) {
+	sales := testSales(5, testDays(1)[0])
+	chunk, err := encodeMinuteChunk(sales)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The prefix of a session starting at 9:30 is the day, the count (2 bytes), and the first (2 bytes)
+	// and last minutes (2 bytes), followed by the decimal places of each column.
+	prefix := len(chunk) - len(chunkColumns(t, chunk))
+	tests := []struct {
+		name  string
+		chunk func() []byte
+	}{
+		{"empty", func() []byte { return nil }},
+		{"truncated prefix", func() []byte { return chunk[:3] }},
+		{"no columns", func() []byte { return chunk[:prefix] }},
+		{"invalid decimal places", func() []byte { c := clone(chunk); c[prefix-1] = maxPlaces + 1; return c }},
+		{"count larger than the columns", func() []byte { return withCount(t, chunk, len(sales)+1) }},
+		{"count smaller than the columns", func() []byte { return withCount(t, chunk, len(sales)-1) }},
+		{"zero count", func() []byte { return withCount(t, chunk, 0) }},
+		{"truncated columns", func() []byte { return chunk[:len(chunk)-10] }},
+		{"damaged columns", func() []byte { c := clone(chunk); c[prefix+20] ^= 0xff; return c }},
+	}
+
+	for _, tc := range tests {
+		if _, err := decodeMinuteChunk(tc.chunk()); err == nil {
+			t.Errorf("%s: decoded, want an error", tc.name)
+		} else if tc.name != "damaged columns" && !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s: error %v is not ErrCorrupt", tc.name, err)
+		}
+	}
+}
+
+// chunkColumns returns the compressed columns of a chunk.
+func chunkColumns(t *testing.T, chunk []byte) []byte {
+	t.Helper()
+
+	_, _, columns, err := readChunkPrefix(chunk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return columns
+}
+
+// withCount returns the chunk with the count in its prefix changed.
+func withCount(t *testing.T, chunk []byte, count int) []byte {
+	t.Helper()
+
+	cr := columnReader{buf: chunk}
+	days := cr.uvarint()
+	cr.uvarint()
+	rest := cr.rest()
+
+	var w columnWriter
+	w.uvarint(days)
+	w.uvarint(uint64(count))
+
+	return append(w.Bytes(), rest...)
+}
+
+func clone(b []byte) []byte {
+	return append([]byte{}, b...)
+}
+
+func TestSaveOneMinSalesMerges(t *testing.T) {
+	resetStore(t)
+
+	days := testDays(3)
+	var sales []stock.OneMinSaleGob
+	for _, day := range days {
+		sales = append(sales, testSales(6, day)...)
+	}
+
+	// Save the first two days and half of the third, then the rest of the third day
+	// with its first minute again, as a retrieval during the session would.
+	half := 2*stock.SessionMinutes + stock.SessionMinutes/2
+	if err := SaveOneMinSales("TEST", sales[:half]); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveOneMinSales("TEST", sales[half-1:]); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadOneMinSales("TEST", time.Time{}, days[2].Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(sales) {
+		t.Fatalf("loaded %d sales, want %d", len(loaded), len(sales))
+	}
+	for i := range loaded {
+		if *loaded[i] != sales[i] {
+			t.Fatalf("sale %d = %+v, want %+v", i, *loaded[i], sales[i])
+		}
+	}
+
+	day, err := LoadOneMinSales("TEST", days[1], days[1].Add(24*time.Hour-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(day) != stock.SessionMinutes || *day[0] != sales[stock.SessionMinutes] {
+		t.Errorf("loaded %d sales of %s, want the %d of the session", len(day), days[1].Format(dayFileFmt), stock.SessionMinutes)
+	}
+
+	last, err := LastOneMinSaleTime("TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := sales[len(sales)-1].StartTime(); !last.Equal(want) {
+		t.Errorf("last sale time = %s, want %s", last, want)
+	}
+}
+
+// benchSymbols and benchSessions are the size of the stores written by the benchmarks.
+const (
+	benchSymbols  = 5
+	benchSessions = 20
+)
+
+// benchGobDir returns the directory of the benchmarks' gob records.
+func benchGobDir(symbol string) string {
+	return filepath.Join(config.StoreDir(), "gob", symbol) + string(filepath.Separator)
+}
+
+// appendMinuteRecords stores each day's sales as framed gob records, as one minute sales were stored before
+// chunks, with a record appended to the day's file for each save. The quote snapshot record type is used since
+// it is also appended to a file per day.
+func appendMinuteRecords(symbol string, days []time.Time, sales func(day time.Time) []stock.OneMinSaleGob) error {
+	dir := benchGobDir(symbol)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		if err := appendRecord(dir+day.Format(dayFileFmt), QuoteSnapshotRecords, sales(day)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func loadMinuteRecords(symbol string, days []time.Time) (int, error) {
+	cnt := 0
+	for _, day := range days {
+		err := readRecords(benchGobDir(symbol)+day.Format(dayFileFmt), QuoteSnapshotRecords, func(dec *gob.Decoder) error {
+			var sales []stock.OneMinSaleGob
+			if err := dec.Decode(&sales); err != nil {
+				return err
+			}
+			cnt += len(sales)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return cnt, nil
+}
+
+func saveMinuteChunks(symbol string, days []time.Time, sales func(day time.Time) []stock.OneMinSaleGob) error {
+	var all []stock.OneMinSaleGob
+	for _, day := range days {
+		all = append(all, sales(day)...)
+	}
+
+	return SaveOneMinSales(symbol, all)
+}
+
+func loadMinuteChunks(symbol string, days []time.Time) (int, error) {
+	sales, err := LoadOneMinSales(symbol, days[0], days[len(days)-1].Add(24*time.Hour-time.Minute))
+
+	return len(sales), err
+}
+
+// dirSize returns the total size of the files in the directory.
+func dirSize(tb testing.TB, dir string) int64 {
+	tb.Helper()
+
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err == nil {
+			size += info.Size()
+		}
+		return err
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return size
+}
+
+type saveFunc func(symbol string, days []time.Time, sales func(day time.Time) []stock.OneMinSaleGob) error
+
+type loadFunc func(symbol string, days []time.Time) (int, error)
+
+// benchmarkWrite stores sessions of sales for each symbol, reporting the stored size per sale.
+func benchmarkWrite(b *testing.B, save saveFunc, dir func() string) {
+	days := testDays(benchSessions)
+	sessions := make(map[time.Time][]stock.OneMinSaleGob)
+	for _, day := range days {
+		sessions[day] = testSales(0, day)
+	}
+	sales := func(day time.Time) []stock.OneMinSaleGob { return sessions[day] }
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		resetStore(b)
+		b.StartTimer()
+
+		for s := 0; s < benchSymbols; s++ {
+			if err := save(fmt.Sprintf("SYM%d", s), days, sales); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.ReportMetric(float64(dirSize(b, dir()))/float64(benchSymbols*benchSessions*stock.SessionMinutes), "B/sale")
+}
+
+// benchmarkLoad loads the last sessions of each symbol.
+func benchmarkLoad(b *testing.B, save saveFunc, load loadFunc, sessions int) {
+	resetStore(b)
+
+	days := testDays(benchSessions)
+	for s := 0; s < benchSymbols; s++ {
+		seed := s
+		if err := save(fmt.Sprintf("SYM%d", s), days, func(day time.Time) []stock.OneMinSaleGob { return testSales(seed, day) }); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for s := 0; s < benchSymbols; s++ {
+			cnt, err := load(fmt.Sprintf("SYM%d", s), days[len(days)-sessions:])
+			if err != nil {
+				b.Fatal(err)
+			}
+			if cnt != sessions*stock.SessionMinutes {
+				b.Fatalf("loaded %d sales, want %d", cnt, sessions*stock.SessionMinutes)
+			}
+		}
+	}
+}
+
+func BenchmarkWriteMinuteChunks(b *testing.B) {
+	benchmarkWrite(b, saveMinuteChunks, minutesDir)
+}
+
+func BenchmarkWriteMinuteRecords(b *testing.B) {
+	benchmarkWrite(b, appendMinuteRecords, func() string { return filepath.Join(config.StoreDir(), "gob") })
+}
+
+func BenchmarkLoadMinuteChunks(b *testing.B) {
+	benchmarkLoad(b, saveMinuteChunks, loadMinuteChunks, benchSessions)
+}
+
+func BenchmarkLoadMinuteRecords(b *testing.B) {
+	benchmarkLoad(b, appendMinuteRecords, loadMinuteRecords, benchSessions)
+}
+
+func BenchmarkLoadMinuteChunksDay(b *testing.B) {
+	benchmarkLoad(b, saveMinuteChunks, loadMinuteChunks, 1)
+}
+
+func BenchmarkLoadMinuteRecordsDay(b *testing.B) {
+	benchmarkLoad(b, appendMinuteRecords, loadMinuteRecords, 1)
+}
+
+func BenchmarkEncodeMinuteChunk(b *testing.B) {
+	sales := testSales(0, testDays(1)[0])
+
+	for i := 0; i < b.N; i++ {
+		if _, err := encodeMinuteChunk(sales); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeMinuteChunk(b *testing.B) {
+	chunk, err := encodeMinuteChunk(testSales(0, testDays(1)[0]))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeMinuteChunk(chunk); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package persist
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Each symbol's one minute sales have an index listing the chunk of each day in date order, with its first and
+// last minutes, so a range load reads only the chunks it needs without listing or opening the others.
+// The index is replaced after the day files on each save. If a save fails between them, the index lacks
+// the latest sales until the day is saved again, as it is by the next retrieval of newer sales.
+
+// minuteIndexVersion is the version of the MinuteIndexRecords files.
+const minuteIndexVersion = 1
+
+// minuteIndexName is the name of the index file in a symbol's directory of one minute sales.
+const minuteIndexName = "index"
+
+type minuteIndex []minuteChunkInfo
+
+func minuteIndexFile(symbol string) string {
+	return minutesSymbolDir(symbol) + minuteIndexName
+}
+
+// loadMinuteIndex loads the symbol's index, building it from the day files if there is none.
+// No chunks are returned if no one minute sales have been stored.
+func loadMinuteIndex(symbol string) (minuteIndex, error) {
+	var index minuteIndex
+
+	fname := minuteIndexFile(symbol)
+	err := readRecords(fname, MinuteIndexRecords, func(dec *gob.Decoder) error {
+		return dec.Decode(&index)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return buildMinuteIndex(symbol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not load one minute sales index for %s from file %s: %w", symbol, fname, err)
+	}
+
+	return index, nil
+}
+
+// buildMinuteIndex builds the symbol's index by reading its day files.
+func buildMinuteIndex(symbol string) (minuteIndex, error) {
+	days, err := listDays(minutesSymbolDir(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("could not index one minute sales for %s: %w", symbol, err)
+	}
+
+	var index minuteIndex
+	for _, day := range days {
+		sales, err := loadOneMinSaleDay(symbol, day)
+		if err != nil {
+			return nil, err
+		}
+		if len(sales) > 0 {
+			index = append(index, minuteChunkInfo{
+				Day:   day,
+				First: sales[0].StartTime(),
+				Last:  sales[len(sales)-1].StartTime(),
+				Count: len(sales),
+			})
+		}
+	}
+
+	return index, nil
+}
+
+func saveMinuteIndex(symbol string, index minuteIndex) error {
+	fname := minuteIndexFile(symbol)
+	err := writeRecords(fname, MinuteIndexRecords, func(enc *gob.Encoder) error {
+		return enc.Encode(index)
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist one minute sales index for %s to file %s: %w", symbol, fname, err)
+	}
+
+	return nil
+}
+
+// with returns the index with the chunk replacing any of the same day, in date order.
+func (mi minuteIndex) with(info minuteChunkInfo) minuteIndex {
+	i := sort.Search(len(mi), func(i int) bool { return !mi[i].Day.Before(info.Day) })
+	if i < len(mi) && mi[i].Day.Equal(info.Day) {
+		mi[i] = info
+		return mi
+	}
+
+	mi = append(mi, minuteChunkInfo{})
+	copy(mi[i+1:], mi[i:])
+	mi[i] = info
+
+	return mi
+}
+
+// overlapping returns the chunks holding sales from start through end.
+func (mi minuteIndex) overlapping(start, end time.Time) minuteIndex {
+	var chunks minuteIndex
+	for _, info := range mi {
+		if !info.Last.Before(start) && !info.First.After(end) {
+			chunks = append(chunks, info)
+		}
+	}
+
+	return chunks
+}
+
+// buildMissingMinuteIndexes builds the indexes of symbols whose one minute sales have none,
+// as in stores written before the sales were indexed.
+func buildMissingMinuteIndexes(dryRun bool) ([]FileMigration, error) {
+	unlock, err := lockStore(OneMinSaleRecords, !dryRun)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := os.ReadDir(minutesDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list %s store: %w", OneMinSaleRecords, err)
+	}
+
+	var results []FileMigration
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		// The directory name is the symbol's file name, which loads the same files as the symbol.
+		symbol := entry.Name()
+		fname := minuteIndexFile(symbol)
+		if _, err := os.Stat(fname); err == nil {
+			continue
+		}
+
+		index, err := buildMinuteIndex(symbol)
+		if err == nil && !dryRun {
+			err = saveMinuteIndex(symbol, index)
+		}
+		results = append(results, FileMigration{File: storePath(fname), Type: MinuteIndexRecords, From: 0,
+			To: MinuteIndexRecords.Version(), Records: len(index), Err: err})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,188 @@
+package persist
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// One minute sales are stored in a directory for each symbol, with a file for each trading day (see listDays)
+// holding the day's sales as a compressed chunk (see minutechunk.go), and an index of the days (see minuteindex.go).
+// Each save merges the new sales into the stored sales of their days, replacing any stored sale of the same
+// minute, and replaces the day files.
+
+func minutesSymbolDir(symbol string) string {
+	return minutesDir() + stockFilename(symbol) + "/"
+}
+
+// SaveOneMinSales merges one minute sales into the symbol's store, replacing stored sales of the same minutes.
+func SaveOneMinSales(symbol string, sales []stock.OneMinSaleGob) error {
+	unlock, err := lockStore(OneMinSaleRecords, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	dir := minutesSymbolDir(symbol)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not persist one minute sales for %s to %s: %w", symbol, dir, err)
+	}
+
+	byDay := make(map[time.Time][]stock.OneMinSaleGob)
+	var days []time.Time
+	for _, sale := range sales {
+		day := saleDay(sale)
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], sale)
+	}
+
+	index, err := loadMinuteIndex(symbol)
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		stored, err := loadOneMinSaleDay(symbol, day)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		merged := make([]stock.OneMinSaleGob, 0, len(stored)+len(byDay[day]))
+		for _, sale := range stored {
+			merged = append(merged, *sale)
+		}
+		merged = append(merged, byDay[day]...)
+
+		fname := dir + day.Format(dayFileFmt)
+		chunk, err := encodeMinuteChunk(merged)
+		if err == nil {
+			err = writeFrames(fname, OneMinSaleRecords, [][]byte{chunk})
+		}
+		if err != nil {
+			return fmt.Errorf("could not persist one minute sales for %s to file %s: %w", symbol, fname, err)
+		}
+
+		info, err := readMinuteChunkInfo(chunk)
+		if err != nil {
+			return err
+		}
+		index = index.with(info)
+	}
+
+	return saveMinuteIndex(symbol, index)
+}
+
+// LoadOneMinSales loads the symbol's stored one minute sales from start through end, in time order.
+// No sales are returned if none have been stored. Only the days holding sales in the range are read.
+func LoadOneMinSales(symbol string, start, end time.Time) ([]*stock.OneMinSaleGob, error) {
+	unlock, err := lockStore(OneMinSaleRecords, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	index, err := loadMinuteIndex(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := index.overlapping(start, end)
+	count := 0
+	for _, info := range chunks {
+		count += info.Count
+	}
+
+	sales := make([]*stock.OneMinSaleGob, 0, count)
+	for _, info := range chunks {
+		daySales, err := loadOneMinSaleDay(symbol, info.Day)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.First.Before(start) && !info.Last.After(end) {
+			sales = append(sales, daySales...)
+			continue
+		}
+		for _, sale := range daySales {
+			t := sale.StartTime()
+			if !t.Before(start) && !t.After(end) {
+				sales = append(sales, sale)
+			}
+		}
+	}
+
+	return sales, nil
+}
+
+// OneMinSaleDates returns the days with stored one minute sales for the symbol, in date order.
+func OneMinSaleDates(symbol string) ([]time.Time, error) {
+	unlock, err := lockStore(OneMinSaleRecords, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	index, err := loadMinuteIndex(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]time.Time, len(index))
+	for i, info := range index {
+		days[i] = info.Day
+	}
+
+	return days, nil
+}
+
+// LastOneMinSaleTime returns the time of the symbol's latest stored one minute sale, or the zero time if none are stored.
+func LastOneMinSaleTime(symbol string) (time.Time, error) {
+	unlock, err := lockStore(OneMinSaleRecords, false)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer unlock()
+
+	index, err := loadMinuteIndex(symbol)
+	if err != nil || len(index) == 0 {
+		return time.Time{}, err
+	}
+
+	return index[len(index)-1].Last, nil
+}
+
+// loadOneMinSaleDay loads a day's stored sales in time order. Files holding more than one chunk,
+// as those of earlier versions may, keep the sale of each minute from the latest chunk.
+func loadOneMinSaleDay(symbol string, day time.Time) ([]*stock.OneMinSaleGob, error) {
+	fname := minutesSymbolDir(symbol) + day.Format(dayFileFmt)
+
+	chunks, err := readPayloads(fname, OneMinSaleRecords)
+	if err != nil {
+		return nil, fmt.Errorf("could not load one minute sales for %s from file %s: %w", symbol, fname, err)
+	}
+
+	var all []stock.OneMinSaleGob
+	for i, chunk := range chunks {
+		chunkSales, err := decodeMinuteChunk(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("could not load one minute sales for %s from file %s: record %d: %w", symbol, fname, i+1, err)
+		}
+		all = append(all, chunkSales...)
+	}
+
+	if len(chunks) > 1 {
+		all = sortSales(all)
+	}
+	sales := make([]*stock.OneMinSaleGob, len(all))
+	for i := range all {
+		sales[i] = &all[i]
+	}
+
+	return sales, nil
+}
@@ -2,32 +2,47 @@ package persist
 
 import (
 	"encoding/gob"
-	"errors"
 	"fmt"
 	"github.com/tsilvers/realtime-securities/markets/option"
-	"io"
 	"os"
 )
 
-func InitExpirationsStore() {
-	_ = os.RemoveAll(optionsDir)
-	_ = os.MkdirAll(optionsDir, 0755)
+// InitExpirationsStore removes all stored option expirations.
+func InitExpirationsStore() error {
+	unlock, err := lockStore(ExpirationRecords, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.RemoveAll(optionsDir()); err != nil {
+		return fmt.Errorf("could not remove option expirations store %s: %w", optionsDir(), err)
+	}
+	if err := os.MkdirAll(optionsDir(), 0755); err != nil {
+		return fmt.Errorf("could not create option expirations store %s: %w", optionsDir(), err)
+	}
+
+	return nil
 }
 
 func SaveExpirations(symbol string, exps []option.ExpirationGob) error {
-	fname := optionsDir + stockFilename(symbol)
-	file, err := os.Create(fname)
+	unlock, err := lockStore(ExpirationRecords, true)
 	if err != nil {
-		return fmt.Errorf("could not persist option expirations for %s to file %s: %w", symbol, fname, err)
+		return err
 	}
-	defer file.Close()
+	defer unlock()
 
-	enc := gob.NewEncoder(file)
-
-	for _, exp := range exps {
-		if err := enc.Encode(exp); err != nil {
-			return fmt.Errorf("could not persist option expirations for %s to file %s: %w", symbol, fname, err)
+	fname := optionsDir() + stockFilename(symbol)
+	err = writeRecords(fname, ExpirationRecords, func(enc *gob.Encoder) error {
+		for _, exp := range exps {
+			if err := enc.Encode(exp); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist option expirations for %s to file %s: %w", symbol, fname, err)
 	}
 
 	return nil
@@ -36,23 +51,22 @@ func SaveExpirations(symbol string, exps []option.ExpirationGob) error {
 func LoadExpirations(symbol string) ([]*option.ExpirationGob, error) {
 	var exps []*option.ExpirationGob
 
-	fname := optionsDir + stockFilename(symbol)
-	file, err := os.Open(fname)
+	unlock, err := lockStore(ExpirationRecords, false)
 	if err != nil {
-		return exps, fmt.Errorf("could not load option expirations for %s from file %s: %w", symbol, fname, err)
+		return nil, err
 	}
-	defer file.Close()
-
-	enc := gob.NewDecoder(file)
+	defer unlock()
 
-	for err == nil {
+	fname := optionsDir() + stockFilename(symbol)
+	err = readRecords(fname, ExpirationRecords, func(dec *gob.Decoder) error {
 		exp := &option.ExpirationGob{}
-		if err = enc.Decode(exp); err == nil {
-			exps = append(exps, exp)
+		if err := dec.Decode(exp); err != nil {
+			return err
 		}
-	}
-
-	if !errors.Is(io.EOF, err) {
+		exps = append(exps, exp)
+		return nil
+	})
+	if err != nil {
 		return nil, fmt.Errorf("could not load option expirations for %s from file %s: %w", symbol, fname, err)
 	}
 
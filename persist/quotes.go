@@ -0,0 +1,98 @@
+package persist
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/quote"
+)
+
+// Quote snapshots are stored in a directory for each symbol, with a file for each day they were received
+// (see listDays). Each save appends one record to a day's file, so the file is a log of every snapshot.
+
+func quotesSymbolDir(symbol string) string {
+	return quotesDir() + stockFilename(symbol) + "/"
+}
+
+// SaveQuoteSnapshots appends quote snapshots to the logs of their symbols for the days they were received.
+func SaveQuoteSnapshots(snapshots []quote.SnapshotGob) error {
+	unlock, err := lockStore(QuoteSnapshotRecords, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	type key struct {
+		symbol string
+		day    string
+	}
+
+	groups := make(map[key][]quote.SnapshotGob)
+	var keys []key
+	for _, snapshot := range snapshots {
+		k := key{symbol: snapshot.Quote.Symbol, day: markets.MarketTime(snapshot.Received).Format(dayFileFmt)}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], snapshot)
+	}
+
+	for _, k := range keys {
+		dir := quotesSymbolDir(k.symbol)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("could not persist quote snapshots for %s to %s: %w", k.symbol, dir, err)
+		}
+
+		fname := dir + k.day
+		if err := appendRecord(fname, QuoteSnapshotRecords, groups[k]); err != nil {
+			return fmt.Errorf("could not persist quote snapshots for %s to file %s: %w", k.symbol, fname, err)
+		}
+	}
+
+	return nil
+}
+
+// LoadQuoteSnapshots loads the symbol's quote snapshots received on the day, in the order they were received.
+func LoadQuoteSnapshots(symbol string, day time.Time) ([]*quote.SnapshotGob, error) {
+	var snapshots []*quote.SnapshotGob
+
+	unlock, err := lockStore(QuoteSnapshotRecords, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fname := quotesSymbolDir(symbol) + day.Format(dayFileFmt)
+	err = readRecords(fname, QuoteSnapshotRecords, func(dec *gob.Decoder) error {
+		var group []quote.SnapshotGob
+		if err := dec.Decode(&group); err != nil {
+			return err
+		}
+		for i := range group {
+			snapshots = append(snapshots, &group[i])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load quote snapshots for %s from file %s: %w", symbol, fname, err)
+	}
+
+	// Concurrent recorders may append out of order.
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Received.Before(snapshots[j].Received) })
+
+	return snapshots, nil
+}
+
+// QuoteSnapshotDates returns the days with stored quote snapshots for the symbol, in date order.
+func QuoteSnapshotDates(symbol string) ([]time.Time, error) {
+	days, err := listDays(quotesSymbolDir(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("could not load quote snapshots for %s: %w", symbol, err)
+	}
+
+	return days, nil
+}
@@ -0,0 +1,119 @@
+package persist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/quote"
+)
+
+// testSnapshot returns a snapshot of the symbol received at the time, identified by its last price.
+func testSnapshot(symbol string, received time.Time, last float64) quote.SnapshotGob {
+	return quote.SnapshotGob{
+		Received: received,
+		Quote:    quote.QuoteGob{Symbol: symbol, TradeDate: received.Add(-time.Second), Last: last, Bid: last - 0.01, Ask: last + 0.01},
+	}
+}
+
+// assertLasts checks the last prices of the snapshots, in order.
+func assertLasts(t *testing.T, name string, snapshots []*quote.SnapshotGob, want ...float64) {
+	t.Helper()
+
+	if len(snapshots) != len(want) {
+		t.Fatalf("%s: %d snapshots, want %d", name, len(snapshots), len(want))
+	}
+	for i, s := range snapshots {
+		if s.Quote.Last != want[i] {
+			got := make([]float64, 0, len(snapshots))
+			for _, s := range snapshots {
+				got = append(got, s.Quote.Last)
+			}
+			t.Fatalf("%s: last prices %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestQuoteSnapshotsReceiveOrder(t *testing.T) {
+	resetStore(t)
+
+	open := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC) // 9:30 in New York.
+	saves := [][]quote.SnapshotGob{
+		{testSnapshot("MSFT", open, 1), testSnapshot("NFLX", open, 500), testSnapshot("MSFT", open.Add(2*time.Minute), 3)},
+		// Another recorder's earlier snapshot appended after a later one.
+		{testSnapshot("MSFT", open.Add(time.Minute), 2)},
+		// Snapshots received together keep the order they were saved in.
+		{testSnapshot("MSFT", open.Add(3*time.Minute), 4), testSnapshot("MSFT", open.Add(3*time.Minute), 5)},
+	}
+	for _, snapshots := range saves {
+		if err := SaveQuoteSnapshots(snapshots); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	msft, err := LoadQuoteSnapshots("MSFT", day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLasts(t, "MSFT", msft, 1, 2, 3, 4, 5)
+	for i := 1; i < len(msft); i++ {
+		if msft[i].Received.Before(msft[i-1].Received) {
+			t.Errorf("snapshot %d received at %s, before %s", i, msft[i].Received, msft[i-1].Received)
+		}
+	}
+
+	nflx, err := LoadQuoteSnapshots("NFLX", day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLasts(t, "NFLX", nflx, 500)
+	if nflx[0].Quote.Symbol != "NFLX" || !nflx[0].Received.Equal(open) {
+		t.Errorf("NFLX snapshot = %+v, want received at %s", *nflx[0], open)
+	}
+}
+
+func TestQuoteSnapshotsNewYorkDays(t *testing.T) {
+	resetStore(t)
+
+	snapshots := []quote.SnapshotGob{
+		// 19:59 and 20:01 in New York on Monday, still Monday although Tuesday in UTC.
+		testSnapshot("MSFT", time.Date(2024, 3, 4, 23, 59, 0, 0, time.UTC), 1),
+		testSnapshot("MSFT", time.Date(2024, 3, 5, 1, 1, 0, 0, time.UTC), 2),
+		// 9:30 in New York on Tuesday.
+		testSnapshot("MSFT", time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC), 3),
+		// 20:30 on the Sunday daylight saving time starts, 0:30 Monday in UTC.
+		testSnapshot("MSFT", time.Date(2024, 3, 11, 0, 30, 0, 0, time.UTC), 4),
+		// 9:30 in New York the next day, an hour earlier in UTC than before daylight saving time.
+		testSnapshot("MSFT", time.Date(2024, 3, 11, 13, 30, 0, 0, time.UTC), 5),
+	}
+	if err := SaveQuoteSnapshots(snapshots); err != nil {
+		t.Fatal(err)
+	}
+
+	days, err := QuoteSnapshotDates("MSFT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2024-03-04", "2024-03-05", "2024-03-10", "2024-03-11"}
+	if len(days) != len(want) {
+		t.Fatalf("%d days, want %d: %v", len(days), len(want), days)
+	}
+	for i, day := range days {
+		if day.Format("2006-01-02") != want[i] {
+			t.Errorf("day %d = %s, want %s", i, day.Format("2006-01-02"), want[i])
+		}
+	}
+
+	wantLasts := [][]float64{{1, 2}, {3}, {4}, {5}}
+	for i, day := range days {
+		loaded, err := LoadQuoteSnapshots("MSFT", day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertLasts(t, want[i], loaded, wantLasts[i]...)
+	}
+
+	if days, err = QuoteSnapshotDates("NFLX"); err != nil || len(days) != 0 {
+		t.Errorf("NFLX days = %v, %v; want none", days, err)
+	}
+}
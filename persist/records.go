@@ -0,0 +1,283 @@
+package persist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Prices and expirations files are rewritten whole, as one frame holding a gob stream of all records.
+// Other files are appended to, with a frame for each save, since a single gob stream cannot be extended
+// once it has been closed. See format.go for the file layout.
+
+// writeRecords replaces the file with one frame holding the values written by encode.
+func writeRecords(fname string, rt RecordType, encode func(enc *gob.Encoder) error) error {
+	var payload bytes.Buffer
+	if err := encode(gob.NewEncoder(&payload)); err != nil {
+		return err
+	}
+
+	return writeFrames(fname, rt, [][]byte{payload.Bytes()})
+}
+
+// writeFrames replaces the file with frames holding the payloads.
+func writeFrames(fname string, rt RecordType, payloads [][]byte) error {
+	data := header(rt)
+	for _, payload := range payloads {
+		data = append(data, frame(payload)...)
+	}
+
+	return writeFileAtomic(fname, data)
+}
+
+// appendRecord appends a frame holding the record to the file, creating the file if needed.
+// A frame left partly written by a failed append is removed first, and an older file is upgraded to the current version.
+func appendRecord(fname string, rt RecordType, record interface{}) error {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(record); err != nil {
+		return err
+	}
+
+	file, end, err := openForAppend(fname, rt)
+	if errors.Is(err, ErrLegacyFormat) || errors.Is(err, ErrOutdated) {
+		if _, _, err = upgradeFile(fname, rt, false); err == nil {
+			file, end, err = openForAppend(fname, rt)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	data := frame(payload.Bytes())
+	if end == 0 {
+		data = append(header(rt), data...)
+	}
+
+	// Write the whole frame at once, after the last complete frame.
+	if err = file.Truncate(end); err == nil {
+		if _, err = file.WriteAt(data, end); err == nil {
+			err = file.Sync()
+		}
+	}
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// openForAppend opens the file for appending, returning the offset after its last complete frame.
+func openForAppend(fname string, rt RecordType) (file *os.File, end int64, err error) {
+	file, err = os.OpenFile(fname, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, 0, err
+	}
+
+	// A file shorter than a header was left by a failed first append.
+	size := info.Size()
+	if size < headerSize {
+		return file, 0, nil
+	}
+
+	h, err := readHeader(bufio.NewReader(io.NewSectionReader(file, 0, size)))
+	if err == nil {
+		err = h.check(rt)
+	}
+	if err == nil && h.Version < rt.Version() {
+		err = ErrOutdated
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, 0, err
+	}
+
+	end = headerSize
+	var buf [frameSize]byte
+	for end+frameSize <= size {
+		if _, err := file.ReadAt(buf[:], end); err != nil {
+			_ = file.Close()
+			return nil, 0, err
+		}
+
+		next := end + frameSize + int64(binary.BigEndian.Uint32(buf[:]))
+		if next > size {
+			break
+		}
+		end = next
+	}
+
+	return file, end, nil
+}
+
+// readRecords calls decode for each value in the file, until decode returns io.EOF at the end of each frame.
+// The records of an older file are upgraded to the current version as they are read.
+func readRecords(fname string, rt RecordType, decode func(dec *gob.Decoder) error) error {
+	file, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	h, err := readHeader(r)
+	if err == nil {
+		err = h.check(rt)
+	}
+	if errors.Is(err, ErrLegacyFormat) || (err == nil && h.Version < rt.Version()) {
+		return readOutdatedRecords(r, rt, h.Version, errors.Is(err, ErrLegacyFormat), decode)
+	}
+	if err != nil {
+		return err
+	}
+
+	for cnt := 1; ; cnt++ {
+		payload, err := readFrame(r, false)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("record %d: %w", cnt, err)
+		}
+
+		if err := decodeAll(bytes.NewReader(payload), decode); err != nil {
+			return fmt.Errorf("record %d: %w", cnt, err)
+		}
+	}
+}
+
+// readPayloads returns the payloads of the file's frames, upgraded to the current version.
+func readPayloads(fname string, rt RecordType) ([][]byte, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(bytes.NewReader(data))
+	h, err := readHeader(r)
+	legacy := errors.Is(err, ErrLegacyFormat)
+	if err == nil {
+		err = h.check(rt)
+	}
+	if err != nil && !legacy {
+		return nil, err
+	}
+
+	frames, err := readFrames(r, rt, legacy)
+	if err != nil {
+		return nil, err
+	}
+
+	return upgradeFrames(rt, h.Version, frames)
+}
+
+func readOutdatedRecords(r *bufio.Reader, rt RecordType, version uint16, legacy bool, decode func(dec *gob.Decoder) error) error {
+	frames, err := readFrames(r, rt, legacy)
+	if err != nil {
+		return err
+	}
+
+	if frames, err = upgradeFrames(rt, version, frames); err != nil {
+		return err
+	}
+
+	for i, payload := range frames {
+		if err := decodeAll(bytes.NewReader(payload), decode); err != nil {
+			return fmt.Errorf("record %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// readFrames returns the payloads of the frames following the header. A legacy stream file is a single frame.
+func readFrames(r *bufio.Reader, rt RecordType, legacy bool) ([][]byte, error) {
+	if legacy && rt.legacyStream() {
+		data, err := io.ReadAll(r)
+		if err != nil || len(data) == 0 {
+			return nil, err
+		}
+		return [][]byte{data}, nil
+	}
+
+	var frames [][]byte
+	for cnt := 1; ; cnt++ {
+		payload, err := readFrame(r, legacy)
+		if errors.Is(err, io.EOF) {
+			return frames, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", cnt, err)
+		}
+		frames = append(frames, payload)
+	}
+}
+
+// decodeAll calls decode until the gob stream is exhausted.
+func decodeAll(r io.Reader, decode func(dec *gob.Decoder) error) error {
+	dec := gob.NewDecoder(r)
+	for {
+		if err := decode(dec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// upgradeFile upgrades the file to the current version of the record type, replacing it unless dryRun is set,
+// and returns the version it was upgraded from and the number of records in it.
+// A file already at the current version is left alone.
+func upgradeFile(fname string, rt RecordType, dryRun bool) (from uint16, records int, err error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	r := bufio.NewReader(bytes.NewReader(data))
+	h, err := readHeader(r)
+	legacy := errors.Is(err, ErrLegacyFormat)
+	if err == nil {
+		err = h.check(rt)
+	}
+	if err != nil && !legacy {
+		return h.Version, 0, err
+	}
+	if !legacy && h.Version == rt.Version() {
+		return h.Version, 0, nil
+	}
+
+	frames, err := readFrames(r, rt, legacy)
+	if err == nil {
+		frames, err = upgradeFrames(rt, h.Version, frames)
+	}
+	if err != nil {
+		return h.Version, 0, err
+	}
+
+	for i, payload := range frames {
+		n, err := rt.countRecords(payload)
+		if err != nil {
+			return h.Version, records, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		records += n
+	}
+
+	if dryRun {
+		return h.Version, records, nil
+	}
+
+	return h.Version, records, writeFrames(fname, rt, frames)
+}
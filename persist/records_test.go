@@ -0,0 +1,77 @@
+package persist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/quote"
+)
+
+// legacyFrame returns the value gob encoded in a frame without a checksum, as files were appended before the header.
+func legacyFrame(t *testing.T, value interface{}) []byte {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(value); err != nil {
+		t.Fatal(err)
+	}
+
+	data := make([]byte, 4, 4+payload.Len())
+	binary.BigEndian.PutUint32(data, uint32(payload.Len()))
+
+	return append(data, payload.Bytes()...)
+}
+
+func TestReadLegacyStream(t *testing.T) {
+	resetStore(t)
+
+	// Legacy prices files are a single gob stream of prices.
+	want := testPrices(4, 5, 6)
+	var stream bytes.Buffer
+	enc := gob.NewEncoder(&stream)
+	for _, price := range want {
+		if err := enc.Encode(price); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fname := pricesDir() + "MSFT"
+	writeStoreFile(t, fname, stream.Bytes())
+	assertPrices(t, "MSFT", want)
+
+	// Reading does not upgrade the file.
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, stream.Bytes()) {
+		t.Error("legacy prices file changed by reading it")
+	}
+}
+
+func TestReadLegacyFrames(t *testing.T) {
+	resetStore(t)
+
+	received := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	saves := [][]quote.SnapshotGob{
+		{{Received: received, Quote: quote.QuoteGob{Symbol: "MSFT", Last: 1}}},
+		{{Received: received.Add(time.Minute), Quote: quote.QuoteGob{Symbol: "MSFT", Last: 2}},
+			{Received: received.Add(2 * time.Minute), Quote: quote.QuoteGob{Symbol: "MSFT", Last: 3}}},
+	}
+
+	var data []byte
+	for _, snapshots := range saves {
+		data = append(data, legacyFrame(t, snapshots)...)
+	}
+	writeStoreFile(t, quotesSymbolDir("MSFT")+"2024-03-05", data)
+
+	snapshots, err := LoadQuoteSnapshots("MSFT", received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLasts(t, "legacy snapshots", snapshots, 1, 2, 3)
+}
@@ -0,0 +1,696 @@
+// Package sqlite is a persist.Store in an embedded SQLite database, using a pure Go driver.
+// Tables are keyed by symbol and date or time, so range queries stay fast across thousands of symbols.
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/config"
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/quote"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+
+	_ "modernc.org/sqlite"
+)
+
+const driverName = "sqlite"
+
+// The SQLite store in the store directory is used by commands when the backend config setting is "sqlite".
+func init() {
+	persist.RegisterBackend("sqlite", func() (persist.Store, error) {
+		return Open(DefaultFilename())
+	})
+}
+
+// DefaultFilename returns the database file used by commands, in the store directory.
+func DefaultFilename() string {
+	return filepath.Join(config.StoreDir(), "store.db")
+}
+
+const (
+	dateFmt = "2006-01-02"
+	timeFmt = "2006-01-02 15:04"
+)
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS daily_prices (
+		symbol TEXT NOT NULL,
+		date   TEXT NOT NULL,
+		open   REAL NOT NULL,
+		close  REAL NOT NULL,
+		high   REAL NOT NULL,
+		low    REAL NOT NULL,
+		volume INTEGER NOT NULL,
+		PRIMARY KEY (symbol, date)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS one_min_sales (
+		symbol TEXT NOT NULL,
+		time   TEXT NOT NULL,
+		open   REAL NOT NULL,
+		close  REAL NOT NULL,
+		high   REAL NOT NULL,
+		low    REAL NOT NULL,
+		volume INTEGER NOT NULL,
+		vwap   REAL NOT NULL,
+		PRIMARY KEY (symbol, time)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS expirations (
+		symbol TEXT NOT NULL,
+		date   TEXT NOT NULL,
+		PRIMARY KEY (symbol, date)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS expiration_strikes (
+		symbol TEXT NOT NULL,
+		date   TEXT NOT NULL,
+		strike REAL NOT NULL,
+		PRIMARY KEY (symbol, date, strike)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS quote_snapshots (
+		id         INTEGER PRIMARY KEY,
+		symbol     TEXT NOT NULL,
+		day        TEXT NOT NULL,
+		received   INTEGER NOT NULL,
+		trade_date INTEGER NOT NULL,
+		prev_close REAL NOT NULL,
+		change     REAL NOT NULL,
+		change_pct REAL NOT NULL,
+		bid        REAL NOT NULL,
+		bid_size   INTEGER NOT NULL,
+		ask        REAL NOT NULL,
+		ask_size   INTEGER NOT NULL,
+		last       REAL NOT NULL,
+		high       REAL NOT NULL,
+		low        REAL NOT NULL,
+		volume     INTEGER NOT NULL,
+		avg_volume INTEGER NOT NULL,
+		year_high  REAL NOT NULL,
+		year_low   REAL NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS quote_snapshots_symbol_day ON quote_snapshots (symbol, day, received)`,
+	`CREATE TABLE IF NOT EXISTS chain_snapshots (
+		id         INTEGER PRIMARY KEY,
+		symbol     TEXT NOT NULL,
+		time       INTEGER NOT NULL,
+		underlying REAL NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS chain_snapshots_symbol ON chain_snapshots (symbol, id)`,
+	`CREATE TABLE IF NOT EXISTS chain_strikes (
+		snapshot_id INTEGER NOT NULL,
+		expiration  TEXT NOT NULL,
+		strike      REAL NOT NULL,
+		PRIMARY KEY (snapshot_id, expiration, strike)
+	) WITHOUT ROWID`,
+	`CREATE TABLE IF NOT EXISTS chain_options (
+		snapshot_id   INTEGER NOT NULL,
+		expiration    TEXT NOT NULL,
+		strike        REAL NOT NULL,
+		call          INTEGER NOT NULL,
+		size          INTEGER NOT NULL,
+		open_interest INTEGER NOT NULL,
+		bid           REAL NOT NULL,
+		bid_size      INTEGER NOT NULL,
+		ask           REAL NOT NULL,
+		ask_size      INTEGER NOT NULL,
+		last          REAL NOT NULL,
+		change        REAL NOT NULL,
+		change_pct    REAL NOT NULL,
+		iv            REAL NOT NULL,
+		PRIMARY KEY (snapshot_id, expiration, strike, call)
+	) WITHOUT ROWID`,
+}
+
+// Store is a persist.Store in an SQLite database file.
+type Store struct {
+	db *sql.DB
+}
+
+var _ persist.Store = (*Store)(nil)
+
+// Open opens the database file, creating it and its tables if needed.
+func Open(fname string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(fname), 0755); err != nil {
+		return nil, fmt.Errorf("could not create database directory for %s: %w", fname, err)
+	}
+
+	db, err := sql.Open(driverName, fname)
+	if err != nil {
+		return nil, fmt.Errorf("could not open database %s: %w", fname, err)
+	}
+
+	// A single connection serializes writers within the process and keeps connection settings in effect.
+	db.SetMaxOpenConns(1)
+
+	statements := append([]string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000"}, schema...)
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("could not initialize database %s: %w", fname, err)
+		}
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// inTx runs f in a transaction, committing if it succeeds.
+func (s *Store) inTx(f func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := f(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) SavePrices(symbol string, prices []stock.DailyPriceGob) error {
+	err := s.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM daily_prices WHERE symbol = ?`, symbol); err != nil {
+			return err
+		}
+		return upsertPrices(tx, symbol, prices)
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist prices for %s: %w", symbol, err)
+	}
+
+	return nil
+}
+
+func (s *Store) UpsertPrices(symbol string, prices []stock.DailyPriceGob) error {
+	err := s.inTx(func(tx *sql.Tx) error {
+		return upsertPrices(tx, symbol, prices)
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist prices for %s: %w", symbol, err)
+	}
+
+	return nil
+}
+
+func upsertPrices(tx *sql.Tx, symbol string, prices []stock.DailyPriceGob) error {
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO daily_prices (symbol, date, open, close, high, low, volume)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, p := range prices {
+		date := time.Date(p.Year, time.Month(p.Month), p.Day, 0, 0, 0, 0, time.UTC).Format(dateFmt)
+		if _, err := stmt.Exec(symbol, date, p.Open, p.Close, p.High, p.Low, p.Volume); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) LoadPrices(symbol string) ([]*stock.DailyPriceGob, error) {
+	rows, err := s.db.Query(`SELECT date, open, close, high, low, volume FROM daily_prices
+		WHERE symbol = ? ORDER BY date`, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("could not load prices for %s: %w", symbol, err)
+	}
+	defer rows.Close()
+
+	var prices []*stock.DailyPriceGob
+	for rows.Next() {
+		var date string
+		p := &stock.DailyPriceGob{}
+		if err := rows.Scan(&date, &p.Open, &p.Close, &p.High, &p.Low, &p.Volume); err != nil {
+			return nil, fmt.Errorf("could not load prices for %s: %w", symbol, err)
+		}
+
+		d, err := time.Parse(dateFmt, date)
+		if err != nil {
+			return nil, fmt.Errorf("could not load prices for %s: %w", symbol, err)
+		}
+		p.Year, p.Month, p.Day = d.Year(), int(d.Month()), d.Day()
+
+		prices = append(prices, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not load prices for %s: %w", symbol, err)
+	}
+
+	return prices, nil
+}
+
+func (s *Store) ClearPrices() error {
+	if _, err := s.db.Exec(`DELETE FROM daily_prices`); err != nil {
+		return fmt.Errorf("could not remove stored prices: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) LastPriceDate(symbol string) (time.Time, error) {
+	return s.lastTime(`SELECT MAX(date) FROM daily_prices WHERE symbol = ?`, symbol, dateFmt)
+}
+
+// lastTime runs a query for the latest date or time of the symbol's data, returning the zero time if there is none.
+func (s *Store) lastTime(query, symbol, layout string) (time.Time, error) {
+	var last sql.NullString
+	if err := s.db.QueryRow(query, symbol).Scan(&last); err != nil {
+		return time.Time{}, fmt.Errorf("could not load latest time for %s: %w", symbol, err)
+	}
+	if !last.Valid {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(layout, last.String)
+}
+
+func (s *Store) SaveOneMinSales(symbol string, sales []stock.OneMinSaleGob) error {
+	err := s.inTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT OR REPLACE INTO one_min_sales (symbol, time, open, close, high, low, volume, vwap)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, sale := range sales {
+			_, err := stmt.Exec(symbol, sale.StartTime().Format(timeFmt),
+				sale.Open, sale.Close, sale.High, sale.Low, sale.Volume, sale.VWAP)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist one minute sales for %s: %w", symbol, err)
+	}
+
+	return nil
+}
+
+func (s *Store) LoadOneMinSales(symbol string, start, end time.Time) ([]*stock.OneMinSaleGob, error) {
+	rows, err := s.db.Query(`SELECT time, open, close, high, low, volume, vwap FROM one_min_sales
+		WHERE symbol = ? AND time >= ? AND time <= ? ORDER BY time`,
+		symbol, start.Format(timeFmt), end.Format(timeFmt))
+	if err != nil {
+		return nil, fmt.Errorf("could not load one minute sales for %s: %w", symbol, err)
+	}
+	defer rows.Close()
+
+	var sales []*stock.OneMinSaleGob
+	for rows.Next() {
+		var t string
+		sale := &stock.OneMinSaleGob{}
+		if err := rows.Scan(&t, &sale.Open, &sale.Close, &sale.High, &sale.Low, &sale.Volume, &sale.VWAP); err != nil {
+			return nil, fmt.Errorf("could not load one minute sales for %s: %w", symbol, err)
+		}
+
+		st, err := time.Parse(timeFmt, t)
+		if err != nil {
+			return nil, fmt.Errorf("could not load one minute sales for %s: %w", symbol, err)
+		}
+		sale.Year, sale.Month, sale.Day = st.Year(), int(st.Month()), st.Day()
+		sale.Hour, sale.Minute = st.Hour(), st.Minute()
+
+		sales = append(sales, sale)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not load one minute sales for %s: %w", symbol, err)
+	}
+
+	return sales, nil
+}
+
+func (s *Store) LastOneMinSaleTime(symbol string) (time.Time, error) {
+	return s.lastTime(`SELECT MAX(time) FROM one_min_sales WHERE symbol = ?`, symbol, timeFmt)
+}
+
+func (s *Store) SaveExpirations(symbol string, exps []option.ExpirationGob) error {
+	err := s.inTx(func(tx *sql.Tx) error {
+		for _, table := range []string{"expirations", "expiration_strikes"} {
+			if _, err := tx.Exec(`DELETE FROM `+table+` WHERE symbol = ?`, symbol); err != nil {
+				return err
+			}
+		}
+
+		expStmt, err := tx.Prepare(`INSERT OR REPLACE INTO expirations (symbol, date) VALUES (?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer expStmt.Close()
+
+		strikeStmt, err := tx.Prepare(`INSERT OR REPLACE INTO expiration_strikes (symbol, date, strike) VALUES (?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer strikeStmt.Close()
+
+		for _, exp := range exps {
+			date := exp.Date.Format(dateFmt)
+			if _, err := expStmt.Exec(symbol, date); err != nil {
+				return err
+			}
+			for _, strike := range exp.Strikes {
+				if _, err := strikeStmt.Exec(symbol, date, strike); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist option expirations for %s: %w", symbol, err)
+	}
+
+	return nil
+}
+
+// LoadExpirations loads the symbol's option expirations in date order, dated at noon UTC as expirations are created.
+// Databases written before expirations had their own rows only have the dates of expirations with strikes.
+func (s *Store) LoadExpirations(symbol string) ([]*option.ExpirationGob, error) {
+	rows, err := s.db.Query(`SELECT date, NULL FROM expirations WHERE symbol = ?
+		UNION ALL SELECT date, strike FROM expiration_strikes WHERE symbol = ? ORDER BY 1, 2`, symbol, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("could not load option expirations for %s: %w", symbol, err)
+	}
+	defer rows.Close()
+
+	var exps []*option.ExpirationGob
+	for rows.Next() {
+		var date string
+		var strike sql.NullFloat64
+		if err := rows.Scan(&date, &strike); err != nil {
+			return nil, fmt.Errorf("could not load option expirations for %s: %w", symbol, err)
+		}
+
+		d, err := time.Parse(dateFmt, date)
+		if err != nil {
+			return nil, fmt.Errorf("could not load option expirations for %s: %w", symbol, err)
+		}
+		d = time.Date(d.Year(), d.Month(), d.Day(), 12, 0, 0, 0, time.UTC)
+
+		if len(exps) == 0 || !exps[len(exps)-1].Date.Equal(d) {
+			exps = append(exps, &option.ExpirationGob{Date: d})
+		}
+		if strike.Valid {
+			exps[len(exps)-1].Strikes = append(exps[len(exps)-1].Strikes, strike.Float64)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not load option expirations for %s: %w", symbol, err)
+	}
+
+	return exps, nil
+}
+
+func (s *Store) SaveQuoteSnapshots(snapshots []quote.SnapshotGob) error {
+	err := s.inTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT INTO quote_snapshots (symbol, day, received, trade_date,
+			prev_close, change, change_pct, bid, bid_size, ask, ask_size, last, high, low,
+			volume, avg_volume, year_high, year_low)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, snapshot := range snapshots {
+			q := snapshot.Quote
+			_, err := stmt.Exec(q.Symbol, markets.MarketTime(snapshot.Received).Format(dateFmt),
+				snapshot.Received.UnixNano(), q.TradeDate.UnixNano(),
+				q.PrevClose, q.Change, q.ChangePct, q.Bid, q.BidSize, q.Ask, q.AskSize, q.Last, q.High, q.Low,
+				q.Volume, q.AvgVolume, q.YearHigh, q.YearLow)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist quote snapshots: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) LoadQuoteSnapshots(symbol string, day time.Time) ([]*quote.SnapshotGob, error) {
+	rows, err := s.db.Query(`SELECT received, trade_date, prev_close, change, change_pct, bid, bid_size,
+		ask, ask_size, last, high, low, volume, avg_volume, year_high, year_low
+		FROM quote_snapshots WHERE symbol = ? AND day = ? ORDER BY received, id`, symbol, day.Format(dateFmt))
+	if err != nil {
+		return nil, fmt.Errorf("could not load quote snapshots for %s: %w", symbol, err)
+	}
+	defer rows.Close()
+
+	var snapshots []*quote.SnapshotGob
+	for rows.Next() {
+		var received, tradeDate int64
+		sg := &quote.SnapshotGob{}
+		q := &sg.Quote
+		err := rows.Scan(&received, &tradeDate, &q.PrevClose, &q.Change, &q.ChangePct, &q.Bid, &q.BidSize,
+			&q.Ask, &q.AskSize, &q.Last, &q.High, &q.Low, &q.Volume, &q.AvgVolume, &q.YearHigh, &q.YearLow)
+		if err != nil {
+			return nil, fmt.Errorf("could not load quote snapshots for %s: %w", symbol, err)
+		}
+		q.Symbol = symbol
+		sg.Received = time.Unix(0, received)
+		q.TradeDate = time.Unix(0, tradeDate)
+
+		snapshots = append(snapshots, sg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not load quote snapshots for %s: %w", symbol, err)
+	}
+
+	return snapshots, nil
+}
+
+func (s *Store) QuoteSnapshotDates(symbol string) ([]time.Time, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT day FROM quote_snapshots WHERE symbol = ? ORDER BY day`, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("could not load quote snapshots for %s: %w", symbol, err)
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("could not load quote snapshots for %s: %w", symbol, err)
+		}
+
+		d, err := time.Parse(dateFmt, day)
+		if err != nil {
+			return nil, fmt.Errorf("could not load quote snapshots for %s: %w", symbol, err)
+		}
+		days = append(days, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not load quote snapshots for %s: %w", symbol, err)
+	}
+
+	return days, nil
+}
+
+func (s *Store) SaveChainSnapshot(symbol string, snapshot option.ChainSnapshotGob) error {
+	err := s.inTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`INSERT INTO chain_snapshots (symbol, time, underlying) VALUES (?, ?, ?)`,
+			symbol, snapshot.Time.UnixNano(), snapshot.Underlying)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		strikeStmt, err := tx.Prepare(`INSERT INTO chain_strikes (snapshot_id, expiration, strike) VALUES (?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer strikeStmt.Close()
+
+		optionStmt, err := tx.Prepare(`INSERT INTO chain_options (snapshot_id, expiration, strike, call,
+			size, open_interest, bid, bid_size, ask, ask_size, last, change, change_pct, iv)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer optionStmt.Close()
+
+		for _, exp := range snapshot.Expirations {
+			date := exp.Date.Format(dateFmt)
+			for _, strike := range exp.Strikes {
+				if _, err := strikeStmt.Exec(id, date, strike.Price); err != nil {
+					return err
+				}
+
+				if err := insertOption(optionStmt, id, date, strike.Price, true, strike.Call); err != nil {
+					return err
+				}
+				if err := insertOption(optionStmt, id, date, strike.Price, false, strike.Put); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist option chain snapshot for %s: %w", symbol, err)
+	}
+
+	return nil
+}
+
+// insertOption stores a call or put of a chain snapshot's strike, if it was loaded.
+func insertOption(stmt *sql.Stmt, id int64, date string, strike float64, call bool, g *option.OptionGob) error {
+	if g == nil {
+		return nil
+	}
+
+	_, err := stmt.Exec(id, date, strike, call, g.Size, g.OpenInterest,
+		g.Bid, g.BidSize, g.Ask, g.AskSize, g.Last, g.Change, g.ChangePct, g.IV)
+
+	return err
+}
+
+// chainStrike identifies a strike of a stored option chain snapshot.
+type chainStrike struct {
+	snapshotID int64
+	expiration string
+	price      float64
+}
+
+// LoadChainSnapshots loads the symbol's option chain snapshots in the order they were saved,
+// with expirations in date order.
+func (s *Store) LoadChainSnapshots(symbol string) ([]*option.ChainSnapshotGob, error) {
+	snapshots, err := s.loadChainSnapshots(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("could not load option chain snapshots for %s: %w", symbol, err)
+	}
+
+	return snapshots, nil
+}
+
+func (s *Store) loadChainSnapshots(symbol string) ([]*option.ChainSnapshotGob, error) {
+	var snapshots []*option.ChainSnapshotGob
+	byID := make(map[int64]*option.ChainSnapshotGob)
+	err := s.query(`SELECT id, time, underlying FROM chain_snapshots WHERE symbol = ? ORDER BY id`, symbol,
+		func(rows *sql.Rows) error {
+			var id, t int64
+			sg := &option.ChainSnapshotGob{Symbol: symbol}
+			if err := rows.Scan(&id, &t, &sg.Underlying); err != nil {
+				return err
+			}
+			sg.Time = time.Unix(0, t)
+
+			snapshots = append(snapshots, sg)
+			byID[id] = sg
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.query(`SELECT k.snapshot_id, k.expiration, k.strike FROM chain_strikes k
+		JOIN chain_snapshots c ON c.id = k.snapshot_id WHERE c.symbol = ? ORDER BY k.snapshot_id, k.expiration, k.strike`,
+		symbol, func(rows *sql.Rows) error {
+			var id int64
+			var date string
+			var price float64
+			if err := rows.Scan(&id, &date, &price); err != nil {
+				return err
+			}
+
+			d, err := time.Parse(dateFmt, date)
+			if err != nil {
+				return err
+			}
+			d = time.Date(d.Year(), d.Month(), d.Day(), 12, 0, 0, 0, time.UTC)
+
+			sg := byID[id]
+			if n := len(sg.Expirations); n == 0 || !sg.Expirations[n-1].Date.Equal(d) {
+				sg.Expirations = append(sg.Expirations, option.ChainExpirationGob{Date: d})
+			}
+			exp := &sg.Expirations[len(sg.Expirations)-1]
+			exp.Strikes = append(exp.Strikes, option.StrikeGob{Price: price})
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	strikes := make(map[chainStrike]*option.StrikeGob)
+	for id, sg := range byID {
+		for i := range sg.Expirations {
+			exp := &sg.Expirations[i]
+			for j := range exp.Strikes {
+				strikes[chainStrike{id, exp.Date.Format(dateFmt), exp.Strikes[j].Price}] = &exp.Strikes[j]
+			}
+		}
+	}
+
+	err = s.query(`SELECT o.snapshot_id, o.expiration, o.strike, o.call, o.size, o.open_interest,
+		o.bid, o.bid_size, o.ask, o.ask_size, o.last, o.change, o.change_pct, o.iv FROM chain_options o
+		JOIN chain_snapshots c ON c.id = o.snapshot_id WHERE c.symbol = ?`,
+		symbol, func(rows *sql.Rows) error {
+			var key chainStrike
+			var call bool
+			g := &option.OptionGob{}
+			err := rows.Scan(&key.snapshotID, &key.expiration, &key.price, &call, &g.Size, &g.OpenInterest,
+				&g.Bid, &g.BidSize, &g.Ask, &g.AskSize, &g.Last, &g.Change, &g.ChangePct, &g.IV)
+			if err != nil {
+				return err
+			}
+
+			strike, ok := strikes[key]
+			if !ok {
+				return fmt.Errorf("option without a strike: %s %g", key.expiration, key.price)
+			}
+			if call {
+				strike.Call = g
+			} else {
+				strike.Put = g
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// query runs a query with the symbol as its argument, calling f for each row.
+func (s *Store) query(query, symbol string, f func(rows *sql.Rows) error) error {
+	rows, err := s.db.Query(query, symbol)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := f(rows); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,199 @@
+package persist
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/config"
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/quote"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Store persists market data. GobStore keeps data in gob files, as the package functions do;
+// the sqlite package keeps it in an SQLite database. Commands use the store given by Open.
+type Store interface {
+	// SavePrices replaces all of the symbol's stored daily prices.
+	SavePrices(symbol string, prices []stock.DailyPriceGob) error
+	// UpsertPrices merges daily prices into the symbol's stored prices, replacing those with the same date.
+	UpsertPrices(symbol string, prices []stock.DailyPriceGob) error
+	// LoadPrices loads the symbol's daily prices in date order.
+	LoadPrices(symbol string) ([]*stock.DailyPriceGob, error)
+	// LastPriceDate returns the date of the latest stored price, or the zero time if none are stored.
+	LastPriceDate(symbol string) (time.Time, error)
+	// ClearPrices removes the stored daily prices of all symbols, for a full rebuild.
+	ClearPrices() error
+
+	// SaveOneMinSales adds one minute sales, replacing stored sales with the same time.
+	SaveOneMinSales(symbol string, sales []stock.OneMinSaleGob) error
+	// LoadOneMinSales loads the symbol's one minute sales from start through end, in time order.
+	LoadOneMinSales(symbol string, start, end time.Time) ([]*stock.OneMinSaleGob, error)
+	// LastOneMinSaleTime returns the time of the latest stored sale, or the zero time if none are stored.
+	LastOneMinSaleTime(symbol string) (time.Time, error)
+
+	// SaveExpirations replaces all of the symbol's stored option expirations.
+	SaveExpirations(symbol string, exps []option.ExpirationGob) error
+	// LoadExpirations loads the symbol's option expirations.
+	LoadExpirations(symbol string) ([]*option.ExpirationGob, error)
+
+	// SaveQuoteSnapshots adds quote snapshots of any symbols.
+	SaveQuoteSnapshots(snapshots []quote.SnapshotGob) error
+	// LoadQuoteSnapshots loads the symbol's quote snapshots received on the day (in New York), in the order received.
+	LoadQuoteSnapshots(symbol string, day time.Time) ([]*quote.SnapshotGob, error)
+	// QuoteSnapshotDates returns the days (in New York) with stored quote snapshots for the symbol, in date order.
+	QuoteSnapshotDates(symbol string) ([]time.Time, error)
+
+	// SaveChainSnapshot adds an option chain snapshot to the symbol's snapshot history.
+	SaveChainSnapshot(symbol string, snapshot option.ChainSnapshotGob) error
+	// LoadChainSnapshots loads the symbol's option chain snapshots in the order they were saved.
+	LoadChainSnapshots(symbol string) ([]*option.ChainSnapshotGob, error)
+
+	Close() error
+}
+
+var _ Store = (*GobStore)(nil)
+
+// backends opens a store of each kind that can be named by the backend config setting.
+var backends = map[string]func() (Store, error){
+	"gob": func() (Store, error) { return NewGobStore(), nil },
+}
+
+// RegisterBackend makes a kind of store available to Open. Store packages register themselves when imported,
+// so commands import them for their side effects, as with database/sql drivers.
+func RegisterBackend(name string, open func() (Store, error)) {
+	backends[name] = open
+}
+
+// Open opens the store named by the backend config setting.
+func Open() (Store, error) {
+	name := config.StoreBackend()
+	open, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown store backend: %s", name)
+	}
+
+	return open()
+}
+
+// LoadDailyPrices loads the symbol's stored prices as daily prices, in date order.
+func LoadDailyPrices(store Store, symbol string) ([]stock.DailyPrice, error) {
+	priceGobs, err := store.LoadPrices(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]stock.DailyPrice, 0, len(priceGobs))
+	for _, priceGob := range priceGobs {
+		price, err := priceGob.ToDailyPrice()
+		if err != nil {
+			return nil, fmt.Errorf("error loading price history for %s: %w", symbol, err)
+		}
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+}
+
+// LoadMinuteSales loads the symbol's stored one minute sales from start through end as one minute sales, in time order.
+func LoadMinuteSales(store Store, symbol string, start, end time.Time) ([]stock.OneMinSale, error) {
+	saleGobs, err := store.LoadOneMinSales(symbol, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	sales := make([]stock.OneMinSale, 0, len(saleGobs))
+	for _, saleGob := range saleGobs {
+		sale, err := saleGob.ToOneMinSale()
+		if err != nil {
+			return nil, fmt.Errorf("error loading one minute sales for %s: %w", symbol, err)
+		}
+		sales = append(sales, sale)
+	}
+
+	return sales, nil
+}
+
+// GobStore is a Store of gob files in the store directory (see package config).
+type GobStore struct{}
+
+func NewGobStore() *GobStore {
+	return &GobStore{}
+}
+
+func (gs *GobStore) SavePrices(symbol string, prices []stock.DailyPriceGob) error {
+	if err := EnsurePricesStore(); err != nil {
+		return err
+	}
+
+	return SavePrices(symbol, prices)
+}
+
+func (gs *GobStore) UpsertPrices(symbol string, prices []stock.DailyPriceGob) error {
+	if err := EnsurePricesStore(); err != nil {
+		return err
+	}
+
+	return UpsertPrices(symbol, prices)
+}
+
+func (gs *GobStore) LoadPrices(symbol string) ([]*stock.DailyPriceGob, error) {
+	return LoadPrices(symbol)
+}
+
+func (gs *GobStore) LastPriceDate(symbol string) (time.Time, error) {
+	return LastPriceDate(symbol)
+}
+
+func (gs *GobStore) ClearPrices() error {
+	return InitPricesStore()
+}
+
+func (gs *GobStore) SaveOneMinSales(symbol string, sales []stock.OneMinSaleGob) error {
+	return SaveOneMinSales(symbol, sales)
+}
+
+func (gs *GobStore) LoadOneMinSales(symbol string, start, end time.Time) ([]*stock.OneMinSaleGob, error) {
+	return LoadOneMinSales(symbol, start, end)
+}
+
+func (gs *GobStore) LastOneMinSaleTime(symbol string) (time.Time, error) {
+	return LastOneMinSaleTime(symbol)
+}
+
+func (gs *GobStore) SaveExpirations(symbol string, exps []option.ExpirationGob) error {
+	if err := os.MkdirAll(optionsDir(), 0755); err != nil {
+		return fmt.Errorf("could not create option expirations store %s: %w", optionsDir(), err)
+	}
+
+	return SaveExpirations(symbol, exps)
+}
+
+func (gs *GobStore) LoadExpirations(symbol string) ([]*option.ExpirationGob, error) {
+	return LoadExpirations(symbol)
+}
+
+func (gs *GobStore) SaveQuoteSnapshots(snapshots []quote.SnapshotGob) error {
+	return SaveQuoteSnapshots(snapshots)
+}
+
+func (gs *GobStore) LoadQuoteSnapshots(symbol string, day time.Time) ([]*quote.SnapshotGob, error) {
+	return LoadQuoteSnapshots(symbol, day)
+}
+
+func (gs *GobStore) QuoteSnapshotDates(symbol string) ([]time.Time, error) {
+	return QuoteSnapshotDates(symbol)
+}
+
+func (gs *GobStore) SaveChainSnapshot(symbol string, snapshot option.ChainSnapshotGob) error {
+	return SaveChainSnapshot(symbol, snapshot)
+}
+
+func (gs *GobStore) LoadChainSnapshots(symbol string) ([]*option.ChainSnapshotGob, error) {
+	return LoadChainSnapshots(symbol)
+}
+
+// Close does nothing, since files are closed after each use.
+func (gs *GobStore) Close() error {
+	return nil
+}
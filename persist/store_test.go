@@ -0,0 +1,86 @@
+package persist
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/config"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// TestMain runs the tests with a temporary data root. The configuration is loaded once per process,
+// so every test shares the root and clears the store with resetStore.
+func TestMain(m *testing.M) {
+	root, err := os.MkdirTemp("", "persist")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := os.Setenv(config.RootEnv, root); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	_ = os.RemoveAll(root)
+	os.Exit(code)
+}
+
+// resetStore removes everything from the store, leaving the empty directory of each record type.
+func resetStore(tb testing.TB) {
+	tb.Helper()
+
+	if err := os.RemoveAll(config.StoreDir()); err != nil {
+		tb.Fatal(err)
+	}
+	for _, rt := range []RecordType{PriceRecords, ExpirationRecords, ChainSnapshotRecords, OneMinSaleRecords, QuoteSnapshotRecords} {
+		if err := os.MkdirAll(rt.dir(), 0755); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+// testDays returns the given number of weekdays starting with Monday, March 4, 2024.
+func testDays(n int) []time.Time {
+	days := make([]time.Time, 0, n)
+	for day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC); len(days) < n; day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days = append(days, day)
+		}
+	}
+
+	return days
+}
+
+// testSales returns a session of synthetic one minute sales, a random walk in cents with a VWAP
+// of six decimal places, the same for each seed and day.
+func testSales(seed int, day time.Time) []stock.OneMinSaleGob {
+	rnd := rand.New(rand.NewSource(int64(seed)<<32 + day.Unix()))
+	cents := int64(2000 + rnd.Intn(50000))
+
+	sales := make([]stock.OneMinSaleGob, 0, stock.SessionMinutes)
+	open := day.Add(stock.MarketOpenHour*time.Hour + stock.MarketOpenMinute*time.Minute)
+	for t := open; t.Before(open.Add(stock.SessionMinutes * time.Minute)); t = t.Add(time.Minute) {
+		first := cents
+		cents += int64(rnd.Intn(21) - 10)
+		high, low := first+int64(rnd.Intn(8)), first-int64(rnd.Intn(8))
+		if cents > high {
+			high = cents
+		}
+		if cents < low {
+			low = cents
+		}
+
+		sales = append(sales, stock.OneMinSaleGob{
+			Year: t.Year(), Month: int(t.Month()), Day: t.Day(), Hour: t.Hour(), Minute: t.Minute(),
+			Open: float64(first) / 100, Close: float64(cents) / 100, High: float64(high) / 100, Low: float64(low) / 100,
+			Volume: int64(1000 + rnd.Intn(200000)),
+			VWAP:   float64(low*10000+rnd.Int63n((high-low)*10000+1)) / 1e6,
+		})
+	}
+
+	return sales
+}
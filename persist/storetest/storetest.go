@@ -0,0 +1,324 @@
+// Package storetest checks that a persist.Store behaves as the Store interface documents,
+// so each kind of store can be tested against the same expectations.
+package storetest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/quote"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+	"github.com/tsilvers/realtime-securities/persist"
+)
+
+// Run runs the conformance tests. open returns an empty store for each test, which Run closes.
+func Run(t *testing.T, open func(t *testing.T) persist.Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, store persist.Store)
+	}{
+		{"Prices", testPrices},
+		{"OneMinSales", testOneMinSales},
+		{"Expirations", testExpirations},
+		{"QuoteSnapshots", testQuoteSnapshots},
+		{"ChainSnapshots", testChainSnapshots},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := open(t)
+			defer func() {
+				if err := store.Close(); err != nil {
+					t.Error(err)
+				}
+			}()
+
+			tt.test(t, store)
+		})
+	}
+}
+
+func price(day int, close float64) stock.DailyPriceGob {
+	return stock.DailyPriceGob{Year: 2024, Month: 3, Day: day, Open: close - 1, Close: close, High: close + 1, Low: close - 2, Volume: 1000}
+}
+
+// assertPrices checks the loaded prices against want, in order.
+func assertPrices(t *testing.T, store persist.Store, symbol string, want ...stock.DailyPriceGob) {
+	t.Helper()
+
+	loaded, err := store.LoadPrices(symbol)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]stock.DailyPriceGob, 0, len(loaded))
+	for _, p := range loaded {
+		got = append(got, *p)
+	}
+	if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+		t.Fatalf("%s prices = %+v, want %+v", symbol, got, want)
+	}
+}
+
+func assertTime(t *testing.T, name string, got time.Time, err error, want time.Time) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("%s = %s, want %s", name, got, want)
+	}
+}
+
+func testPrices(t *testing.T, store persist.Store) {
+	last, err := store.LastPriceDate("MSFT")
+	assertTime(t, "last price date with none stored", last, err, time.Time{})
+
+	if err := store.SavePrices("MSFT", []stock.DailyPriceGob{price(4, 100), price(5, 101), price(6, 102)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SavePrices("NFLX", []stock.DailyPriceGob{price(4, 500)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving replaces all of the symbol's prices.
+	if err := store.SavePrices("MSFT", []stock.DailyPriceGob{price(5, 111), price(7, 113)}); err != nil {
+		t.Fatal(err)
+	}
+	assertPrices(t, store, "MSFT", price(5, 111), price(7, 113))
+
+	// Upserting replaces prices with the same date and keeps the rest, in date order.
+	if err := store.UpsertPrices("MSFT", []stock.DailyPriceGob{price(8, 124), price(4, 120), price(7, 123)}); err != nil {
+		t.Fatal(err)
+	}
+	assertPrices(t, store, "MSFT", price(4, 120), price(5, 111), price(7, 123), price(8, 124))
+	assertPrices(t, store, "NFLX", price(4, 500))
+
+	last, err = store.LastPriceDate("MSFT")
+	assertTime(t, "last price date", last, err, time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC))
+
+	if err := store.ClearPrices(); err != nil {
+		t.Fatal(err)
+	}
+	for _, symbol := range []string{"MSFT", "NFLX"} {
+		last, err = store.LastPriceDate(symbol)
+		assertTime(t, symbol+" last price date after clearing", last, err, time.Time{})
+	}
+}
+
+func sale(day, hour, minute int, close float64) stock.OneMinSaleGob {
+	return stock.OneMinSaleGob{
+		Year: 2024, Month: 3, Day: day, Hour: hour, Minute: minute,
+		Open: close, Close: close, High: close + 0.05, Low: close - 0.05, Volume: 100, VWAP: close + 0.01,
+	}
+}
+
+func assertSales(t *testing.T, store persist.Store, symbol string, start, end time.Time, want ...stock.OneMinSaleGob) {
+	t.Helper()
+
+	loaded, err := store.LoadOneMinSales(symbol, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]stock.OneMinSaleGob, 0, len(loaded))
+	for _, s := range loaded {
+		got = append(got, *s)
+	}
+	if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+		t.Fatalf("%s sales from %s to %s = %+v, want %+v", symbol, start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"), got, want)
+	}
+}
+
+func testOneMinSales(t *testing.T, store persist.Store) {
+	last, err := store.LastOneMinSaleTime("MSFT")
+	assertTime(t, "last sale time with none stored", last, err, time.Time{})
+
+	monday := []stock.OneMinSaleGob{sale(4, 9, 30, 100), sale(4, 9, 31, 101), sale(4, 9, 32, 102), sale(4, 15, 59, 103)}
+	tuesday := []stock.OneMinSaleGob{sale(5, 9, 30, 110), sale(5, 9, 31, 111)}
+	if err := store.SaveOneMinSales("MSFT", append(append([]stock.OneMinSaleGob{}, monday...), tuesday...)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sales with the same time are replaced, and others are added.
+	if err := store.SaveOneMinSales("MSFT", []stock.OneMinSaleGob{sale(4, 9, 31, 201), sale(4, 9, 33, 203)}); err != nil {
+		t.Fatal(err)
+	}
+	monday = []stock.OneMinSaleGob{sale(4, 9, 30, 100), sale(4, 9, 31, 201), sale(4, 9, 32, 102), sale(4, 9, 33, 203), sale(4, 15, 59, 103)}
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+	assertSales(t, store, "MSFT", time.Time{}, at(31, 0, 0), append(append([]stock.OneMinSaleGob{}, monday...), tuesday...)...)
+
+	// Both ends of the range are included.
+	assertSales(t, store, "MSFT", at(4, 9, 31), at(4, 9, 33), monday[1:4]...)
+	assertSales(t, store, "MSFT", at(4, 15, 59), at(5, 9, 30), monday[4], tuesday[0])
+	assertSales(t, store, "MSFT", at(4, 16, 0), at(5, 9, 29))
+
+	last, err = store.LastOneMinSaleTime("MSFT")
+	assertTime(t, "last sale time", last, err, at(5, 9, 31))
+}
+
+func testExpirations(t *testing.T, store persist.Store) {
+	first := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	second := time.Date(2024, 3, 22, 12, 0, 0, 0, time.UTC)
+	third := time.Date(2024, 4, 19, 12, 0, 0, 0, time.UTC)
+
+	if err := store.SaveExpirations("MSFT", []option.ExpirationGob{{Date: first, Strikes: []float64{400, 405}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving replaces all of the symbol's expirations, including those without strikes yet.
+	want := []option.ExpirationGob{
+		{Date: second, Strikes: []float64{395, 400, 410}},
+		{Date: third},
+	}
+	if err := store.SaveExpirations("MSFT", want); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := store.LoadExpirations("MSFT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(want) {
+		t.Fatalf("%d expirations, want %d", len(loaded), len(want))
+	}
+	for i, exp := range loaded {
+		if !exp.Date.Equal(want[i].Date) {
+			t.Errorf("expiration %d = %s, want %s", i, exp.Date, want[i].Date)
+		}
+		if len(exp.Strikes) != len(want[i].Strikes) || (len(exp.Strikes) > 0 && !reflect.DeepEqual(exp.Strikes, want[i].Strikes)) {
+			t.Errorf("expiration %d strikes = %v, want %v", i, exp.Strikes, want[i].Strikes)
+		}
+	}
+}
+
+func snapshot(symbol string, received time.Time, last float64) quote.SnapshotGob {
+	return quote.SnapshotGob{
+		Received: received,
+		Quote: quote.QuoteGob{
+			Symbol: symbol, TradeDate: received.Add(-time.Second), PrevClose: last - 1, Change: 1, ChangePct: 1 / (last - 1) * 100,
+			Bid: last - 0.01, BidSize: 3, Ask: last + 0.01, AskSize: 4, Last: last, High: last + 2, Low: last - 2,
+			Volume: 12345, AvgVolume: 23456, YearHigh: last + 50, YearLow: last - 50,
+		},
+	}
+}
+
+func testQuoteSnapshots(t *testing.T, store persist.Store) {
+	open := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC) // 9:30 in New York.
+	evening := time.Date(2024, 3, 5, 1, 0, 0, 0, time.UTC)
+
+	saves := [][]quote.SnapshotGob{
+		{snapshot("MSFT", open.Add(time.Minute), 2), snapshot("NFLX", open, 500), snapshot("MSFT", evening, 1)},
+		{snapshot("MSFT", open, 3), snapshot("MSFT", open, 4)},
+	}
+	for _, snapshots := range saves {
+		if err := store.SaveQuoteSnapshots(snapshots); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Received at 8:00 pm on Monday in New York.
+	days, err := store.QuoteSnapshotDates("MSFT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 2 || days[0].Format("2006-01-02") != "2024-03-04" || days[1].Format("2006-01-02") != "2024-03-05" {
+		t.Fatalf("days = %v, want 2024-03-04 and 2024-03-05", days)
+	}
+
+	loaded, err := store.LoadQuoteSnapshots("MSFT", days[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []quote.SnapshotGob{saves[1][0], saves[1][1], saves[0][0]}
+	if len(loaded) != len(want) {
+		t.Fatalf("%d snapshots, want %d", len(loaded), len(want))
+	}
+	for i, s := range loaded {
+		assertSnapshot(t, i, *s, want[i])
+	}
+
+	if loaded, err = store.LoadQuoteSnapshots("MSFT", days[0]); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 1 {
+		t.Fatalf("%d snapshots on %s, want 1", len(loaded), days[0].Format("2006-01-02"))
+	}
+	assertSnapshot(t, 0, *loaded[0], saves[0][2])
+}
+
+// assertSnapshot compares snapshots, allowing their times to be in different locations.
+func assertSnapshot(t *testing.T, i int, got, want quote.SnapshotGob) {
+	t.Helper()
+
+	if !got.Received.Equal(want.Received) || !got.Quote.TradeDate.Equal(want.Quote.TradeDate) {
+		t.Errorf("snapshot %d received %s traded %s, want %s and %s",
+			i, got.Received, got.Quote.TradeDate, want.Received, want.Quote.TradeDate)
+	}
+
+	got.Received, want.Received = time.Time{}, time.Time{}
+	got.Quote.TradeDate, want.Quote.TradeDate = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot %d = %+v, want %+v", i, got, want)
+	}
+}
+
+func chainSnapshot(taken time.Time, underlying float64) option.ChainSnapshotGob {
+	call := func(price float64) *option.OptionGob {
+		return &option.OptionGob{Size: 100, OpenInterest: 1200, Bid: price, BidSize: 10, Ask: price + 0.1, AskSize: 12,
+			Last: price + 0.05, Change: 0.5, ChangePct: 4.5, IV: 0.25}
+	}
+
+	return option.ChainSnapshotGob{
+		Symbol:     "MSFT",
+		Time:       taken,
+		Underlying: underlying,
+		Expirations: []option.ChainExpirationGob{
+			{Date: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC), Strikes: []option.StrikeGob{
+				{Price: 400, Call: call(6), Put: call(4)},
+				{Price: 405, Call: call(3)},
+			}},
+			{Date: time.Date(2024, 4, 19, 12, 0, 0, 0, time.UTC), Strikes: []option.StrikeGob{
+				{Price: 410, Put: call(12)},
+			}},
+		},
+	}
+}
+
+func testChainSnapshots(t *testing.T, store persist.Store) {
+	want := []option.ChainSnapshotGob{
+		chainSnapshot(time.Date(2024, 3, 5, 15, 0, 0, 0, time.UTC), 402.5),
+		chainSnapshot(time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC), 401.5),
+	}
+	for _, snapshot := range want {
+		if err := store.SaveChainSnapshot("MSFT", snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := store.LoadChainSnapshots("MSFT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(want) {
+		t.Fatalf("%d chain snapshots, want %d", len(loaded), len(want))
+	}
+
+	// Snapshots are loaded in the order saved, not by time.
+	for i, got := range loaded {
+		if !got.Time.Equal(want[i].Time) {
+			t.Errorf("chain snapshot %d time = %s, want %s", i, got.Time, want[i].Time)
+		}
+		g, w := *got, want[i]
+		g.Time, w.Time = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(g, w) {
+			t.Errorf("chain snapshot %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+)
+
+var expirationHeader = []string{"symbol", "date", "strikes"}
+
+// ExpirationRecord is an option expiration in exported form. In CSV, strikes are separated by spaces.
+type ExpirationRecord struct {
+	Symbol  string    `json:"symbol"`
+	Date    string    `json:"date"` // YYYY-MM-DD
+	Strikes []float64 `json:"strikes"`
+}
+
+// ExpirationRecords converts a symbol's stored option expirations for export.
+func ExpirationRecords(symbol string, exps []*option.ExpirationGob) []ExpirationRecord {
+	records := make([]ExpirationRecord, 0, len(exps))
+	for _, exp := range exps {
+		records = append(records, ExpirationRecord{Symbol: symbol, Date: exp.Date.Format(dateFmt), Strikes: exp.Strikes})
+	}
+
+	return records
+}
+
+// ToGob validates the record as stored expirations are and converts it for storage.
+// Expirations that have passed are allowed, so exported expirations can be imported again.
+func (er ExpirationRecord) ToGob() (option.ExpirationGob, error) {
+	if er.Symbol == "" {
+		return option.ExpirationGob{}, fmt.Errorf("symbol is missing")
+	}
+
+	date, err := time.Parse(dateFmt, er.Date)
+	if err != nil {
+		return option.ExpirationGob{}, fmt.Errorf("invalid date: %s", er.Date)
+	}
+
+	exp, err := option.ExpirationGob{Date: date, Strikes: er.Strikes}.ToExpiration()
+	if err != nil {
+		return option.ExpirationGob{}, fmt.Errorf("%s %s: %w", er.Symbol, er.Date, err)
+	}
+
+	return exp.ToGob(), nil
+}
+
+func WriteExpirations(w io.Writer, f Format, records []ExpirationRecord) error {
+	if f != CSV {
+		return writeJSON(w, f, len(records), func(i int) interface{} { return records[i] })
+	}
+
+	return writeCSV(w, expirationHeader, len(records), func(i int) []string {
+		r := records[i]
+		strikes := make([]string, 0, len(r.Strikes))
+		for _, strike := range r.Strikes {
+			strikes = append(strikes, formatFloat(strike))
+		}
+		return []string{r.Symbol, r.Date, strings.Join(strikes, " ")}
+	})
+}
+
+// ReadExpirations reads option expiration records. They are not validated until converted with ToGob.
+func ReadExpirations(r io.Reader, f Format) ([]ExpirationRecord, error) {
+	var records []ExpirationRecord
+
+	var err error
+	if f == CSV {
+		err = readCSV(r, expirationHeader, func(row []string) error {
+			er := ExpirationRecord{Symbol: row[0], Date: row[1]}
+			fields := strings.Fields(row[2])
+			er.Strikes = make([]float64, len(fields))
+			xs := make([]*float64, len(fields))
+			for i := range er.Strikes {
+				xs[i] = &er.Strikes[i]
+			}
+			if err := parseFloats(fields, xs...); err != nil {
+				return err
+			}
+			records = append(records, er)
+			return nil
+		})
+	} else {
+		err = readJSON(r, f, func(dec *json.Decoder) error {
+			er := ExpirationRecord{}
+			if err := dec.Decode(&er); err != nil {
+				return err
+			}
+			records = append(records, er)
+			return nil
+		})
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read option expirations: %w", err)
+	}
+
+	return records, nil
+}
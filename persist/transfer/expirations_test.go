@@ -0,0 +1,59 @@
+package transfer
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+)
+
+// Stored expirations that have long passed are exported and imported again unchanged.
+func TestExpirationsRoundTrip(t *testing.T) {
+	exps := []*option.ExpirationGob{
+		{Date: time.Date(2020, 3, 20, 12, 0, 0, 0, time.UTC), Strikes: []float64{90, 95, 100}},
+		{Date: time.Date(2020, 4, 17, 12, 0, 0, 0, time.UTC), Strikes: []float64{92.5, 97.5}},
+	}
+
+	for _, f := range []Format{CSV, JSON, JSONL} {
+		var buf bytes.Buffer
+		if err := WriteExpirations(&buf, f, ExpirationRecords("MSFT", exps)); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+
+		records, err := ReadExpirations(&buf, f)
+		if err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		if len(records) != len(exps) {
+			t.Fatalf("%s: read %d expirations, want %d", f, len(records), len(exps))
+		}
+
+		for i, r := range records {
+			got, err := r.ToGob()
+			if err != nil {
+				t.Fatalf("%s: %v", f, err)
+			}
+			if !reflect.DeepEqual(got, *exps[i]) {
+				t.Errorf("%s: imported %+v, want %+v", f, got, *exps[i])
+			}
+		}
+	}
+}
+
+func TestExpirationRecordInvalid(t *testing.T) {
+	records := []ExpirationRecord{
+		{Date: "2020-03-20", Strikes: []float64{100}},
+		{Symbol: "MSFT", Date: "03/20/2020", Strikes: []float64{100}},
+		{Symbol: "MSFT", Date: "2020-03-20"},
+		{Symbol: "MSFT", Date: "2020-03-20", Strikes: []float64{100, 100}},
+		{Symbol: "MSFT", Date: "2020-03-20", Strikes: []float64{-5, 100}},
+	}
+
+	for _, r := range records {
+		if _, err := r.ToGob(); err == nil {
+			t.Errorf("%+v converted, want an error", r)
+		}
+	}
+}
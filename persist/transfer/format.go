@@ -0,0 +1,146 @@
+// Package transfer exports stored market data to CSV, JSON and JSON Lines, and imports it back
+// with the same validation as data from a provider.
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	CSV Format = iota
+	JSON
+	JSONL // JSON Lines: one JSON object per line.
+)
+
+// Format is a file format for exported data.
+type Format int
+
+func (f Format) String() string {
+	switch f {
+	case CSV:
+		return "CSV"
+	case JSON:
+		return "JSON"
+	case JSONL:
+		return "JSONL"
+	}
+
+	return "Unknown"
+}
+
+// FormatOf returns the format given by a file's extension: .csv, .json, or .jsonl.
+func FormatOf(fname string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(fname)) {
+	case ".csv":
+		return CSV, nil
+	case ".json":
+		return JSON, nil
+	case ".jsonl":
+		return JSONL, nil
+	}
+
+	return CSV, fmt.Errorf("unknown file format: %s", fname)
+}
+
+// writeCSV writes the header and a row for each of n records.
+func writeCSV(w io.Writer, header []string, n int, row func(i int) []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i := 0; i < n; i++ {
+		if err := cw.Write(row(i)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// readCSV checks the header and calls next with each row.
+func readCSV(r io.Reader, header []string, next func(row []string) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(header)
+
+	got, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("could not read CSV header: %w", err)
+	}
+	for i := range header {
+		if strings.TrimSpace(strings.ToLower(got[i])) != header[i] {
+			return fmt.Errorf("invalid CSV header %q, expected %q", strings.Join(got, ","), strings.Join(header, ","))
+		}
+	}
+
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := next(row); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+}
+
+// writeJSON writes n records as a JSON array, or as JSON Lines.
+func writeJSON(w io.Writer, f Format, n int, record func(i int) interface{}) error {
+	if f == JSON {
+		records := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			records = append(records, record(i))
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	enc := json.NewEncoder(w)
+	for i := 0; i < n; i++ {
+		if err := enc.Encode(record(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readJSON calls next to decode each record of a JSON array, or of JSON Lines.
+func readJSON(r io.Reader, f Format, next func(dec *json.Decoder) error) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	if f == JSON {
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return fmt.Errorf("JSON data must be an array of records")
+		}
+	}
+
+	for cnt := 1; dec.More(); cnt++ {
+		if err := next(dec); err != nil {
+			return fmt.Errorf("record %d: %w", cnt, err)
+		}
+	}
+
+	if f == JSON {
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("JSON array is not terminated: %w", err)
+		}
+	}
+
+	return nil
+}
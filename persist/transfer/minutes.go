@@ -0,0 +1,130 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+const timeFmt = "2006-01-02 15:04"
+
+var minuteHeader = []string{"symbol", "time", "open", "close", "high", "low", "volume", "vwap"}
+
+// MinuteRecord is a one minute sale in exported form.
+type MinuteRecord struct {
+	Symbol string  `json:"symbol"`
+	Time   string  `json:"time"` // YYYY-MM-DD HH:MM, New York time.
+	Open   float64 `json:"open"`
+	Close  float64 `json:"close"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Volume int64   `json:"volume"`
+	VWAP   float64 `json:"vwap"`
+}
+
+// MinuteRecords converts a symbol's stored one minute sales for export.
+func MinuteRecords(symbol string, sales []*stock.OneMinSaleGob) []MinuteRecord {
+	records := make([]MinuteRecord, 0, len(sales))
+	for _, s := range sales {
+		records = append(records, MinuteRecord{
+			Symbol: symbol,
+			Time:   s.StartTime().Format(timeFmt),
+			Open:   s.Open,
+			Close:  s.Close,
+			High:   s.High,
+			Low:    s.Low,
+			Volume: s.Volume,
+			VWAP:   s.VWAP,
+		})
+	}
+
+	return records
+}
+
+// ToGob validates the record as NewOneMinSale does and converts it for storage.
+// As for stored sales, sales older than the data provider serves are allowed.
+func (mr MinuteRecord) ToGob() (stock.OneMinSaleGob, error) {
+	if mr.Symbol == "" {
+		return stock.OneMinSaleGob{}, fmt.Errorf("symbol is missing")
+	}
+
+	t, err := time.Parse(timeFmt, mr.Time)
+	if err != nil {
+		return stock.OneMinSaleGob{}, fmt.Errorf("invalid time: %s", mr.Time)
+	}
+
+	omsg := stock.OneMinSaleGob{
+		Year:   t.Year(),
+		Month:  int(t.Month()),
+		Day:    t.Day(),
+		Hour:   t.Hour(),
+		Minute: t.Minute(),
+		Open:   mr.Open,
+		Close:  mr.Close,
+		High:   mr.High,
+		Low:    mr.Low,
+		Volume: mr.Volume,
+		VWAP:   mr.VWAP,
+	}
+	if _, err := omsg.ToOneMinSale(); err != nil {
+		return stock.OneMinSaleGob{}, fmt.Errorf("%s %s: %w", mr.Symbol, mr.Time, err)
+	}
+
+	return omsg, nil
+}
+
+func WriteMinutes(w io.Writer, f Format, records []MinuteRecord) error {
+	if f != CSV {
+		return writeJSON(w, f, len(records), func(i int) interface{} { return records[i] })
+	}
+
+	return writeCSV(w, minuteHeader, len(records), func(i int) []string {
+		r := records[i]
+		return []string{r.Symbol, r.Time, formatFloat(r.Open), formatFloat(r.Close), formatFloat(r.High),
+			formatFloat(r.Low), strconv.FormatInt(r.Volume, 10), formatFloat(r.VWAP)}
+	})
+}
+
+// ReadMinutes reads one minute sale records. They are not validated until converted with ToGob.
+func ReadMinutes(r io.Reader, f Format) ([]MinuteRecord, error) {
+	var records []MinuteRecord
+
+	var err error
+	if f == CSV {
+		err = readCSV(r, minuteHeader, func(row []string) error {
+			mr := MinuteRecord{Symbol: row[0], Time: row[1]}
+			if err := parseFloats(row[2:6], &mr.Open, &mr.Close, &mr.High, &mr.Low); err != nil {
+				return err
+			}
+			volume, err := strconv.ParseInt(row[6], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid volume: %s", row[6])
+			}
+			mr.Volume = volume
+			if err := parseFloats(row[7:8], &mr.VWAP); err != nil {
+				return err
+			}
+			records = append(records, mr)
+			return nil
+		})
+	} else {
+		err = readJSON(r, f, func(dec *json.Decoder) error {
+			mr := MinuteRecord{}
+			if err := dec.Decode(&mr); err != nil {
+				return err
+			}
+			records = append(records, mr)
+			return nil
+		})
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read one minute sales: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,129 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+const dateFmt = "2006-01-02"
+
+var priceHeader = []string{"symbol", "date", "open", "close", "high", "low", "volume"}
+
+// PriceRecord is a daily price in exported form.
+type PriceRecord struct {
+	Symbol string  `json:"symbol"`
+	Date   string  `json:"date"` // YYYY-MM-DD
+	Open   float64 `json:"open"`
+	Close  float64 `json:"close"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Volume int64   `json:"volume"`
+}
+
+// PriceRecords converts a symbol's stored daily prices for export.
+func PriceRecords(symbol string, prices []*stock.DailyPriceGob) []PriceRecord {
+	records := make([]PriceRecord, 0, len(prices))
+	for _, p := range prices {
+		records = append(records, PriceRecord{
+			Symbol: symbol,
+			Date:   time.Date(p.Year, time.Month(p.Month), p.Day, 0, 0, 0, 0, time.UTC).Format(dateFmt),
+			Open:   p.Open,
+			Close:  p.Close,
+			High:   p.High,
+			Low:    p.Low,
+			Volume: p.Volume,
+		})
+	}
+
+	return records
+}
+
+// ToGob validates the record as NewDailyPrice does and converts it for storage.
+func (pr PriceRecord) ToGob() (stock.DailyPriceGob, error) {
+	if pr.Symbol == "" {
+		return stock.DailyPriceGob{}, fmt.Errorf("symbol is missing")
+	}
+
+	date, err := time.Parse(dateFmt, pr.Date)
+	if err != nil {
+		return stock.DailyPriceGob{}, fmt.Errorf("invalid date: %s", pr.Date)
+	}
+
+	dp, err := stock.NewDailyPrice(date.Year(), int(date.Month()), date.Day(), pr.Open, pr.Close, pr.High, pr.Low, pr.Volume)
+	if err != nil {
+		return stock.DailyPriceGob{}, fmt.Errorf("%s %s: %w", pr.Symbol, pr.Date, err)
+	}
+
+	return dp.ToGob(), nil
+}
+
+func WritePrices(w io.Writer, f Format, records []PriceRecord) error {
+	if f != CSV {
+		return writeJSON(w, f, len(records), func(i int) interface{} { return records[i] })
+	}
+
+	return writeCSV(w, priceHeader, len(records), func(i int) []string {
+		r := records[i]
+		return []string{r.Symbol, r.Date, formatFloat(r.Open), formatFloat(r.Close), formatFloat(r.High),
+			formatFloat(r.Low), strconv.FormatInt(r.Volume, 10)}
+	})
+}
+
+// ReadPrices reads daily price records. They are not validated until converted with ToGob.
+func ReadPrices(r io.Reader, f Format) ([]PriceRecord, error) {
+	var records []PriceRecord
+
+	var err error
+	if f == CSV {
+		err = readCSV(r, priceHeader, func(row []string) error {
+			pr := PriceRecord{Symbol: row[0], Date: row[1]}
+			if err := parseFloats(row[2:6], &pr.Open, &pr.Close, &pr.High, &pr.Low); err != nil {
+				return err
+			}
+			volume, err := strconv.ParseInt(row[6], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid volume: %s", row[6])
+			}
+			pr.Volume = volume
+			records = append(records, pr)
+			return nil
+		})
+	} else {
+		err = readJSON(r, f, func(dec *json.Decoder) error {
+			pr := PriceRecord{}
+			if err := dec.Decode(&pr); err != nil {
+				return err
+			}
+			records = append(records, pr)
+			return nil
+		})
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read daily prices: %w", err)
+	}
+
+	return records, nil
+}
+
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
+
+// parseFloats parses each field into the float at the same position.
+func parseFloats(fields []string, xs ...*float64) error {
+	for i, field := range fields {
+		x, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number: %s", field)
+		}
+		*xs[i] = x
+	}
+
+	return nil
+}
@@ -0,0 +1,711 @@
+package persist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/config"
+	"github.com/tsilvers/realtime-securities/markets"
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/quote"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// Verify reads every file of the gob store as the loaders do, but keeps going past damage to report all of it:
+// frames failing their checksum are skipped, and each record is validated as it was when it was retrieved and
+// checked to be in order without duplicates. Quote snapshots are not checked for order, since concurrent
+// recorders may append them out of order.
+//
+// Repaired files are rewritten with their valid records, in order and keeping the last of any duplicates,
+// and the original is kept in the quarantine directory. Files that cannot be repaired are moved there.
+
+// quarantineName is the name of the store directory holding quarantined files, under a directory for each run.
+const quarantineName = "quarantine"
+
+// VerifyMode is what Verify does with files that have problems.
+type VerifyMode int
+
+const (
+	ReportOnly VerifyMode = iota
+	Quarantine            // Move files with problems to the quarantine directory.
+	Repair                // Rewrite damaged files with their valid records, quarantining those that cannot be repaired.
+)
+
+// ProblemKind classifies a problem found in a stored file.
+type ProblemKind int
+
+const (
+	Unreadable ProblemKind = iota + 1 // The file cannot be read, or its header is damaged.
+	Corrupt                           // Frames or records cannot be decoded.
+	Invalid                           // Records fail validation or are out of order or duplicated.
+	Orphaned                          // The file's symbol is not in the list of stock symbols.
+	Leftover                          // A temporary file left by an interrupted write.
+)
+
+func (pk ProblemKind) String() string {
+	switch pk {
+	case Unreadable:
+		return "unreadable"
+	case Corrupt:
+		return "corrupt"
+	case Invalid:
+		return "invalid"
+	case Orphaned:
+		return "orphaned"
+	case Leftover:
+		return "leftover"
+	default:
+		return fmt.Sprintf("problem %d", int(pk))
+	}
+}
+
+// Problem is a problem found in a stored file.
+type Problem struct {
+	Kind   ProblemKind
+	Detail string
+}
+
+func (p Problem) String() string {
+	return p.Kind.String() + ": " + p.Detail
+}
+
+// FileCheck is the result of verifying a stored file.
+type FileCheck struct {
+	File     string // Path in the store directory.
+	Type     RecordType
+	Records  int // Valid records.
+	Problems []Problem
+	Action   string // "repaired" or "quarantined" if the file was changed.
+	Err      error  // Error repairing or quarantining the file.
+}
+
+// OK returns true if no problems were found in the file.
+func (fc FileCheck) OK() bool {
+	return len(fc.Problems) == 0
+}
+
+// repairable returns true if the file can be rewritten with its valid records.
+func (fc FileCheck) repairable() bool {
+	for _, p := range fc.Problems {
+		if p.Kind != Corrupt && p.Kind != Invalid {
+			return false
+		}
+	}
+
+	return fc.Records > 0
+}
+
+func VerifyHeader() string {
+	return fmt.Sprintln("File                                     Records  Problems")
+}
+
+func (fc FileCheck) String() string {
+	problems := make([]string, len(fc.Problems))
+	for i, p := range fc.Problems {
+		problems[i] = p.String()
+	}
+
+	result := strings.Join(problems, "; ")
+	if fc.Action != "" {
+		result += " [" + fc.Action + "]"
+	}
+	if fc.Err != nil {
+		result += " ERROR: " + fc.Err.Error()
+	}
+
+	return fmt.Sprintf("%-40s %7d  %s", fc.File, fc.Records, result)
+}
+
+// QuarantineDir returns the directory files are quarantined in.
+func QuarantineDir() string {
+	return filepath.Join(config.StoreDir(), quarantineName)
+}
+
+// Verify checks every file of the gob store and returns the results, including files without problems.
+// Files whose symbols are not among the symbols are orphaned; no files are orphaned if symbols is empty.
+// Files with problems are left unchanged by ReportOnly, and quarantined or repaired by the other modes.
+func Verify(symbols []string, mode VerifyMode) ([]FileCheck, error) {
+	v := verifier{
+		mode:       mode,
+		symbols:    make(map[string]bool, len(symbols)),
+		quarantine: filepath.Join(QuarantineDir(), time.Now().Format("20060102-150405")),
+	}
+	for _, symbol := range symbols {
+		v.symbols[stockFilename(symbol)] = true
+	}
+
+	for _, rt := range []RecordType{PriceRecords, ExpirationRecords, ChainSnapshotRecords, OneMinSaleRecords, QuoteSnapshotRecords} {
+		if err := v.verifyType(rt); err != nil {
+			return v.checks, err
+		}
+	}
+
+	return v.checks, nil
+}
+
+type verifier struct {
+	mode       VerifyMode
+	symbols    map[string]bool // File names of the symbols.
+	quarantine string
+	checks     []FileCheck
+}
+
+// verifyType checks the files of the record type, holding its lock so no files are written meanwhile.
+func (v *verifier) verifyType(rt RecordType) error {
+	unlock, err := lockStore(rt, v.mode != ReportOnly)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	dir := rt.dir()
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not list %s store: %w", rt, err)
+	}
+
+	perDay := rt == OneMinSaleRecords || rt == QuoteSnapshotRecords
+	for _, entry := range entries {
+		switch {
+		case isTempFile(entry.Name()):
+			v.leftover(rt, dir+entry.Name())
+		case strings.HasPrefix(entry.Name(), "."):
+		case !perDay && !entry.IsDir():
+			v.verifyFile(rt, dir+entry.Name(), entry.Name(), time.Time{})
+		case perDay && entry.IsDir():
+			if err := v.verifySymbolDir(rt, entry.Name()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifySymbolDir checks the day files of a symbol, and the index of its one minute sales.
+func (v *verifier) verifySymbolDir(rt RecordType, name string) error {
+	dir := rt.dir() + name + string(filepath.Separator)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("could not list %s store: %w", rt, err)
+	}
+
+	for _, entry := range entries {
+		if isTempFile(entry.Name()) {
+			v.leftover(rt, dir+entry.Name())
+			continue
+		}
+		if day, err := time.Parse(dayFileFmt, entry.Name()); err == nil && !entry.IsDir() {
+			v.verifyFile(rt, dir+entry.Name(), name, day)
+		}
+	}
+
+	if rt == OneMinSaleRecords {
+		v.verifyMinuteIndex(name)
+	}
+
+	if v.mode != ReportOnly {
+		// Removes the directory only if every file was quarantined.
+		_ = os.Remove(dir)
+	}
+
+	return nil
+}
+
+// verifyFile checks a file holding records of the symbol with the file name, for the day of per-day files.
+func (v *verifier) verifyFile(rt RecordType, fname, name string, day time.Time) {
+	check := FileCheck{File: storePath(fname), Type: rt}
+
+	var payloads [][]byte
+	data, err := os.ReadFile(fname)
+	if err == nil {
+		var frames [][]byte
+		frames, check.Problems, err = salvageFrames(data, rt)
+		if err == nil {
+			check.Records, check.Problems, payloads, err = checkRecords(rt, name, day, frames, check.Problems)
+
+			if err == nil && check.Records == 0 && len(check.Problems) == 0 && (!day.IsZero() || len(frames) == 0) {
+				check.Problems = append(check.Problems, Problem{Kind: Corrupt, Detail: "file holds no records"})
+			}
+		}
+	}
+	if err != nil {
+		check.Problems = append(check.Problems, Problem{Kind: Unreadable, Detail: err.Error()})
+	}
+
+	v.checkSymbol(&check, name)
+	v.resolve(&check, fname, data, payloads)
+	v.checks = append(v.checks, check)
+}
+
+// verifyMinuteIndex checks that the index of a symbol's one minute sales lists its day files, after any have been
+// repaired or quarantined. The index is not checked if it is missing, as loads build it, or if day files are damaged.
+func (v *verifier) verifyMinuteIndex(name string) {
+	fname := minuteIndexFile(name)
+	data, err := os.ReadFile(fname)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+
+	check := FileCheck{File: storePath(fname), Type: MinuteIndexRecords}
+	var stored minuteIndex
+	if err == nil {
+		var frames [][]byte
+		frames, check.Problems, err = salvageFrames(data, MinuteIndexRecords)
+		check.Problems = append(check.Problems, decodeFrames(frames, func(dec *gob.Decoder) error {
+			return dec.Decode(&stored)
+		})...)
+	}
+	if err != nil {
+		check.Problems = append(check.Problems, Problem{Kind: Unreadable, Detail: err.Error()})
+	}
+	check.Records = len(stored)
+
+	built, buildErr := buildMinuteIndex(name)
+	if buildErr == nil && len(check.Problems) == 0 && !v.orphaned(name) {
+		if detail := diffMinuteIndex(stored, built); detail != "" {
+			check.Problems = append(check.Problems, Problem{Kind: Invalid, Detail: detail})
+		}
+	}
+
+	v.checkSymbol(&check, name)
+
+	// The index is rebuilt rather than salvaged.
+	if !check.OK() && v.mode == Repair && buildErr == nil && !v.orphaned(name) {
+		v.keep(&check, fname, data)
+		if check.Err == nil {
+			check.Err = saveMinuteIndex(name, built)
+		}
+		check.Action = "repaired"
+		check.Records = len(built)
+	} else {
+		v.resolve(&check, fname, data, nil)
+	}
+
+	v.checks = append(v.checks, check)
+}
+
+// diffMinuteIndex describes the first difference between the stored index and the index built from the day files.
+func diffMinuteIndex(stored, built minuteIndex) string {
+	for i := 0; i < len(stored) || i < len(built); i++ {
+		switch {
+		case i == len(stored):
+			return fmt.Sprintf("day %s is not indexed", built[i].Day.Format(dayFileFmt))
+		case i == len(built) || stored[i].Day.Before(built[i].Day):
+			return fmt.Sprintf("indexed day %s has no day file", stored[i].Day.Format(dayFileFmt))
+		case stored[i].Day.After(built[i].Day):
+			return fmt.Sprintf("day %s is not indexed", built[i].Day.Format(dayFileFmt))
+		case !stored[i].First.Equal(built[i].First) || !stored[i].Last.Equal(built[i].Last) || stored[i].Count != built[i].Count:
+			return fmt.Sprintf("index of day %s does not match its day file", built[i].Day.Format(dayFileFmt))
+		}
+	}
+
+	return ""
+}
+
+func (v *verifier) leftover(rt RecordType, fname string) {
+	check := FileCheck{File: storePath(fname), Type: rt, Problems: []Problem{{Kind: Leftover, Detail: "temporary file of an interrupted write"}}}
+	v.resolve(&check, fname, nil, nil)
+	v.checks = append(v.checks, check)
+}
+
+func (v *verifier) orphaned(name string) bool {
+	return len(v.symbols) > 0 && !v.symbols[name]
+}
+
+func (v *verifier) checkSymbol(check *FileCheck, name string) {
+	if v.orphaned(name) {
+		check.Problems = append(check.Problems, Problem{Kind: Orphaned, Detail: fmt.Sprintf("%s is not in the symbols list", name)})
+	}
+}
+
+// resolve repairs or quarantines a file with problems, as the mode requires. payloads are the frames of its
+// valid records.
+func (v *verifier) resolve(check *FileCheck, fname string, data []byte, payloads [][]byte) {
+	if check.OK() || v.mode == ReportOnly {
+		return
+	}
+
+	if v.mode == Repair && check.repairable() && payloads != nil {
+		v.keep(check, fname, data)
+		if check.Err == nil {
+			check.Err = writeFrames(fname, check.Type, payloads)
+		}
+		check.Action = "repaired"
+		return
+	}
+
+	dest := filepath.Join(v.quarantine, check.File)
+	check.Err = os.MkdirAll(filepath.Dir(dest), 0755)
+	if check.Err == nil {
+		check.Err = os.Rename(fname, dest)
+	}
+	check.Action = "quarantined"
+}
+
+// keep saves the original data of a file about to be repaired in the quarantine directory.
+func (v *verifier) keep(check *FileCheck, fname string, data []byte) {
+	dest := filepath.Join(v.quarantine, check.File)
+	check.Err = os.MkdirAll(filepath.Dir(dest), 0755)
+	if check.Err == nil {
+		check.Err = os.WriteFile(dest, data, 0644)
+	}
+}
+
+// isTempFile returns true if the file name is that of a temporary file written by writeFileAtomic.
+func isTempFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, ".tmp-")
+}
+
+// salvageFrames returns the frames of a stored file upgraded to the current version, skipping frames that fail
+// their checksum or cannot be upgraded and stopping at a truncated frame, with the problems found.
+// An error is returned if the file's header is damaged or it holds other records.
+func salvageFrames(data []byte, rt RecordType) (frames [][]byte, problems []Problem, err error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	h, err := readHeader(r)
+	legacy := errors.Is(err, ErrLegacyFormat)
+	if err == nil {
+		err = h.check(rt)
+	}
+	if err != nil && !legacy {
+		return nil, nil, err
+	}
+
+	var read [][]byte
+	if legacy && rt.legacyStream() {
+		if read, err = readFrames(r, rt, true); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		for cnt := 1; ; cnt++ {
+			payload, err := readFrame(r, legacy)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				problems = append(problems, Problem{Kind: Corrupt, Detail: fmt.Sprintf("record %d: %v", cnt, err)})
+				if errors.Is(err, errChecksum) {
+					continue
+				}
+				break
+			}
+			read = append(read, payload)
+		}
+	}
+
+	for i, payload := range read {
+		upgraded, err := upgradeFrames(rt, h.Version, [][]byte{payload})
+		if err != nil {
+			problems = append(problems, Problem{Kind: Corrupt, Detail: fmt.Sprintf("record %d: %v", i+1, err)})
+			continue
+		}
+		frames = append(frames, upgraded[0])
+	}
+
+	return frames, problems, nil
+}
+
+// decodeFrames calls decode for each value in each frame, as decodeAll does. A frame that cannot be decoded is
+// corrupt, and the values decoded from it before the error are kept.
+func decodeFrames(frames [][]byte, decode func(dec *gob.Decoder) error) (problems []Problem) {
+	for i, payload := range frames {
+		if err := decodeAll(bytes.NewReader(payload), decode); err != nil {
+			problems = append(problems, Problem{Kind: Corrupt, Detail: fmt.Sprintf("record %d: %v", i+1, err)})
+		}
+	}
+
+	return problems
+}
+
+// checkRecords decodes and validates the records of a file of the symbol with the file name, for the day of
+// per-day files, adding to the problems found reading its frames. If there are problems, the frames of the
+// valid records are returned for a repair.
+func checkRecords(rt RecordType, name string, day time.Time, frames [][]byte, problems []Problem) (int, []Problem, [][]byte, error) {
+	switch rt {
+	case PriceRecords:
+		return checkPrices(frames, problems)
+	case ExpirationRecords:
+		return checkExpirations(frames, problems)
+	case ChainSnapshotRecords:
+		return checkChainSnapshots(name, frames, problems)
+	case OneMinSaleRecords:
+		return checkOneMinSales(day, frames, problems)
+	default:
+		return checkQuoteSnapshots(name, day, frames, problems)
+	}
+}
+
+// orderProblem describes a record with the time of an earlier record, or one before the previous record,
+// as AppendDailyPrices rejects them.
+func orderProblem(what string, t, prev time.Time, duplicate bool, format string) Problem {
+	if duplicate {
+		return Problem{Kind: Invalid, Detail: fmt.Sprintf("duplicate %s %s", what, t.Format(format))}
+	}
+
+	return Problem{Kind: Invalid, Detail: fmt.Sprintf("%s %s is out of order after %s", what, t.Format(format), prev.Format(format))}
+}
+
+// recordCheck describes the records of a type for checkKeyed.
+type recordCheck struct {
+	what    string // Name of a record in problems.
+	timeFmt string // Format of a record's time in problems.
+	ordered bool   // Records are stored in time order.
+
+	// decode decodes a value of a frame into its records.
+	decode func(dec *gob.Decoder) ([]interface{}, error)
+
+	// check validates a record and returns its time, or describes why it is invalid.
+	check func(record interface{}) (t time.Time, invalid string)
+
+	// encode encodes valid records, in time order, into the frames of a repaired file.
+	encode func(records []interface{}) ([][]byte, error)
+}
+
+// checkKeyed decodes and validates the records of a file, which are keyed by their times, adding to the problems
+// found reading its frames. If there are problems, the valid records are sorted, keeping the last of any with the
+// same time, and encoded for a repair.
+func checkKeyed(frames [][]byte, problems []Problem, rc recordCheck) (int, []Problem, [][]byte, error) {
+	var records []interface{}
+	problems = append(problems, decodeFrames(frames, func(dec *gob.Decoder) error {
+		decoded, err := rc.decode(dec)
+		if err != nil {
+			return err
+		}
+		records = append(records, decoded...)
+		return nil
+	})...)
+
+	byTime := make(map[int64]interface{}, len(records))
+	var prev time.Time
+	for _, record := range records {
+		t, invalid := rc.check(record)
+		if invalid != "" {
+			problems = append(problems, Problem{Kind: Invalid, Detail: invalid})
+			continue
+		}
+		if _, ok := byTime[t.UnixNano()]; ok || (rc.ordered && len(byTime) > 0 && !t.After(prev)) {
+			problems = append(problems, orderProblem(rc.what, t, prev, ok, rc.timeFmt))
+		}
+		byTime[t.UnixNano()] = record
+		prev = t
+	}
+
+	if len(problems) == 0 {
+		return len(byTime), nil, nil, nil
+	}
+
+	times := make([]int64, 0, len(byTime))
+	for t := range byTime {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	valid := make([]interface{}, 0, len(times))
+	for _, t := range times {
+		valid = append(valid, byTime[t])
+	}
+
+	payloads, err := rc.encode(valid)
+	if err != nil {
+		return 0, problems, nil, err
+	}
+
+	return len(valid), problems, payloads, nil
+}
+
+// encodeRecords encodes the records one after another in a single frame.
+func encodeRecords(records []interface{}) ([][]byte, error) {
+	var payload bytes.Buffer
+	enc := gob.NewEncoder(&payload)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+
+	return [][]byte{payload.Bytes()}, nil
+}
+
+// encodeRecordFrames encodes each record in a frame of its own.
+func encodeRecordFrames(records []interface{}) ([][]byte, error) {
+	payloads := make([][]byte, 0, len(records))
+	for _, record := range records {
+		var payload bytes.Buffer
+		if err := gob.NewEncoder(&payload).Encode(record); err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, payload.Bytes())
+	}
+
+	return payloads, nil
+}
+
+func checkPrices(frames [][]byte, problems []Problem) (int, []Problem, [][]byte, error) {
+	return checkKeyed(frames, problems, recordCheck{
+		what:    "price",
+		timeFmt: dayFileFmt,
+		ordered: true,
+		decode: func(dec *gob.Decoder) ([]interface{}, error) {
+			var price stock.DailyPriceGob
+			err := dec.Decode(&price)
+			return []interface{}{price}, err
+		},
+		check: func(record interface{}) (time.Time, string) {
+			price := record.(stock.DailyPriceGob)
+			date := priceDate(price)
+			if _, err := price.ToDailyPrice(); err != nil {
+				return date, fmt.Sprintf("price %s: %v", date.Format(dayFileFmt), err)
+			}
+			return date, ""
+		},
+		encode: encodeRecords,
+	})
+}
+
+func checkExpirations(frames [][]byte, problems []Problem) (int, []Problem, [][]byte, error) {
+	return checkKeyed(frames, problems, recordCheck{
+		what:    "expiration",
+		timeFmt: dayFileFmt,
+		ordered: true,
+		decode: func(dec *gob.Decoder) ([]interface{}, error) {
+			var exp option.ExpirationGob
+			err := dec.Decode(&exp)
+			return []interface{}{exp}, err
+		},
+		check: func(record interface{}) (time.Time, string) {
+			eg := record.(option.ExpirationGob)
+			exp, err := eg.ToExpiration()
+			if err != nil {
+				return eg.Date, fmt.Sprintf("expiration %s: %v", eg.Date.Format(dayFileFmt), err)
+			}
+			return exp.Date(), ""
+		},
+		encode: encodeRecords,
+	})
+}
+
+// checkChainSnapshots checks a symbol's option chain snapshots, which are appended in the order they were taken,
+// one to a frame.
+func checkChainSnapshots(name string, frames [][]byte, problems []Problem) (int, []Problem, [][]byte, error) {
+	const timeFmt = "2006-01-02 15:04:05"
+
+	return checkKeyed(frames, problems, recordCheck{
+		what:    "snapshot",
+		timeFmt: timeFmt,
+		ordered: true,
+		decode: func(dec *gob.Decoder) ([]interface{}, error) {
+			var snapshot option.ChainSnapshotGob
+			err := dec.Decode(&snapshot)
+			return []interface{}{snapshot}, err
+		},
+		check: func(record interface{}) (time.Time, string) {
+			snapshot := record.(option.ChainSnapshotGob)
+			taken := snapshot.Time.Format(timeFmt)
+			if _, err := snapshot.ToChainSnapshot(); err != nil {
+				return snapshot.Time, fmt.Sprintf("snapshot %s: %v", taken, err)
+			}
+			if stockFilename(snapshot.Symbol) != name {
+				return snapshot.Time, fmt.Sprintf("snapshot %s is of %s", taken, snapshot.Symbol)
+			}
+			return snapshot.Time, ""
+		},
+		encode: encodeRecordFrames,
+	})
+}
+
+// checkOneMinSales checks a day file of one minute sales. Chunks are in time order once decoded; a file holding
+// several chunks, as those of earlier versions may, keeps the sale of each minute from the latest chunk.
+func checkOneMinSales(day time.Time, frames [][]byte, problems []Problem) (int, []Problem, [][]byte, error) {
+	const timeFmt = "2006-01-02 15:04"
+
+	var valid []stock.OneMinSaleGob
+	for i, chunk := range frames {
+		sales, err := decodeMinuteChunk(chunk)
+		if err != nil {
+			problems = append(problems, Problem{Kind: Corrupt, Detail: fmt.Sprintf("record %d: %v", i+1, err)})
+			continue
+		}
+		if chunkDay := saleDay(sales[0]); !chunkDay.Equal(day) {
+			problems = append(problems, Problem{Kind: Invalid, Detail: fmt.Sprintf("record %d holds sales of %s", i+1, chunkDay.Format(dayFileFmt))})
+			continue
+		}
+
+		for _, sale := range sales {
+			if _, err := sale.ToOneMinSale(); err != nil {
+				problems = append(problems, Problem{Kind: Invalid, Detail: fmt.Sprintf("sale %s: %v", sale.StartTime().Format(timeFmt), err)})
+				continue
+			}
+			valid = append(valid, sale)
+		}
+	}
+
+	valid = sortSales(valid)
+	if len(problems) == 0 || len(valid) == 0 {
+		return len(valid), problems, nil, nil
+	}
+
+	chunk, err := encodeMinuteChunk(valid)
+	if err != nil {
+		return 0, problems, nil, err
+	}
+
+	return len(valid), problems, [][]byte{chunk}, nil
+}
+
+// checkQuoteSnapshots checks a day file of a symbol's quote snapshots, each frame holding the snapshots of a save.
+func checkQuoteSnapshots(name string, day time.Time, frames [][]byte, problems []Problem) (int, []Problem, [][]byte, error) {
+	const timeFmt = "2006-01-02 15:04:05.000"
+
+	return checkKeyed(frames, problems, recordCheck{
+		what:    "snapshot",
+		timeFmt: timeFmt,
+		decode: func(dec *gob.Decoder) ([]interface{}, error) {
+			var group []quote.SnapshotGob
+			err := dec.Decode(&group)
+			records := make([]interface{}, len(group))
+			for i, snapshot := range group {
+				records[i] = snapshot
+			}
+			return records, err
+		},
+		check: func(record interface{}) (time.Time, string) {
+			snapshot := record.(quote.SnapshotGob)
+			received := markets.MarketTime(snapshot.Received)
+			switch _, err := snapshot.ToSnapshot(); {
+			case err != nil:
+				return received, fmt.Sprintf("snapshot %s: %v", received.Format(timeFmt), err)
+			case stockFilename(snapshot.Quote.Symbol) != name:
+				return received, fmt.Sprintf("snapshot %s is of %s", received.Format(timeFmt), snapshot.Quote.Symbol)
+			case received.Format(dayFileFmt) != day.Format(dayFileFmt):
+				return received, fmt.Sprintf("snapshot %s was received on another day", received.Format(timeFmt))
+			}
+			return received, ""
+		},
+		// The snapshots of a repaired file are saved together.
+		encode: func(records []interface{}) ([][]byte, error) {
+			group := make([]quote.SnapshotGob, len(records))
+			for i, record := range records {
+				group[i] = record.(quote.SnapshotGob)
+			}
+
+			var payload bytes.Buffer
+			if err := gob.NewEncoder(&payload).Encode(group); err != nil {
+				return nil, err
+			}
+			return [][]byte{payload.Bytes()}, nil
+		},
+	})
+}
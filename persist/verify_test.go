@@ -0,0 +1,270 @@
+package persist
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"github.com/tsilvers/realtime-securities/markets/stock"
+)
+
+// testPrices returns valid daily prices for the given days of March 2024.
+func testPrices(days ...int) []stock.DailyPriceGob {
+	prices := make([]stock.DailyPriceGob, len(days))
+	for i, day := range days {
+		prices[i] = stock.DailyPriceGob{Year: 2024, Month: 3, Day: day,
+			Open: 100 + float64(day), Close: 101 + float64(day), High: 102 + float64(day), Low: 99 + float64(day), Volume: int64(1000 * day)}
+	}
+
+	return prices
+}
+
+// priceFrames returns a file of prices, each in a frame of its own.
+func priceFrames(t *testing.T, prices []stock.DailyPriceGob) [][]byte {
+	t.Helper()
+
+	frames := make([][]byte, len(prices))
+	for i, price := range prices {
+		var payload bytes.Buffer
+		if err := gob.NewEncoder(&payload).Encode(price); err != nil {
+			t.Fatal(err)
+		}
+		frames[i] = frame(payload.Bytes())
+	}
+
+	return frames
+}
+
+// writeStoreFile writes the data to the file, creating its directory.
+func writeStoreFile(t *testing.T, fname string, data []byte) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(fname), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fname, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// verifyFile verifies the store and returns the check of the file, failing if it was not checked.
+func verifyFile(t *testing.T, symbols []string, mode VerifyMode, fname string) FileCheck {
+	t.Helper()
+
+	checks, err := Verify(symbols, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, check := range checks {
+		if check.File == storePath(fname) {
+			if check.Err != nil {
+				t.Fatalf("%s: %v", check.File, check.Err)
+			}
+			return check
+		}
+	}
+
+	t.Fatalf("%s was not verified", storePath(fname))
+	return FileCheck{}
+}
+
+// assertProblems checks the kinds of the file's problems and that their details contain the given text.
+func assertProblems(t *testing.T, check FileCheck, kinds []ProblemKind, details []string) {
+	t.Helper()
+
+	if len(check.Problems) != len(kinds) {
+		t.Fatalf("%s has problems %v, want %d", check.File, check.Problems, len(kinds))
+	}
+	for i, p := range check.Problems {
+		if p.Kind != kinds[i] || !strings.Contains(p.Detail, details[i]) {
+			t.Errorf("%s problem %d = %q, want %s containing %q", check.File, i+1, p, kinds[i], details[i])
+		}
+	}
+}
+
+// assertPrices checks the stored prices of the symbol.
+func assertPrices(t *testing.T, symbol string, want []stock.DailyPriceGob) {
+	t.Helper()
+
+	prices, err := LoadPrices(symbol)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]stock.DailyPriceGob, len(prices))
+	for i, price := range prices {
+		got[i] = *price
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stored prices %v, want %v", got, want)
+	}
+}
+
+// quarantined returns the quarantined copy of the file, failing if there is none.
+func quarantined(t *testing.T, fname string) []byte {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(QuarantineDir(), "*", storePath(fname)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("%d quarantined copies of %s, want 1", len(matches), storePath(fname))
+	}
+
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	resetStore(t)
+
+	prices := testPrices(4, 5, 6)
+	frames := priceFrames(t, prices)
+	frames[1][len(frames[1])-1] ^= 0xff
+
+	fname := pricesDir() + "TEST"
+	data := append(header(PriceRecords), bytes.Join(frames, nil)...)
+	writeStoreFile(t, fname, data)
+
+	check := verifyFile(t, nil, ReportOnly, fname)
+	assertProblems(t, check, []ProblemKind{Corrupt}, []string{"record 2: " + ErrCorrupt.Error() + ": frame checksum mismatch"})
+	if check.Records != 2 || check.Action != "" {
+		t.Errorf("reported %d records and action %q, want 2 records unchanged", check.Records, check.Action)
+	}
+	if stored, _ := os.ReadFile(fname); !bytes.Equal(stored, data) {
+		t.Error("reporting changed the file")
+	}
+
+	check = verifyFile(t, nil, Repair, fname)
+	if check.Action != "repaired" {
+		t.Errorf("action %q, want repaired", check.Action)
+	}
+	assertPrices(t, "TEST", []stock.DailyPriceGob{prices[0], prices[2]})
+	if !bytes.Equal(quarantined(t, fname), data) {
+		t.Error("the quarantined copy differs from the damaged file")
+	}
+
+	if check = verifyFile(t, nil, ReportOnly, fname); !check.OK() || check.Records != 2 {
+		t.Errorf("repaired file has problems %v and %d records", check.Problems, check.Records)
+	}
+}
+
+func TestVerifyTruncated(t *testing.T) {
+	resetStore(t)
+
+	prices := testPrices(4, 5, 6)
+	fname := pricesDir() + "TEST"
+	data := append(header(PriceRecords), bytes.Join(priceFrames(t, prices), nil)...)
+	data = data[:len(data)-5]
+
+	for _, mode := range []VerifyMode{Quarantine, Repair} {
+		writeStoreFile(t, fname, data)
+
+		check := verifyFile(t, nil, mode, fname)
+		assertProblems(t, check, []ProblemKind{Corrupt}, []string{"record 3: " + ErrCorrupt.Error() + ": frame is truncated"})
+		if check.Records != 2 {
+			t.Errorf("%d records, want 2", check.Records)
+		}
+
+		switch mode {
+		case Quarantine:
+			if check.Action != "quarantined" {
+				t.Errorf("action %q, want quarantined", check.Action)
+			}
+			if _, err := os.Stat(fname); !os.IsNotExist(err) {
+				t.Errorf("quarantined file is still stored: %v", err)
+			}
+			if !bytes.Equal(quarantined(t, fname), data) {
+				t.Error("the quarantined file differs from the damaged file")
+			}
+			resetStore(t)
+		case Repair:
+			if check.Action != "repaired" {
+				t.Errorf("action %q, want repaired", check.Action)
+			}
+			assertPrices(t, "TEST", prices[:2])
+		}
+	}
+}
+
+// Repairs sort the records, keeping the last of any duplicates.
+func TestVerifyOrder(t *testing.T) {
+	resetStore(t)
+
+	prices := testPrices(4, 5, 6)
+	replacement := prices[1]
+	replacement.Close += 0.5
+	if err := SavePrices("TEST", []stock.DailyPriceGob{prices[1], prices[0], prices[2], replacement}); err != nil {
+		t.Fatal(err)
+	}
+
+	exps := []option.ExpirationGob{
+		{Date: time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC), Strikes: []float64{90, 100}},
+		{Date: time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC), Strikes: []float64{95, 105}},
+	}
+	if err := SaveExpirations("TEST", exps); err != nil {
+		t.Fatal(err)
+	}
+
+	check := verifyFile(t, nil, ReportOnly, optionsDir()+"TEST")
+	assertProblems(t, check, []ProblemKind{Invalid}, []string{"expiration 2024-03-08 is out of order after 2024-03-15"})
+
+	// Repairs both files.
+	check = verifyFile(t, nil, Repair, pricesDir()+"TEST")
+	assertProblems(t, check, []ProblemKind{Invalid, Invalid}, []string{
+		"price 2024-03-04 is out of order after 2024-03-05",
+		"duplicate price 2024-03-05",
+	})
+	if check.Records != 3 || check.Action != "repaired" {
+		t.Errorf("%d records and action %q, want 3 repaired", check.Records, check.Action)
+	}
+	assertPrices(t, "TEST", []stock.DailyPriceGob{prices[0], replacement, prices[2]})
+
+	stored, err := LoadExpirations("TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 || !reflect.DeepEqual(*stored[0], exps[1]) || !reflect.DeepEqual(*stored[1], exps[0]) {
+		t.Errorf("stored expirations %v, want them in date order", stored)
+	}
+}
+
+// Files of symbols that are not in the list are quarantined, even when repairing.
+func TestVerifyOrphaned(t *testing.T) {
+	resetStore(t)
+
+	prices := testPrices(4, 5)
+	for _, symbol := range []string{"MSFT", "TEST"} {
+		if err := SavePrices(symbol, prices); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := verifyFile(t, []string{"MSFT"}, ReportOnly, pricesDir()+"TEST")
+	assertProblems(t, check, []ProblemKind{Orphaned}, []string{"TEST is not in the symbols list"})
+
+	if check = verifyFile(t, []string{"MSFT"}, ReportOnly, pricesDir()+"MSFT"); !check.OK() {
+		t.Errorf("MSFT has problems %v", check.Problems)
+	}
+
+	check = verifyFile(t, []string{"MSFT"}, Repair, pricesDir()+"TEST")
+	if check.Action != "quarantined" {
+		t.Errorf("action %q, want quarantined", check.Action)
+	}
+	if _, err := os.Stat(pricesDir() + "TEST"); !os.IsNotExist(err) {
+		t.Errorf("orphaned file is still stored: %v", err)
+	}
+	quarantined(t, pricesDir()+"TEST")
+	assertPrices(t, "MSFT", prices)
+}
@@ -15,6 +15,7 @@ type Provider interface {
 	GetTimeSales(symbol string, start time.Time) []stock.OneMinSale
 	GetQuotes(symbols []string) []quote.Quote
 	GetOptionExpirations(symbol string) []option.Expiration
+	GetOptionChain(symbol string, expiration option.Expiration) (option.Expiration, error)
 	GetMarketStatus() markets.StatusType
 }
 
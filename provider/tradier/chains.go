@@ -0,0 +1,135 @@
+package tradier
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/tsilvers/realtime-securities/markets/option"
+	"time"
+)
+
+const chainsURLFmt = "markets/options/chains?symbol=%s&expiration=%s&greeks=true"
+
+type chainsJSON struct {
+	Options chainJSON
+}
+
+type chainJSON struct {
+	Option []optionJSON
+}
+
+// chainsJSONSingle is used if only one option is returned.
+type chainsJSONSingle struct {
+	Options chainJSONSingle
+}
+
+type chainJSONSingle struct {
+	Option optionJSON
+}
+
+type optionJSON struct {
+	Symbol       string
+	Underlying   string
+	Strike       json.Number
+	OptionType   string `json:"option_type"`
+	Expiration   string `json:"expiration_date"`
+	ContractSize int    `json:"contract_size"`
+	OpenInterest int    `json:"open_interest"`
+	Bid          json.Number
+	BidSize      int
+	Ask          json.Number
+	AskSize      int
+	Last         json.Number
+	Change       json.Number
+	ChangePct    json.Number `json:"change_percentage"`
+	Greeks       greeksJSON
+}
+
+type greeksJSON struct {
+	MidIV json.Number `json:"mid_iv"`
+}
+
+// GetOptionChain returns a copy of the expiration with the calls and puts loaded for each strike price.
+// An error is returned if the chain could not be retrieved or no options were loaded, so an expiration
+// without quotes is never taken for a complete chain. Options that cannot be loaded are reported and skipped.
+func (t *Tradier) GetOptionChain(symbol string, expiration option.Expiration) (option.Expiration, error) {
+	var response []byte
+	var err error
+
+	url := fmt.Sprintf(chainsURLFmt, symbol, expiration.ExpirationDateStr())
+	response, err = t.request(url)
+	if err != nil {
+		return expiration, fmt.Errorf("could not retrieve option chain for %s %s: %w", symbol, expiration.ExpirationDateStr(), err)
+	}
+
+	showResponseError := showResponseErrorFunc(url)
+
+	chainsResponse := &chainsJSON{}
+	if err := json.Unmarshal(response, chainsResponse); err != nil {
+		// If error on Unmarshal, check if one option was returned.
+		chainsResponseSingle := &chainsJSONSingle{}
+		if err := json.Unmarshal(response, chainsResponseSingle); err != nil {
+			return expiration, fmt.Errorf("could not read option chain for %s %s: %w", symbol, expiration.ExpirationDateStr(), err)
+		}
+		// Return single option in chainsJSON struct.
+		chainsResponse.Options.Option = append(chainsResponse.Options.Option, chainsResponseSingle.Options.Option)
+	}
+
+	data := chainsResponse.Options.Option
+	if len(data) == 0 {
+		return expiration, fmt.Errorf("no options in option chain for %s %s", symbol, expiration.ExpirationDateStr())
+	}
+
+	// Copy the strikes so the caller's expiration is not modified.
+	strikes := make([]float64, 0, len(expiration.Strikes()))
+	for _, strike := range expiration.Strikes() {
+		strikes = append(strikes, strike.Price())
+	}
+	chain, err := option.NewExpiration(expiration.Date(), strikes)
+	if err != nil {
+		return expiration, fmt.Errorf("option chain for %s %s: %w", symbol, expiration.ExpirationDateStr(), err)
+	}
+
+	var expDate time.Time
+	var strike float64
+	loaded := 0
+
+	for _, oJSON := range data {
+		if expDate, err = time.Parse("2006-01-02", oJSON.Expiration); err != nil {
+			showResponseError(err, fmt.Sprintf("Symbol: %s, Option: %s, Date: %s", symbol, oJSON.Symbol, oJSON.Expiration))
+			continue
+		}
+
+		if strike, err = oJSON.Strike.Float64(); err != nil {
+			showResponseError(err, fmt.Sprintf("Symbol: %s, Option: %s, Strike: %s", symbol, oJSON.Symbol, oJSON.Strike))
+			continue
+		}
+
+		o, err := option.NewOption(symbol, expDate, strike, oJSON.OptionType == "call", oJSON.ContractSize, oJSON.OpenInterest)
+		if err != nil {
+			showResponseError(err, fmt.Sprintf("Symbol: %s, Option: %s", symbol, oJSON.Symbol))
+			continue
+		}
+
+		// Invalid numeric values are left as the zero value.
+		o.Bid, _ = oJSON.Bid.Float64()
+		o.BidSize = oJSON.BidSize
+		o.Ask, _ = oJSON.Ask.Float64()
+		o.AskSize = oJSON.AskSize
+		o.Last, _ = oJSON.Last.Float64()
+		o.Change, _ = oJSON.Change.Float64()
+		o.ChangePct, _ = oJSON.ChangePct.Float64()
+		o.IV, _ = oJSON.Greeks.MidIV.Float64()
+
+		if err := chain.SetOption(o); err != nil {
+			showResponseError(err)
+			continue
+		}
+		loaded++
+	}
+
+	if loaded == 0 {
+		return expiration, fmt.Errorf("no valid options in option chain for %s %s", symbol, expiration.ExpirationDateStr())
+	}
+
+	return chain, nil
+}
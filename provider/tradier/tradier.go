@@ -7,13 +7,15 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/tsilvers/realtime-securities/config"
 )
 
 const (
 	tradierHost               = "api.tradier.com"
 	tradierURL                = "https://" + tradierHost + "/v1/"
 	tradierFormat             = "application/json"
-	tradierAuthFile           = "../../resources/provider-auth/tradier"
+	tradierAuthName           = "tradier" // Credentials file name.
 	tradierTimeoutSec         = 30
 	tradierRequestDelayMillis = 500 // Avoid rate limiting by keeping requests below 2 per second.
 
@@ -42,7 +44,7 @@ func init() {
 	}
 
 	// Get authorization token.
-	authToken, err := ioutil.ReadFile(tradierAuthFile)
+	authToken, err := ioutil.ReadFile(config.CredentialsFile(tradierAuthName))
 	if err != nil {
 		log.Fatalln("Could not retrieve Tradier authorization token:", err)
 	}